@@ -0,0 +1,179 @@
+// Package backup writes the database to a portable archive and restores it:
+// a gzipped tar with a manifest.json and one <table>.jsonl file per table,
+// one json object per row.
+package backup
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+)
+
+const (
+	// Format names the archive so restore can tell it from other tar files.
+	Format = "gator-backup"
+	// Version is the version of the archive layout, not of the schema.
+	Version = 1
+
+	manifestName = "manifest.json"
+	tableSuffix  = ".jsonl"
+)
+
+// Manifest describes the archive, it is its first file.
+type Manifest struct {
+	Format  string `json:"format"`
+	Version int    `json:"version"`
+	// SchemaVersion is the last migration applied to the database that was backed up.
+	SchemaVersion int64       `json:"schema_version"`
+	CreatedAt     time.Time   `json:"created_at"`
+	Tables        []TableInfo `json:"tables"`
+}
+
+// TableInfo is a table of the archive, in the order it is written and restored.
+type TableInfo struct {
+	Name    string   `json:"name"`
+	Rows    int64    `json:"rows"`
+	Columns []string `json:"columns"`
+}
+
+// Check returns an error when the archive can not be restored into a
+// database at schemaVersion.
+func (m Manifest) Check(schemaVersion int64) error {
+	if m.Format != Format {
+		return fmt.Errorf("not a gator backup")
+	}
+	if m.Version > Version {
+		return fmt.Errorf("backup format version %v is newer than this gator supports (%v), update gator", m.Version, Version)
+	}
+	if m.SchemaVersion > schemaVersion {
+		return fmt.Errorf("backup of schema version %v is newer than the database (version %v), migrate the database first", m.SchemaVersion, schemaVersion)
+	}
+	return nil
+}
+
+func (m Manifest) table(name string) (TableInfo, bool) {
+	for _, t := range m.Tables {
+		if t.Name == name {
+			return t, true
+		}
+	}
+	return TableInfo{}, false
+}
+
+// Writer writes an archive, the manifest first and then the tables in order.
+type Writer struct {
+	gz  *gzip.Writer
+	tar *tar.Writer
+}
+
+// NewWriter starts an archive with its manifest.
+func NewWriter(w io.Writer, m Manifest) (*Writer, error) {
+	m.Format, m.Version = Format, Version
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	gz := gzip.NewWriter(w)
+	out := &Writer{gz: gz, tar: tar.NewWriter(gz)}
+	if err := out.writeFile(manifestName, int64(len(data)), strings.NewReader(string(data))); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// WriteTable copies the rows of a table, json lines, into the archive. The
+// rows are spooled to a temporary file because tar needs their size first.
+func (w *Writer) WriteTable(name string, rows func(out io.Writer) error) error {
+	spool, err := os.CreateTemp("", "gator-backup-*.jsonl")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(spool.Name())
+	defer spool.Close()
+
+	if err := rows(spool); err != nil {
+		return err
+	}
+	size, err := spool.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return err
+	}
+	if _, err := spool.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	return w.writeFile(name+tableSuffix, size, spool)
+}
+
+func (w *Writer) writeFile(name string, size int64, content io.Reader) error {
+	err := w.tar.WriteHeader(&tar.Header{
+		Name:    name,
+		Mode:    0600,
+		Size:    size,
+		ModTime: time.Now(),
+	})
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(w.tar, content)
+	return err
+}
+
+// Close finishes the archive.
+func (w *Writer) Close() error {
+	if err := w.tar.Close(); err != nil {
+		return err
+	}
+	return w.gz.Close()
+}
+
+// Reader reads an archive written by Writer.
+type Reader struct {
+	Manifest Manifest
+	tar      *tar.Reader
+}
+
+// NewReader opens an archive and reads its manifest.
+func NewReader(r io.Reader) (*Reader, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("not a gator backup: %v", err)
+	}
+	out := &Reader{tar: tar.NewReader(gz)}
+	header, err := out.tar.Next()
+	if err != nil {
+		return nil, fmt.Errorf("not a gator backup: %v", err)
+	}
+	if header.Name != manifestName {
+		return nil, fmt.Errorf("not a gator backup: the first file is %v instead of %v", header.Name, manifestName)
+	}
+	if err := json.NewDecoder(out.tar).Decode(&out.Manifest); err != nil {
+		return nil, fmt.Errorf("invalid backup manifest: %v", err)
+	}
+	if out.Manifest.Format != Format {
+		return nil, errors.New("not a gator backup")
+	}
+	return out, nil
+}
+
+// Next returns the next table and its rows, io.EOF after the last one.
+func (r *Reader) Next() (TableInfo, io.Reader, error) {
+	header, err := r.tar.Next()
+	if err != nil {
+		return TableInfo{}, nil, err
+	}
+	name, ok := strings.CutSuffix(header.Name, tableSuffix)
+	if !ok {
+		return TableInfo{}, nil, fmt.Errorf("unexpected file %v in backup", header.Name)
+	}
+	table, ok := r.Manifest.table(name)
+	if !ok {
+		return TableInfo{}, nil, fmt.Errorf("table %v is not in the backup manifest", name)
+	}
+	return table, r.tar, nil
+}
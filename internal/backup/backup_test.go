@@ -0,0 +1,270 @@
+package backup
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/o0n1x/gator/internal/database"
+	"github.com/o0n1x/gator/internal/dbtest"
+)
+
+func TestArchiveRoundTrip(t *testing.T) {
+	manifest := Manifest{
+		SchemaVersion: 17,
+		CreatedAt:     time.Date(2024, 3, 1, 9, 30, 0, 0, time.UTC),
+		Tables: []TableInfo{
+			{Name: "users", Rows: 2, Columns: []string{"id", "name"}},
+			{Name: "feeds", Rows: 0, Columns: []string{"id", "url"}},
+		},
+	}
+	content := map[string]string{
+		"users": `{"id":"a","name":"alice"}` + "\n" + `{"id":"b","name":"bob"}` + "\n",
+		"feeds": "",
+	}
+
+	var b bytes.Buffer
+	w, err := NewWriter(&b, manifest)
+	if err != nil {
+		t.Fatalf("NewWriter Failed %v", err)
+	}
+	for _, table := range manifest.Tables {
+		err := w.WriteTable(table.Name, func(out io.Writer) error {
+			_, err := io.WriteString(out, content[table.Name])
+			return err
+		})
+		if err != nil {
+			t.Fatalf("WriteTable Failed %v", err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close Failed %v", err)
+	}
+
+	r, err := NewReader(&b)
+	if err != nil {
+		t.Fatalf("NewReader Failed %v", err)
+	}
+	manifest.Format, manifest.Version = Format, Version
+	if !reflect.DeepEqual(r.Manifest, manifest) {
+		t.Errorf("Manifest Mismatch wanted: %+v , got: %+v", manifest, r.Manifest)
+	}
+	for _, want := range manifest.Tables {
+		info, rows, err := r.Next()
+		if err != nil {
+			t.Fatalf("Next Failed %v", err)
+		}
+		data, _ := io.ReadAll(rows)
+		if info.Name != want.Name || string(data) != content[want.Name] {
+			t.Errorf("table Mismatch wanted: %v %q , got: %v %q", want.Name, content[want.Name], info.Name, data)
+		}
+	}
+	if _, _, err := r.Next(); !errors.Is(err, io.EOF) {
+		t.Errorf("Next Failed, wanted io.EOF after the last table, got %v", err)
+	}
+}
+
+// tarball builds a gzipped tar of files in order.
+func tarball(t *testing.T, files ...[2]string) *bytes.Buffer {
+	var b bytes.Buffer
+	gz := gzip.NewWriter(&b)
+	tw := tar.NewWriter(gz)
+	for _, f := range files {
+		if err := tw.WriteHeader(&tar.Header{Name: f[0], Mode: 0600, Size: int64(len(f[1]))}); err != nil {
+			t.Fatalf("WriteHeader Failed %v", err)
+		}
+		tw.Write([]byte(f[1]))
+	}
+	tw.Close()
+	gz.Close()
+	return &b
+}
+
+func TestNewReaderErrors(t *testing.T) {
+	cases := map[string]io.Reader{
+		"not gzip":         strings.NewReader("users.jsonl"),
+		"no manifest":      tarball(t, [2]string{"users.jsonl", "{}\n"}),
+		"other format":     tarball(t, [2]string{manifestName, `{"format":"other","version":1}`}),
+		"invalid manifest": tarball(t, [2]string{manifestName, `{"format":`}),
+	}
+	for name, input := range cases {
+		t.Run(name, func(t *testing.T) {
+			if _, err := NewReader(input); err == nil {
+				t.Errorf("NewReader Failed, wanted an error")
+			}
+		})
+	}
+
+	t.Run("table missing from manifest", func(t *testing.T) {
+		input := tarball(t, [2]string{manifestName, fmt.Sprintf(`{"format":%q,"version":1}`, Format)}, [2]string{"users.jsonl", "{}\n"})
+		r, err := NewReader(input)
+		if err != nil {
+			t.Fatalf("NewReader Failed %v", err)
+		}
+		if _, _, err := r.Next(); err == nil {
+			t.Errorf("Next Failed, wanted an error")
+		}
+	})
+}
+
+func TestManifestCheck(t *testing.T) {
+	cases := map[string]struct {
+		manifest Manifest
+		schema   int64
+		err      bool
+	}{
+		"same schema":        {manifest: Manifest{Format: Format, Version: Version, SchemaVersion: 17}, schema: 17},
+		"older schema":       {manifest: Manifest{Format: Format, Version: Version, SchemaVersion: 12}, schema: 17},
+		"newer schema":       {manifest: Manifest{Format: Format, Version: Version, SchemaVersion: 18}, schema: 17, err: true},
+		"newer format":       {manifest: Manifest{Format: Format, Version: Version + 1, SchemaVersion: 17}, schema: 17, err: true},
+		"not a gator backup": {manifest: Manifest{Format: "other", Version: Version}, schema: 17, err: true},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := tc.manifest.Check(tc.schema)
+			if (err != nil) != tc.err {
+				t.Errorf("Check Mismatch wanted error: %v , got: %v", tc.err, err)
+			}
+		})
+	}
+}
+
+func TestIDMap(t *testing.T) {
+	users, _ := tableByName("users")
+	follows, _ := tableByName("feed_follows")
+	reads, _ := tableByName("post_reads")
+	ids := idMap{}
+
+	// alice exists in the database under another id
+	ids.match(users, row(`{"id":"backup-alice","name":"alice"}`), row(`{"id":"db-alice"}`))
+	// bob was matched by his own id, nothing to change
+	ids.match(users, row(`{"id":"bob","name":"bob"}`), row(`{"id":"bob"}`))
+	// composite keys are never referenced
+	ids.match(reads, row(`{"user_id":"x","post_id":"y"}`), row(`{"user_id":"z","post_id":"y"}`))
+
+	follow := row(`{"id":"f","user_id":"backup-alice","feed_id":"feed","folder_id":null}`)
+	ids.remap(follows, follow)
+	want := row(`{"id":"f","user_id":"db-alice","feed_id":"feed","folder_id":null}`)
+	if !reflect.DeepEqual(follow, want) {
+		t.Errorf("remap Mismatch wanted: %s , got: %s", encode(want), encode(follow))
+	}
+	if len(ids) != 1 || len(ids["users"]) != 1 {
+		t.Errorf("ids Mismatch wanted only alice, got: %v", ids)
+	}
+}
+
+func row(s string) map[string]json.RawMessage {
+	var r map[string]json.RawMessage
+	json.Unmarshal([]byte(s), &r)
+	return r
+}
+
+func encode(r map[string]json.RawMessage) []byte {
+	data, _ := json.Marshal(r)
+	return data
+}
+
+func TestTablesOrder(t *testing.T) {
+	seen := map[string]bool{}
+	for _, table := range tables {
+		for column, target := range table.refs {
+			if !seen[target] {
+				t.Errorf("table order Failed, %v.%v references %v which is restored later", table.name, column, target)
+			}
+		}
+		seen[table.name] = true
+	}
+}
+
+func TestBackupAndRestore(t *testing.T) {
+	ctx := context.Background()
+	sources, targets := dbtest.Open(t), dbtest.Open(t)
+	for i := range sources {
+		t.Run(sources[i].Name, func(t *testing.T) {
+			src, dst := database.NewStore(sources[i].DB), database.NewStore(targets[i].DB)
+			now := sql.NullTime{Time: time.Date(2024, 5, 3, 10, 30, 15, 250000000, time.UTC), Valid: true}
+			name := func(s string) sql.NullString { return sql.NullString{String: s, Valid: true} }
+
+			alice, err := src.CreateUser(ctx, database.CreateUserParams{ID: uuid.New(), CreatedAt: now, UpdatedAt: now, Name: "alice"})
+			if err != nil {
+				t.Fatalf("CreateUser Failed %v", err)
+			}
+			feed, err := src.CreateFeed(ctx, database.CreateFeedParams{ID: uuid.New(), CreatedAt: now, UpdatedAt: now, Name: name("go"), Url: name("https://go.dev/blog/feed.atom"), UserID: uuid.NullUUID{UUID: alice.ID, Valid: true}, UrlKey: name("go.dev/blog/feed.atom"), Kind: "rss"})
+			if err != nil {
+				t.Fatalf("CreateFeed Failed %v", err)
+			}
+			_, err = src.CreateFeedFollow(ctx, database.CreateFeedFollowParams{ID: uuid.New(), CreatedAt: now, UpdatedAt: now, UserID: uuid.NullUUID{UUID: alice.ID, Valid: true}, FeedID: uuid.NullUUID{UUID: feed.ID, Valid: true}})
+			if err != nil {
+				t.Fatalf("CreateFeedFollow Failed %v", err)
+			}
+			post, err := src.CreatePost(ctx, database.CreatePostParams{ID: uuid.New(), CreatedAt: now, UpdatedAt: now, Title: name("Generics"), Url: name("https://go.dev/blog/generics"), PublishedAt: now, FeedID: uuid.NullUUID{UUID: feed.ID, Valid: true}})
+			if err != nil {
+				t.Fatalf("CreatePost Failed %v", err)
+			}
+			if err := src.StarPost(ctx, database.StarPostParams{UserID: alice.ID, PostID: post.ID, StarredAt: now.Time}); err != nil {
+				t.Fatalf("StarPost Failed %v", err)
+			}
+
+			// the target already has alice, under another id
+			existing, err := dst.CreateUser(ctx, database.CreateUserParams{ID: uuid.New(), Name: "alice"})
+			if err != nil {
+				t.Fatalf("CreateUser Failed %v", err)
+			}
+
+			var archive bytes.Buffer
+			if _, err := Backup(ctx, sources[i].DB, &archive); err != nil {
+				t.Fatalf("Backup Failed %v", err)
+			}
+			_, reports, err := Restore(ctx, targets[i].DB, bytes.NewReader(archive.Bytes()), Merge, false)
+			if err != nil {
+				t.Fatalf("Restore Failed %v", err)
+			}
+			counts := map[string][2]int{}
+			for _, report := range reports {
+				if report.Inserted+report.Updated+report.Skipped > 0 {
+					counts[report.Name] = [2]int{report.Inserted, report.Updated}
+				}
+			}
+			want := map[string][2]int{"users": {0, 1}, "feeds": {1, 0}, "feed_follows": {1, 0}, "posts": {1, 0}, "post_stars": {1, 0}}
+			if !reflect.DeepEqual(counts, want) {
+				t.Errorf("Restore Mismatch wanted: %v , got: %v", want, counts)
+			}
+
+			// merged into the alice of the target, her created_at filled from the backup
+			user, err := dst.GetUser(ctx, "alice")
+			if err != nil || user.ID != existing.ID || user.CreatedAt != now {
+				t.Errorf("GetUser Mismatch wanted: %v created at %v , got: %+v %v", existing.ID, now.Time, user, err)
+			}
+			starred, err := dst.GetStarredPosts(ctx, existing.ID)
+			if err != nil || len(starred) != 1 || starred[0].Post.ID != post.ID || starred[0].Post.PublishedAt != now || starred[0].StarredAt != now.Time {
+				t.Errorf("GetStarredPosts Mismatch wanted: %+v , got: %+v %v", post, starred, err)
+			}
+			follows, err := dst.GetFeedFollowsForUser(ctx, uuid.NullUUID{UUID: existing.ID, Valid: true})
+			if err != nil || len(follows) != 1 || follows[0].FeedID.UUID != feed.ID {
+				t.Errorf("GetFeedFollowsForUser Mismatch wanted: %v , got: %+v %v", feed.ID, follows, err)
+			}
+
+			// a second restore finds every row
+			_, reports, err = Restore(ctx, targets[i].DB, bytes.NewReader(archive.Bytes()), Skip, false)
+			if err != nil {
+				t.Fatalf("Restore Failed %v", err)
+			}
+			for _, report := range reports {
+				if report.Inserted != 0 || report.Updated != 0 {
+					t.Errorf("Restore %v Mismatch wanted every row skipped, got: %+v", report.Name, report)
+				}
+			}
+		})
+	}
+}
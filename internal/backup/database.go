@@ -0,0 +1,455 @@
+package backup
+
+import (
+	"bufio"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/o0n1x/gator/internal/database"
+	"github.com/o0n1x/gator/internal/migrate"
+)
+
+// table is a table of the backup and how its rows are matched on restore.
+type table struct {
+	name string
+	// key is the primary key.
+	key []string
+	// unique lists the other column sets that identify a row, a row matching
+	// one of them is the same row under another id.
+	unique [][]string
+	// refs maps the columns holding the id of another table to that table.
+	refs map[string]string
+}
+
+// tables are in restore order, every table comes after the tables it references.
+var tables = []table{
+	{name: "users", key: []string{"id"}, unique: [][]string{{"name"}}},
+	{name: "feeds", key: []string{"id"}, unique: [][]string{{"url_key"}, {"url"}}, refs: map[string]string{"user_id": "users"}},
+	{name: "feed_scrapers", key: []string{"feed_id"}, refs: map[string]string{"feed_id": "feeds"}},
+	{name: "feed_watches", key: []string{"feed_id"}, refs: map[string]string{"feed_id": "feeds"}},
+	{name: "page_snapshots", key: []string{"id"}, refs: map[string]string{"feed_id": "feeds"}},
+	{name: "folders", key: []string{"id"}, unique: [][]string{{"user_id", "name"}}, refs: map[string]string{"user_id": "users"}},
+	{name: "feed_follows", key: []string{"id"}, unique: [][]string{{"user_id", "feed_id"}}, refs: map[string]string{"user_id": "users", "feed_id": "feeds", "folder_id": "folders"}},
+	{name: "posts", key: []string{"id"}, unique: [][]string{{"url"}}, refs: map[string]string{"feed_id": "feeds"}},
+	{name: "post_reads", key: []string{"user_id", "post_id"}, refs: map[string]string{"user_id": "users", "post_id": "posts"}},
+	{name: "post_stars", key: []string{"user_id", "post_id"}, refs: map[string]string{"user_id": "users", "post_id": "posts"}},
+	{name: "read_later", key: []string{"user_id", "post_id"}, refs: map[string]string{"user_id": "users", "post_id": "posts"}},
+	{name: "saved_searches", key: []string{"id"}, unique: [][]string{{"user_id", "name"}}, refs: map[string]string{"user_id": "users"}},
+}
+
+func tableByName(name string) (table, bool) {
+	for _, t := range tables {
+		if t.name == name {
+			return t, true
+		}
+	}
+	return table{}, false
+}
+
+// Policy decides what restore does with a row that is already in the database.
+type Policy string
+
+const (
+	// Skip keeps the row in the database as it is.
+	Skip Policy = "skip"
+	// Overwrite replaces the row in the database with the row of the backup.
+	Overwrite Policy = "overwrite"
+	// Merge keeps the row in the database and fills its empty columns from the backup.
+	Merge Policy = "merge"
+)
+
+// Policies lists every policy, the default first.
+var Policies = []string{string(Skip), string(Overwrite), string(Merge)}
+
+// TableReport counts what restore did with the rows of a table.
+type TableReport struct {
+	Name     string
+	Inserted int
+	Updated  int
+	Skipped  int
+}
+
+// queryer is a connection or a transaction.
+type queryer interface {
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+}
+
+// SchemaVersion is the last migration applied to the database.
+func SchemaVersion(ctx context.Context, db queryer, dialect database.Dialect) (int64, error) {
+	return migrate.Current(ctx, db, dialect)
+}
+
+// column is a column of a table and its sql type.
+type column struct {
+	name string
+	typ  string
+}
+
+func names(cols []column) []string {
+	names := make([]string, len(cols))
+	for i, c := range cols {
+		names[i] = c.name
+	}
+	return names
+}
+
+// columns lists the columns of a table that can be written, generated
+// columns are left out. A table that does not exist has none.
+func columns(ctx context.Context, db queryer, dialect database.Dialect, name string) ([]column, error) {
+	query := `
+SELECT column_name, upper(data_type) FROM information_schema.columns
+WHERE table_schema = current_schema() AND table_name = $1 AND is_generated = 'NEVER'
+ORDER BY ordinal_position`
+	if dialect == database.SQLite {
+		// hidden columns are the generated ones
+		query = `SELECT name, upper(type) FROM pragma_table_xinfo($1) WHERE hidden = 0 ORDER BY cid`
+	}
+	rows, err := db.QueryContext(ctx, query, name)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var cols []column
+	for rows.Next() {
+		var c column
+		if err := rows.Scan(&c.name, &c.typ); err != nil {
+			return nil, err
+		}
+		cols = append(cols, c)
+	}
+	return cols, rows.Err()
+}
+
+// rowJSON is the sql of the columns of the row alias as a json object.
+func rowJSON(dialect database.Dialect, alias string, cols []column) string {
+	if dialect != database.SQLite {
+		return "row_to_json(" + alias + ")"
+	}
+	var fields []string
+	for _, c := range cols {
+		value := alias + "." + quoteIdent(c.name)
+		// sqlite booleans are integers, the archive has json booleans
+		if c.typ == "BOOLEAN" {
+			value = fmt.Sprintf("json(CASE WHEN %v IS NULL THEN 'null' WHEN %v THEN 'true' ELSE 'false' END)", value, value)
+		}
+		fields = append(fields, fmt.Sprintf("'%v', %v", c.name, value))
+	}
+	return "json_object(" + strings.Join(fields, ", ") + ")"
+}
+
+// jsonRecord is the sql of a row of the table t made from the json object $1,
+// with every column of cols.
+func jsonRecord(dialect database.Dialect, t string, cols []column) string {
+	if dialect != database.SQLite {
+		return fmt.Sprintf("json_populate_record(NULL::%v, $1::json)", quoteIdent(t))
+	}
+	var fields []string
+	for _, c := range cols {
+		value := fmt.Sprintf(`json_extract($1, '$."%v"')`, c.name)
+		// postgres writes times as 2006-01-02T15:04:05, sqlite compares them as text
+		if c.typ == "TIMESTAMP" {
+			value = "replace(" + value + ", 'T', ' ')"
+		}
+		fields = append(fields, value+" AS "+quoteIdent(c.name))
+	}
+	return "(SELECT " + strings.Join(fields, ", ") + ")"
+}
+
+// Backup writes every table of the database to w, from one snapshot.
+func Backup(ctx context.Context, db *sql.DB, w io.Writer) (Manifest, error) {
+	dialect := database.DialectOf(db)
+	tx, err := db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+	if err != nil {
+		return Manifest{}, err
+	}
+	defer tx.Rollback()
+
+	manifest := Manifest{CreatedAt: time.Now().UTC()}
+	manifest.SchemaVersion, err = SchemaVersion(ctx, tx, dialect)
+	if err != nil {
+		return Manifest{}, err
+	}
+	tableColumns := map[string][]column{}
+	for _, t := range tables {
+		cols, err := columns(ctx, tx, dialect, t.name)
+		if err != nil {
+			return Manifest{}, fmt.Errorf("error reading the columns of %v: %v", t.name, err)
+		}
+		if len(cols) == 0 {
+			continue
+		}
+		tableColumns[t.name] = cols
+		info := TableInfo{Name: t.name, Columns: names(cols)}
+		if err := tx.QueryRowContext(ctx, "SELECT count(*) FROM "+quoteIdent(t.name)).Scan(&info.Rows); err != nil {
+			return Manifest{}, fmt.Errorf("error counting %v: %v", t.name, err)
+		}
+		manifest.Tables = append(manifest.Tables, info)
+	}
+
+	out, err := NewWriter(w, manifest)
+	if err != nil {
+		return Manifest{}, err
+	}
+	for _, info := range manifest.Tables {
+		t, _ := tableByName(info.Name)
+		query := fmt.Sprintf("SELECT %v FROM (SELECT %v FROM %v ORDER BY %v) r",
+			rowJSON(dialect, "r", tableColumns[t.name]), quoteIdents(info.Columns), quoteIdent(t.name), quoteIdents(t.key))
+		err := out.WriteTable(info.Name, func(w io.Writer) error {
+			rows, err := tx.QueryContext(ctx, query)
+			if err != nil {
+				return err
+			}
+			defer rows.Close()
+			for rows.Next() {
+				var row []byte
+				if err := rows.Scan(&row); err != nil {
+					return err
+				}
+				if _, err := fmt.Fprintf(w, "%s\n", row); err != nil {
+					return err
+				}
+			}
+			return rows.Err()
+		})
+		if err != nil {
+			return Manifest{}, fmt.Errorf("error writing %v: %v", info.Name, err)
+		}
+	}
+	return manifest, out.Close()
+}
+
+// Restore writes the rows of an archive into the database in one
+// transaction. Rows are matched to the rows already there by primary key and
+// by their unique columns, a row matched under another id keeps the id of the
+// database and the rows referencing it are changed to that id. policy decides
+// what happens to matched rows. Nothing is written when dryRun is set.
+func Restore(ctx context.Context, db *sql.DB, r io.Reader, policy Policy, dryRun bool) (Manifest, []TableReport, error) {
+	archive, err := NewReader(r)
+	if err != nil {
+		return Manifest{}, nil, err
+	}
+	dialect := database.DialectOf(db)
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return Manifest{}, nil, err
+	}
+	defer tx.Rollback()
+
+	version, err := SchemaVersion(ctx, tx, dialect)
+	if err != nil {
+		return Manifest{}, nil, err
+	}
+	if err := archive.Manifest.Check(version); err != nil {
+		return Manifest{}, nil, err
+	}
+
+	ids := idMap{}
+	var reports []TableReport
+	for {
+		info, rows, err := archive.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return Manifest{}, nil, err
+		}
+		report, err := restoreTable(ctx, tx, dialect, info, rows, policy, ids)
+		if err != nil {
+			return Manifest{}, nil, fmt.Errorf("error restoring %v: %v", info.Name, err)
+		}
+		reports = append(reports, report)
+	}
+	if dryRun {
+		return archive.Manifest, reports, nil
+	}
+	return archive.Manifest, reports, tx.Commit()
+}
+
+func restoreTable(ctx context.Context, tx *sql.Tx, dialect database.Dialect, info TableInfo, rows io.Reader, policy Policy, ids idMap) (TableReport, error) {
+	report := TableReport{Name: info.Name}
+	t, ok := tableByName(info.Name)
+	if !ok {
+		return report, fmt.Errorf("unknown table")
+	}
+	known, err := columns(ctx, tx, dialect, t.name)
+	if err != nil {
+		return report, err
+	}
+	writable := map[string]bool{}
+	var keys []column
+	for _, c := range known {
+		writable[c.name] = true
+		if contains(t.key, c.name) {
+			keys = append(keys, c)
+		}
+	}
+	for _, column := range info.Columns {
+		if !writable[column] {
+			return report, fmt.Errorf("the database has no column %v, migrate the database first", column)
+		}
+	}
+
+	record := jsonRecord(dialect, t.name, known)
+	matchers := append([][]string{t.key}, t.unique...)
+	var updated []string
+	for _, column := range info.Columns {
+		if !contains(t.key, column) {
+			updated = append(updated, column)
+		}
+	}
+
+	scanner := bufio.NewScanner(rows)
+	scanner.Buffer(make([]byte, 64*1024), 64*1024*1024)
+	var count int64
+	for scanner.Scan() {
+		count++
+		var row map[string]json.RawMessage
+		if err := json.Unmarshal(scanner.Bytes(), &row); err != nil {
+			return report, fmt.Errorf("row %v: %v", count, err)
+		}
+		ids.remap(t, row)
+		data, err := json.Marshal(row)
+		if err != nil {
+			return report, err
+		}
+
+		// find the row in the database, by primary key first
+		var existing []byte
+		for _, columns := range matchers {
+			query := fmt.Sprintf("SELECT %v FROM (SELECT %v FROM %v t, %v r WHERE %v LIMIT 1) k",
+				rowJSON(dialect, "k", keys), qualified("t", t.key), quoteIdent(t.name), record, matchColumns(columns))
+			err = tx.QueryRowContext(ctx, query, data).Scan(&existing)
+			if err == nil {
+				break
+			}
+			if !errors.Is(err, sql.ErrNoRows) {
+				return report, fmt.Errorf("row %v: %v", count, err)
+			}
+		}
+
+		if existing == nil {
+			query := fmt.Sprintf("INSERT INTO %v (%v) SELECT %v FROM %v", quoteIdent(t.name), quoteIdents(info.Columns), quoteIdents(info.Columns), record)
+			if _, err := tx.ExecContext(ctx, query, data); err != nil {
+				return report, fmt.Errorf("row %v: %v", count, err)
+			}
+			report.Inserted++
+			continue
+		}
+
+		var key map[string]json.RawMessage
+		if err := json.Unmarshal(existing, &key); err != nil {
+			return report, err
+		}
+		ids.match(t, row, key)
+		if policy == Skip || len(updated) == 0 {
+			report.Skipped++
+			continue
+		}
+		for column, value := range key {
+			row[column] = value
+		}
+		data, err = json.Marshal(row)
+		if err != nil {
+			return report, err
+		}
+		var sets []string
+		for _, column := range updated {
+			value := "r." + quoteIdent(column)
+			if policy == Merge {
+				value = fmt.Sprintf("COALESCE(t.%v, r.%v)", quoteIdent(column), quoteIdent(column))
+			}
+			sets = append(sets, quoteIdent(column)+" = "+value)
+		}
+		query := fmt.Sprintf("UPDATE %v AS t SET %v FROM %v r WHERE %v",
+			quoteIdent(t.name), strings.Join(sets, ", "), record, matchColumns(t.key))
+		if _, err := tx.ExecContext(ctx, query, data); err != nil {
+			return report, fmt.Errorf("row %v: %v", count, err)
+		}
+		report.Updated++
+	}
+	if err := scanner.Err(); err != nil {
+		return report, err
+	}
+	if count != info.Rows {
+		return report, fmt.Errorf("the backup has %v rows instead of %v, it is incomplete", count, info.Rows)
+	}
+	return report, nil
+}
+
+// idMap maps the ids of the backup to the ids of the database, by table, for
+// the rows that were already in the database under another id.
+type idMap map[string]map[string]json.RawMessage
+
+// remap changes the references of row to the ids of the database.
+func (ids idMap) remap(t table, row map[string]json.RawMessage) {
+	for column, target := range t.refs {
+		value, ok := row[column]
+		if !ok {
+			continue
+		}
+		if id, ok := ids[target][string(value)]; ok {
+			row[column] = id
+		}
+	}
+}
+
+// match records that row is the row of the database with the primary key key.
+func (ids idMap) match(t table, row, key map[string]json.RawMessage) {
+	// only single column ids are referenced, composite keys are made of references
+	if len(t.key) != 1 {
+		return
+	}
+	column := t.key[0]
+	if string(row[column]) == string(key[column]) {
+		return
+	}
+	if ids[t.name] == nil {
+		ids[t.name] = map[string]json.RawMessage{}
+	}
+	ids[t.name][string(row[column])] = key[column]
+}
+
+func matchColumns(columns []string) string {
+	var conditions []string
+	for _, column := range columns {
+		conditions = append(conditions, fmt.Sprintf("t.%v = r.%v", quoteIdent(column), quoteIdent(column)))
+	}
+	return strings.Join(conditions, " AND ")
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func quoteIdent(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
+func qualified(prefix string, names []string) string {
+	quoted := make([]string, len(names))
+	for i, name := range names {
+		quoted[i] = prefix + "." + quoteIdent(name)
+	}
+	return strings.Join(quoted, ", ")
+}
+
+func quoteIdents(names []string) string {
+	quoted := make([]string, len(names))
+	for i, name := range names {
+		quoted[i] = quoteIdent(name)
+	}
+	return strings.Join(quoted, ", ")
+}
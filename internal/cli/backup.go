@@ -0,0 +1,84 @@
+package cli
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"text/tabwriter"
+
+	"github.com/o0n1x/gator/internal/backup"
+)
+
+// HandlerBackup writes every table to a compressed archive, - writes it to stdout.
+func HandlerBackup(s *State, cmd Command) error {
+	path := cmd.Args[0]
+	if path == "-" {
+		_, err := backup.Backup(context.Background(), s.Conn, os.Stdout)
+		return err
+	}
+
+	// the archive is written next to its path and renamed, a failed backup
+	// never replaces a good one
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".gator-backup-*")
+	if err != nil {
+		return fmt.Errorf("error creating backup: %v", err)
+	}
+	defer os.Remove(tmp.Name())
+	manifest, err := backup.Backup(context.Background(), s.Conn, tmp)
+	if err != nil {
+		tmp.Close()
+		return fmt.Errorf("error writing backup: %v", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("error writing backup: %v", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("error writing backup: %v", err)
+	}
+
+	var rows int64
+	for _, table := range manifest.Tables {
+		rows += table.Rows
+	}
+	fmt.Printf("backed up %v rows of %v tables (schema version %v) to %v\n", rows, len(manifest.Tables), manifest.SchemaVersion, path)
+	return nil
+}
+
+func restoreFlags(f *flag.FlagSet) {
+	choice(f, "policy", string(backup.Skip), "what to do with rows that are already in the database", backup.Policies...)
+	f.Bool("dry-run", false, "report what would be restored without changing anything")
+}
+
+// HandlerRestore writes the rows of a backup into the database, - reads stdin.
+func HandlerRestore(s *State, cmd Command) error {
+	var in io.Reader = os.Stdin
+	if path := cmd.Args[0]; path != "-" {
+		f, err := os.Open(path)
+		if err != nil {
+			return fmt.Errorf("error opening backup: %v", err)
+		}
+		defer f.Close()
+		in = f
+	}
+
+	dryRun := cmd.Bool("dry-run")
+	manifest, reports, err := backup.Restore(context.Background(), s.Conn, in, backup.Policy(cmd.String("policy")), dryRun)
+	if err != nil {
+		return fmt.Errorf("error restoring backup, nothing was changed: %v", err)
+	}
+
+	fmt.Printf("backup of %v, schema version %v\n\n", manifest.CreatedAt.Local().Format("2006-01-02 15:04"), manifest.SchemaVersion)
+	table := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(table, "TABLE\tINSERTED\tUPDATED\tSKIPPED")
+	for _, report := range reports {
+		fmt.Fprintf(table, "%v\t%v\t%v\t%v\n", report.Name, report.Inserted, report.Updated, report.Skipped)
+	}
+	table.Flush()
+	if dryRun {
+		fmt.Println("\ndry run, nothing was changed")
+	}
+	return nil
+}
@@ -0,0 +1,209 @@
+package cli
+
+import (
+	"context"
+	"database/sql"
+	"flag"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/google/uuid"
+	"github.com/o0n1x/gator/internal/database"
+	"github.com/o0n1x/gator/internal/output"
+)
+
+const (
+	browseLimit    = 2
+	browseMaxLimit = 100
+
+	groupByFeed = "feed"
+	groupByDay  = "day"
+)
+
+func browseFlags(f *flag.FlagSet) {
+	f.Bool("all", false, "include posts that were already read")
+	f.Bool("unread", true, "only show unread posts, --unread=false is the same as --all")
+	f.String("folder", "", "only show posts of feeds in this folder")
+	f.Bool("hidden", false, "include feeds hidden from the main timeline")
+	f.String("saved", "", "only show posts matching this saved search")
+	f.String("feed", "", "only show posts of this feed")
+	f.String("since", "", "only posts published since this time (date, 24h, 7d, today, yesterday)")
+	f.String("until", "", "only posts published before this time (date, 24h, 7d, today, yesterday)")
+	f.String("author", "", "only posts whose author contains this text")
+	f.String("keyword", "", "only posts whose title or description contains this text")
+	choice(f, "sort", "", "order of the posts", string(database.BrowseByPublished), string(database.BrowseByFetched), string(database.BrowseByFeed))
+	f.Bool("reverse", false, "reverse the order")
+	choice(f, "group-by", "", "print a header for every", groupByFeed, groupByDay)
+	f.String("after", "", "cursor printed at the end of the previous page")
+}
+
+func HandlerBrowse(s *State, cmd Command, user database.User) error {
+	params := database.BrowseParams{
+		UserID:        user.ID,
+		UnreadOnly:    cmd.Bool("unread") && !cmd.Bool("all"),
+		IncludeHidden: cmd.Bool("hidden"),
+		Author:        cmd.String("author"),
+		Keyword:       cmd.String("keyword"),
+		Sort:          database.BrowseSort(cmd.String("sort")),
+		Reverse:       cmd.Bool("reverse"),
+		After:         cmd.String("after"),
+		Limit:         browseLimit,
+	}
+
+	groupBy := cmd.String("group-by")
+	switch groupBy {
+	case groupByFeed:
+		if params.Sort != "" && params.Sort != database.BrowseByFeed {
+			return usageErrorf(cmd.Name, "--group-by feed needs --sort feed, not %v", params.Sort)
+		}
+		params.Sort = database.BrowseByFeed
+	case groupByDay:
+		if params.Sort == database.BrowseByFeed {
+			return usageErrorf(cmd.Name, "--group-by day needs --sort published or fetched")
+		}
+	}
+
+	if folderName := cmd.String("folder"); folderName != "" {
+		folder, err := folderByName(s, user, folderName)
+		if err != nil {
+			return err
+		}
+		params.FolderID = uuid.NullUUID{UUID: folder.ID, Valid: true}
+		// hidden feeds are only kept out of the main timeline
+		params.IncludeHidden = true
+	}
+	if savedName := cmd.String("saved"); savedName != "" {
+		saved, err := savedSearchByName(s, user, savedName)
+		if err != nil {
+			return err
+		}
+		params.Search = sql.NullString{String: saved.Query, Valid: true}
+		params.IncludeHidden = true
+	}
+	if feedURL := cmd.String("feed"); feedURL != "" {
+		feed, err := feedByURL(s, feedURL)
+		if err != nil {
+			return err
+		}
+		params.FeedID = uuid.NullUUID{UUID: feed.ID, Valid: true}
+		params.IncludeHidden = true
+		// a single feed is listed in the default sort of its subscription
+		follow, err := s.DB.GetFeedFollow(context.Background(), database.GetFeedFollowParams{
+			UserID: uuid.NullUUID{UUID: user.ID, Valid: true},
+			FeedID: params.FeedID,
+		})
+		if err == nil && !cmd.IsSet("sort") && follow.DefaultSort == sortOldest {
+			params.Reverse = !params.Reverse
+		}
+	}
+	now := time.Now()
+	if sinceArg := cmd.String("since"); sinceArg != "" {
+		since, err := parseTimeArg(sinceArg, now)
+		if err != nil {
+			return err
+		}
+		params.Since = sql.NullTime{Time: since, Valid: true}
+	}
+	if untilArg := cmd.String("until"); untilArg != "" {
+		until, err := parseTimeArg(untilArg, now)
+		if err != nil {
+			return err
+		}
+		params.Until = sql.NullTime{Time: until, Valid: true}
+	}
+
+	if len(cmd.Args) > 0 {
+		n, err := strconv.Atoi(cmd.Args[0])
+		if err != nil || n < 1 || n > browseMaxLimit {
+			return usageErrorf(cmd.Name, "invalid limit %v, expected a number from 1 to %v", cmd.Args[0], browseMaxLimit)
+		}
+		params.Limit = int32(n)
+	}
+
+	posts, err := s.DB.Browse(context.Background(), params)
+	if err != nil {
+		return fmt.Errorf("error getting list of posts: %v", err)
+	}
+
+	if outputFormat(cmd) != output.Text {
+		var records []postRecord
+		for _, post := range posts {
+			record := newPostRecord(post.Post, post.FeedName, post.IsRead)
+			record.Cursor = database.BrowseCursor(params, post)
+			records = append(records, record)
+		}
+		return writeRecords(cmd, records)
+	}
+
+	group := ""
+	for _, post := range posts {
+		if groupBy != "" {
+			g := post.FeedName
+			if groupBy == groupByDay {
+				day := post.Post.PublishedAt.Time
+				if params.Sort == database.BrowseByFetched {
+					day = post.Post.CreatedAt.Time
+				}
+				g = day.Format("Monday, 2006-01-02")
+			}
+			if g != group {
+				group = g
+				fmt.Printf(color.YellowString("=== %v ===\n"), group)
+			}
+		}
+		fmt.Printf("   -------------------- \n")
+		fmt.Printf(color.YellowString("Title: %v"), "")
+		fmt.Printf(color.GreenString("%v"), post.Post.Title.String)
+		if post.IsRead {
+			fmt.Printf(" (read)")
+		}
+		fmt.Printf("\n")
+		fmt.Printf("	ID: %v\n", post.Post.ID)
+		fmt.Printf("	Feed: %v\n", post.FeedName)
+		if post.Post.Author.Valid {
+			fmt.Printf("	Author: %v\n", post.Post.Author.String)
+		}
+		fmt.Printf("	Published Date: %v\n", post.Post.PublishedAt.Time)
+		fmt.Printf("	URL: %v\n", post.Post.Url.String)
+		fmt.Printf("	Description: %v\n", post.Post.Description.String)
+
+	}
+	fmt.Printf("   --- End of Posts --- \n")
+	if len(posts) == int(params.Limit) {
+		fmt.Printf("next page: --after %v\n", database.BrowseCursor(params, posts[len(posts)-1]))
+	}
+	return nil
+}
+
+// parseTimeArg parses an absolute date or a time relative to now such as
+// 24h, 7d, 2w, today or yesterday.
+func parseTimeArg(value string, now time.Time) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	midnight := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	switch strings.ToLower(value) {
+	case "now":
+		return now.UTC(), nil
+	case "today":
+		return midnight.UTC(), nil
+	case "yesterday":
+		return midnight.AddDate(0, 0, -1).UTC(), nil
+	}
+
+	// time.ParseDuration stops at hours, days and weeks are handled here
+	for unit, days := range map[string]int{"d": 1, "w": 7} {
+		if n, err := strconv.Atoi(strings.TrimSuffix(value, unit)); err == nil && strings.HasSuffix(value, unit) && n >= 0 {
+			return now.AddDate(0, 0, -n*days).UTC(), nil
+		}
+	}
+	if d, err := time.ParseDuration(value); err == nil {
+		return now.Add(-d).UTC(), nil
+	}
+	t, err := parseDateArg(value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid time %v, expected YYYY-MM-DD, RFC3339, a duration like 24h or 7d, today or yesterday", value)
+	}
+	return t, nil
+}
@@ -3,10 +3,8 @@ package cli
 import (
 	"context"
 	"database/sql"
-	"errors"
+	"flag"
 	"fmt"
-	"os"
-	"strconv"
 	"time"
 
 	"github.com/google/uuid"
@@ -14,32 +12,54 @@ import (
 	"github.com/fatih/color"
 	"github.com/o0n1x/gator/internal/config"
 	"github.com/o0n1x/gator/internal/database"
+	"github.com/o0n1x/gator/internal/output"
 	"github.com/o0n1x/gator/internal/rss"
+	"github.com/o0n1x/gator/internal/source"
+	"github.com/o0n1x/gator/internal/urlnorm"
 )
 
 type State struct {
 	State *config.Config
-	DB    *database.Queries
+	DB    database.Store
+	// Conn is the connection DB runs on, for the commands that need their own
+	// sql. It is nil when DB is not a database, like the memory store of tests.
+	Conn *sql.DB
+	// schemaChecked is set once the schema passed checkSchema.
+	schemaChecked bool
+}
+
+// URLs returns the url normalizer configured for this state.
+func (s *State) URLs() *urlnorm.Normalizer {
+	return urlnorm.New(s.State.TrackingParams, s.State.ResolveRedirects)
+}
+
+// Fetchers returns the url scheme fetchers configured for this state.
+func (s *State) Fetchers() *source.Registry {
+	return source.NewRegistry(s.State.AllowFileFeeds, s.State.AllowExecFeeds)
 }
 
 type Command struct {
 	Name string
 	Args []string
+	// Flags holds the parsed flags of the command, read them with String, Bool, Int and Float.
+	Flags *flag.FlagSet
 }
 
 type Commands struct {
-	Commands map[string]func(*State, Command) error
+	Commands map[string]Spec
+	// SchemaCheck runs before every command that is not AnySchema, a command
+	// is refused when it returns an error.
+	SchemaCheck func(s *State) error
 }
 
 func HandlerLogin(s *State, cmd Command) error {
 	if len(cmd.Args) == 0 {
-		return errors.New("expected arg 'username' but was not found")
+		return usageErrorf(cmd.Name, "expected arg 'username' but was not found")
 	}
 	name := cmd.Args[0]
 	_, err := s.DB.GetUser(context.Background(), name)
 	if err != nil {
-		fmt.Printf("Error login, User %v does not exist\n", name)
-		os.Exit(1)
+		return fmt.Errorf("error login, user %v does not exist", name)
 	}
 	err = s.State.SetUser(name)
 	if err != nil {
@@ -51,49 +71,89 @@ func HandlerLogin(s *State, cmd Command) error {
 
 func HandlerRegister(s *State, cmd Command) error {
 	if len(cmd.Args) < 1 {
-		return errors.New("expected arg 'username' but was not found")
+		return usageErrorf(cmd.Name, "expected arg 'username' but was not found")
 	}
 	name := cmd.Args[0]
 	_, err := s.DB.GetUser(context.Background(), name)
 	if err == nil {
-		fmt.Printf("Error registering user: %v \n Error: User already Exists\n", name)
-		os.Exit(1)
+		return fmt.Errorf("error registering user %v: user already exists", name)
 	}
+	users, err := s.DB.GetUsers(context.Background())
+	if err != nil {
+		return fmt.Errorf("error registering user %v: %v", name, err)
+	}
+	// the first user sets gator up and is its admin
 	user, err := s.DB.CreateUser(context.Background(), database.CreateUserParams{
 		ID:        uuid.New(),
 		CreatedAt: sql.NullTime{Time: time.Now(), Valid: true},
 		UpdatedAt: sql.NullTime{Time: time.Now(), Valid: true},
 		Name:      name,
+		IsAdmin:   len(users) == 0,
 	})
 	if err != nil {
-		fmt.Printf("Error registering user: %v \n Error: %v", name, err)
-		os.Exit(1)
+		return fmt.Errorf("error registering user %v: %v", name, err)
 	}
 	s.State.SetUser(name)
 	fmt.Println("User Registered: ", user)
 	return nil
 }
 
+const (
+	feedKindRSS    = "rss"
+	feedKindScrape = "scrape"
+	feedKindWatch  = "watch"
+)
+
 func HandlerAddFeed(s *State, cmd Command, user database.User) error {
 	if len(cmd.Args) < 2 {
-		return errors.New("expected arg 'name' and 'url' but was not found")
+		return usageErrorf(cmd.Name, "expected arg 'name' and 'url' but was not found")
 	}
 	name := cmd.Args[0]
-	url := cmd.Args[1]
 
-	feed, err := s.DB.CreateFeed(context.Background(), database.CreateFeedParams{
+	var feed database.Feed
+	var feedfollow database.CreateFeedFollowRow
+	err := s.DB.InTx(context.Background(), func(tx database.Store) error {
+		var err error
+		feed, feedfollow, err = createFeed(s, tx, user, name, cmd.Args[1], feedKindRSS)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Created Feed:\nName: %v\nURL: %v\nUsername: %v\n", feed.Name.String, feed.Url.String, user.Name)
+	fmt.Printf("%v successfully followed %v\n", feedfollow.UserName, feedfollow.FeedName.String)
+	return nil
+}
+
+// createFeed normalizes rawURL, creates the feed of the given kind on db and
+// follows it for user, run it in a transaction to not leave a feed nobody follows.
+func createFeed(s *State, db database.Store, user database.User, name, rawURL, kind string) (database.Feed, database.CreateFeedFollowRow, error) {
+	url, err := s.URLs().Normalize(rawURL)
+	if err != nil {
+		return database.Feed{}, database.CreateFeedFollowRow{}, fmt.Errorf("invalid feed url %v: %v", rawURL, err)
+	}
+	urlKey, err := s.URLs().Key(url)
+	if err != nil {
+		return database.Feed{}, database.CreateFeedFollowRow{}, fmt.Errorf("invalid feed url %v: %v", rawURL, err)
+	}
+	if existing, err := db.GetFeedByURLKey(context.Background(), sql.NullString{String: urlKey, Valid: true}); err == nil {
+		return database.Feed{}, database.CreateFeedFollowRow{}, fmt.Errorf("feed already exists as %v (%v), use follow instead", existing.Name.String, existing.Url.String)
+	}
+
+	feed, err := db.CreateFeed(context.Background(), database.CreateFeedParams{
 		ID:        uuid.New(),
 		CreatedAt: sql.NullTime{Time: time.Now(), Valid: true},
 		UpdatedAt: sql.NullTime{Time: time.Now(), Valid: true},
 		Name:      sql.NullString{String: name, Valid: true},
 		Url:       sql.NullString{String: url, Valid: true},
 		UserID:    uuid.NullUUID{UUID: user.ID, Valid: true},
+		UrlKey:    sql.NullString{String: urlKey, Valid: true},
+		Kind:      kind,
 	})
 	if err != nil {
-		fmt.Printf("Error registering feed as User %v . Error: %v", name, err)
-		os.Exit(1)
+		return database.Feed{}, database.CreateFeedFollowRow{}, fmt.Errorf("error registering feed %v: %v", name, err)
 	}
-	feedfollow, err := s.DB.CreateFeedFollow(context.Background(), database.CreateFeedFollowParams{
+	feedfollow, err := db.CreateFeedFollow(context.Background(), database.CreateFeedFollowParams{
 		ID:        uuid.New(),
 		CreatedAt: sql.NullTime{Time: time.Now(), Valid: true},
 		UpdatedAt: sql.NullTime{Time: time.Now(), Valid: true},
@@ -101,19 +161,40 @@ func HandlerAddFeed(s *State, cmd Command, user database.User) error {
 		FeedID:    uuid.NullUUID{UUID: feed.ID, Valid: true},
 	})
 	if err != nil {
-		fmt.Printf("DB Error for following,\nError: %v\n", err)
-		os.Exit(1)
+		return database.Feed{}, database.CreateFeedFollowRow{}, fmt.Errorf("error following feed %v: %v", name, err)
 	}
-	fmt.Printf("Created Feed:\nName: %v\nURL: %v\nUsername: %v\n", feed.Name.String, feed.Url.String, user.Name)
-	fmt.Printf("%v successfully followed %v\n", feedfollow.UserName, feedfollow.FeedName.String)
-	return nil
+	return feed, feedfollow, nil
+}
+
+// feedByURL looks a feed up by any url that normalizes to the same key.
+func feedByURL(s *State, url string) (database.Feed, error) {
+	urlKey, err := s.URLs().Key(url)
+	if err != nil {
+		return database.Feed{}, fmt.Errorf("invalid feed url %v: %v", url, err)
+	}
+	feed, err := s.DB.GetFeedByURLKey(context.Background(), sql.NullString{String: urlKey, Valid: true})
+	if err != nil {
+		return database.Feed{}, fmt.Errorf("feed with url: %v does not exist", url)
+	}
+	return feed, nil
 }
 
 func HandlerReset(s *State, cmd Command) error {
-	err := s.DB.DeleteUsers(context.Background())
+	err := s.DB.InTx(context.Background(), func(tx database.Store) error {
+		if err := tx.DeleteUsers(context.Background()); err != nil {
+			return fmt.Errorf("error deleting users: %v", err)
+		}
+		if err := tx.DeleteFeeds(context.Background()); err != nil {
+			return fmt.Errorf("error deleting feeds: %v", err)
+		}
+		// starred and queued posts went with their users, nothing keeps the orphans anymore
+		if _, err := tx.PruneOrphanedPosts(context.Background()); err != nil {
+			return fmt.Errorf("error deleting posts: %v", err)
+		}
+		return nil
+	})
 	if err != nil {
-		fmt.Printf("Error deleting users: %v\n", err)
-		os.Exit(1)
+		return err
 	}
 	fmt.Println("All users deleted Successfully")
 	return nil
@@ -122,14 +203,28 @@ func HandlerReset(s *State, cmd Command) error {
 func HandlerUsers(s *State, cmd Command) error {
 	users, err := s.DB.GetUsers(context.Background())
 	if err != nil {
-		fmt.Printf("Error retrieving users: %v\n", err)
-		os.Exit(1)
+		return fmt.Errorf("error retrieving users: %v", err)
+	}
+	if outputFormat(cmd) != output.Text {
+		var records []userRecord
+		for _, user := range users {
+			records = append(records, userRecord{
+				Name:      user.Name,
+				Current:   user.Name == s.State.CurrentUserName,
+				Admin:     user.IsAdmin,
+				CreatedAt: timePtr(user.CreatedAt),
+			})
+		}
+		return writeRecords(cmd, records)
 	}
 	for _, user := range users {
 		fmt.Printf("* %v", user.Name)
 		if user.Name == s.State.CurrentUserName {
 			fmt.Printf(" (current)")
 		}
+		if user.IsAdmin {
+			fmt.Printf(" (admin)")
+		}
 		fmt.Printf("\n")
 	}
 	return nil
@@ -137,78 +232,177 @@ func HandlerUsers(s *State, cmd Command) error {
 
 func HandlerAgg(s *State, cmd Command) error {
 	if len(cmd.Args) < 1 {
-		return errors.New("expected arg 'time_between_reqs' but was not found")
+		return usageErrorf(cmd.Name, "expected arg 'time_between_reqs' but was not found")
 	}
 	duration_text := cmd.Args[0]
 
 	duration, err := time.ParseDuration(duration_text)
 	if err != nil {
-		fmt.Printf("Error parsing time %v: %v\n", duration_text, err)
-		os.Exit(1)
+		return usageErrorf(cmd.Name, "error parsing time %v: %v", duration_text, err)
 	}
 
 	//TODO not taking advantage of decoupled time checking for concurrent scraping
 	ticker := time.NewTicker(duration)
 	for ; ; <-ticker.C {
-		scrapeFeeds(s, duration)
+		// a feed that fails waits for its next turn, the others go on
+		if err := scrapeFeeds(s, duration); err != nil {
+			fmt.Printf("Error: %v\n", err)
+		}
 	}
 
 }
 
-func scrapeFeeds(s *State, duration time.Duration) {
+func scrapeFeeds(s *State, duration time.Duration) error {
 	nextfeed, err := s.DB.GetNextFeedToFetch(context.Background())
 	if err != nil {
-		fmt.Printf("Error fetching next feed. Maybe there is no feed to scrape.")
-		os.Exit(1)
+		return fmt.Errorf("error fetching next feed, maybe there is no feed to scrape")
 	}
 	if nextfeed.LastFetchedAt.Time.Add(duration).After(time.Now()) {
-		fmt.Printf("No Feed to fetch yet.")
-		os.Exit(1)
+		return fmt.Errorf("no feed to fetch yet")
 	}
-	err = s.DB.MarkFeedFetched(context.Background(), database.MarkFeedFetchedParams{
-		ID:        nextfeed.ID,
-		UpdatedAt: sql.NullTime{Time: time.Now(), Valid: true},
+
+	rss, err := refreshFeed(s, nextfeed, func(format string, a ...interface{}) {
+		fmt.Printf(format, a...)
 	})
 	if err != nil {
-		fmt.Printf("Error marking feed %v , Error: %v.\n", nextfeed.Url, err)
-		os.Exit(1)
+		return err
 	}
 
-	rss, err := rss.FetchFeed(context.Background(), nextfeed.Url.String)
-	fmt.Printf("Fetched from %v\n", nextfeed.Name)
+	//printing rss
+	fmt.Printf("Channel Title: %v\n", rss.Channel.Title)
+	//fmt.Printf("Channel Description:\n%v\n",rss.Channel.Description)
+	fmt.Printf("number of feeds fetched: %v\n", len(rss.Channel.Item))
+	return nil
+}
+
+// refreshFeed fetches feed now and saves its new posts, logf reports the
+// posts that were skipped and the notifications of followers. The posts,
+// the site url and the fetch time are saved in one transaction. A feed that
+// failed is marked as fetched on its own, it goes to the back of the queue
+// instead of being picked again before every other feed.
+func refreshFeed(s *State, nextfeed database.Feed, logf func(format string, a ...interface{})) (*rss.RSSFeed, error) {
+	rss, snapshot, err := fetchFeed(context.Background(), s, nextfeed)
+	logf("Fetched from %v\n", nextfeed.Name.String)
+	if err != nil {
+		markAttempted(s, nextfeed, logf)
+		return nil, fmt.Errorf("error retrieving RSS feed: %v", err)
+	}
+	items := feedPosts(s, nextfeed, rss, logf)
+
+	var created []database.Post
+	err = s.DB.InTx(context.Background(), func(tx database.Store) error {
+		created = nil
+		if link := rss.Channel.Link; link != "" && link != nextfeed.SiteUrl.String {
+			err := tx.SetFeedSiteURL(context.Background(), database.SetFeedSiteURLParams{
+				ID:        nextfeed.ID,
+				SiteUrl:   sql.NullString{String: link, Valid: true},
+				UpdatedAt: sql.NullTime{Time: time.Now(), Valid: true},
+			})
+			if err != nil {
+				return fmt.Errorf("error saving the site url of %v: %v", nextfeed.Name.String, err)
+			}
+		}
+		if snapshot != nil {
+			if _, err := tx.CreatePageSnapshot(context.Background(), *snapshot); err != nil {
+				return fmt.Errorf("error saving the snapshot of %v: %v", nextfeed.Name.String, err)
+			}
+		}
+		for _, item := range items {
+			post, err := tx.CreatePost(context.Background(), item)
+			if err == sql.ErrNoRows {
+				// saved by an earlier fetch
+				continue
+			}
+			if err != nil {
+				return fmt.Errorf("error saving post %v: %v", item.Url.String, err)
+			}
+			created = append(created, post)
+		}
+		err := tx.MarkFeedFetched(context.Background(), database.MarkFeedFetchedParams{
+			ID:        nextfeed.ID,
+			UpdatedAt: sql.NullTime{Time: time.Now(), Valid: true},
+		})
+		if err != nil {
+			return fmt.Errorf("error marking feed %v: %v", nextfeed.Url.String, err)
+		}
+		return nil
+	})
 	if err != nil {
-		fmt.Printf("Error retrieving RSS feed: %v\n", err)
-		os.Exit(1)
+		markAttempted(s, nextfeed, logf)
+		return nil, err
 	}
 
-	for _, rssitem := range rss.Channel.Item {
-		pubdate, err := parsePublishedAt(rssitem.PubDate)
+	fullText, err := s.DB.FeedWantsFullText(context.Background(), uuid.NullUUID{UUID: nextfeed.ID, Valid: true})
+	if err != nil {
+		logf("Silenced Error couldnt check full content subscriptions: %v\n", err)
+	}
+	notify, err := s.DB.GetNotifyFollowers(context.Background(), uuid.NullUUID{UUID: nextfeed.ID, Valid: true})
+	if err != nil {
+		logf("Silenced Error couldnt list notified followers: %v\n", err)
+	}
+	for _, post := range created {
+		if fullText {
+			err = fetchFullContent(s, post)
+			if err != nil {
+				logf("Silenced Error couldnt fetch full content of %v: %v\n", post.Url.String, err)
+			}
+		}
+		for _, follower := range notify {
+			logf(color.MagentaString("[new post for %v]")+" %v: %v\n", follower.UserName, follower.FeedName, post.Title.String)
+		}
+	}
+	return rss, nil
+}
+
+// markAttempted marks a feed that failed as fetched, outside the
+// transaction of its posts.
+func markAttempted(s *State, feed database.Feed, logf func(format string, a ...interface{})) {
+	err := s.DB.MarkFeedFetched(context.Background(), database.MarkFeedFetchedParams{
+		ID:        feed.ID,
+		UpdatedAt: sql.NullTime{Time: time.Now(), Valid: true},
+	})
+	if err != nil {
+		logf("Silenced Error couldnt mark the failed feed %v: %v\n", feed.Url.String, err)
+	}
+}
 
+// feedPosts turns the items of a fetched feed into posts, the ones with an
+// invalid link or publish date are skipped.
+func feedPosts(s *State, feed database.Feed, rss *rss.RSSFeed, logf func(format string, a ...interface{})) []database.CreatePostParams {
+	urls := s.URLs()
+	var posts []database.CreatePostParams
+	for _, rssitem := range rss.Channel.Item {
+		link := rssitem.Link
+		var err error
+		// watch posts link to a snapshot fragment of the page, normalizing would merge them
+		if feed.Kind != feedKindWatch {
+			link, err = urls.Canonical(context.Background(), rssitem.Link)
+			if err != nil {
+				logf("Skipping item %v with invalid link %v: %v\n", rssitem.Title, rssitem.Link, err)
+				continue
+			}
+		}
+		pubdate := time.Now().UTC()
+		if rssitem.PubDate != "" {
+			pubdate, err = parsePublishedAt(rssitem.PubDate)
+		}
 		if err != nil {
-			fmt.Printf("Error parsing time %v: %v\n", rssitem.PubDate, err)
-			os.Exit(1)
+			logf("Skipping item %v with invalid publish date %v: %v\n", rssitem.Title, rssitem.PubDate, err)
+			continue
 		}
-		_, err = s.DB.CreatePost(context.Background(), database.CreatePostParams{
+		posts = append(posts, database.CreatePostParams{
 			ID:          uuid.New(),
 			CreatedAt:   sql.NullTime{Time: time.Now(), Valid: true},
 			UpdatedAt:   sql.NullTime{Time: time.Now(), Valid: true},
 			Title:       sql.NullString{String: rssitem.Title, Valid: true},
-			Url:         sql.NullString{String: rssitem.Link, Valid: true},
+			Url:         sql.NullString{String: link, Valid: true},
 			Description: sql.NullString{String: rssitem.Description, Valid: true},
 			PublishedAt: sql.NullTime{Time: pubdate, Valid: true},
-			FeedID:      uuid.NullUUID{UUID: nextfeed.ID, Valid: true},
+			FeedID:      uuid.NullUUID{UUID: feed.ID, Valid: true},
+			Author:      sql.NullString{String: rssitem.Author, Valid: rssitem.Author != ""},
 		})
-		if err != nil {
-			fmt.Printf("Silenced Error couldnt insert post into dbms: %v\n", err)
-		}
-
 	}
-
-	//printing rss
-	fmt.Printf("Channel Title: %v\n", rss.Channel.Title)
-	//fmt.Printf("Channel Description:\n%v\n",rss.Channel.Description)
-	fmt.Printf("number of feeds fetched: %v\n", len(rss.Channel.Item))
-
+	return posts
 }
 
 var layouts = []string{
@@ -233,31 +427,73 @@ func parsePublishedAt(s string) (time.Time, error) {
 func HandlerFeeds(s *State, cmd Command) error {
 	feeds, err := s.DB.GetFeeds(context.Background())
 	if err != nil {
-		fmt.Printf("Error retrieving feeds: %v\n", err)
-		os.Exit(1)
+		return fmt.Errorf("error retrieving feeds: %v", err)
 	}
 
-	for _, feed := range feeds {
-		user, err := s.DB.GetUserByID(context.Background(), feed.UserID.UUID)
+	// feeds works logged out too, the current user's own names are shown when there is one
+	displayNames := map[uuid.UUID]string{}
+	if current, err := s.DB.GetUser(context.Background(), s.State.CurrentUserName); err == nil {
+		follows, err := s.DB.GetFeedFollowsForUser(context.Background(), uuid.NullUUID{UUID: current.ID, Valid: true})
 		if err != nil {
-			fmt.Printf("Error feed fetching, User with id %v does not exist\n", feed.UserID.UUID)
-			os.Exit(1)
+			return fmt.Errorf("error listing follows: %v", err)
+		}
+		for _, follow := range follows {
+			if follow.DisplayName.Valid {
+				displayNames[follow.FeedID.UUID] = follow.DisplayName.String
+			}
+		}
+	}
+
+	format := outputFormat(cmd)
+	var records []feedRecord
+	for _, feed := range feeds {
+		// the feeds of deleted users have no owner until an admin gives them one
+		var owner string
+		if feed.UserID.Valid {
+			user, err := s.DB.GetUserByID(context.Background(), feed.UserID.UUID)
+			if err != nil {
+				return fmt.Errorf("error fetching feeds, user with id %v does not exist", feed.UserID.UUID)
+			}
+			owner = user.Name
+		}
+		if format != output.Text {
+			records = append(records, feedRecord{
+				Name:          feed.Name.String,
+				URL:           feed.Url.String,
+				Kind:          feed.Kind,
+				User:          owner,
+				DisplayName:   displayNames[feed.ID],
+				LastFetchedAt: timePtr(feed.LastFetchedAt),
+			})
+			continue
+		}
+		if owner == "" {
+			owner = "-"
 		}
-		fmt.Printf("* Name: %v\n  URL: %v\n  User: %v\n", feed.Name.String, feed.Url.String, user.Name)
+		fmt.Printf("* Name: %v\n  URL: %v\n  User: %v\n", feed.Name.String, feed.Url.String, owner)
+		if name, ok := displayNames[feed.ID]; ok {
+			fmt.Printf("  Your Name: %v\n", name)
+		}
+	}
+	if format != output.Text {
+		return writeRecords(cmd, records)
 	}
 	return nil
 }
 
 func HandlerFollow(s *State, cmd Command, user database.User) error {
 	if len(cmd.Args) < 1 {
-		return errors.New("expected arg 'url' but was not found")
+		return usageErrorf(cmd.Name, "expected arg 'url' but was not found")
 	}
 	url := cmd.Args[0]
+	urlKey, err := s.URLs().Key(url)
+	if err != nil {
+		return fmt.Errorf("invalid feed url %v: %v", url, err)
+	}
 
-	feed, err := s.DB.GetFeedByURL(context.Background(), sql.NullString{String: url, Valid: true})
+	feed, err := s.DB.GetFeedByURLKey(context.Background(), sql.NullString{String: urlKey, Valid: true})
 	if err != nil {
-		fmt.Printf("Error following, feed with url: %v does not exist\n", url)
-		os.Exit(1)
+		return fmt.Errorf("error following, feed with url: %v does not exist", url)
 	}
 
 	feedfollow, err := s.DB.CreateFeedFollow(context.Background(), database.CreateFeedFollowParams{
@@ -268,8 +504,7 @@ func HandlerFollow(s *State, cmd Command, user database.User) error {
 		FeedID:    uuid.NullUUID{UUID: feed.ID, Valid: true},
 	})
 	if err != nil {
-		fmt.Printf("DB Error for following,\nError: %v\n", err)
-		os.Exit(1)
+		return fmt.Errorf("error following %v: %v", url, err)
 	}
 	fmt.Printf("%v successfully followed %v\n", feedfollow.UserName, feedfollow.FeedName.String)
 	return nil
@@ -278,14 +513,17 @@ func HandlerFollow(s *State, cmd Command, user database.User) error {
 
 func HandlerUnfollow(s *State, cmd Command, user database.User) error {
 	if len(cmd.Args) < 1 {
-		return errors.New("expected arg 'url' but was not found")
+		return usageErrorf(cmd.Name, "expected arg 'url' but was not found")
 	}
 	url := cmd.Args[0]
+	urlKey, err := s.URLs().Key(url)
+	if err != nil {
+		return fmt.Errorf("invalid feed url %v: %v", url, err)
+	}
 
-	feed, err := s.DB.GetFeedByURL(context.Background(), sql.NullString{String: url, Valid: true})
+	feed, err := s.DB.GetFeedByURLKey(context.Background(), sql.NullString{String: urlKey, Valid: true})
 	if err != nil {
-		fmt.Printf("Error unfollowing, feed with url: %v does not exist\n", url)
-		os.Exit(1)
+		return fmt.Errorf("error unfollowing, feed with url: %v does not exist", url)
 	}
 
 	err = s.DB.DeleteFeedFollow(context.Background(), database.DeleteFeedFollowParams{
@@ -293,71 +531,100 @@ func HandlerUnfollow(s *State, cmd Command, user database.User) error {
 		FeedID: uuid.NullUUID{UUID: feed.ID, Valid: true},
 	})
 	if err != nil {
-		fmt.Printf("DB Error for unfollowing,\nError: %v\n", err)
-		os.Exit(1)
+		return fmt.Errorf("error unfollowing %v: %v", url, err)
 	}
 	fmt.Printf("%v successfully unfollowed %v\n", user.Name, feed.Name)
 	return nil
 }
 
 func HandlerFollowing(s *State, cmd Command, user database.User) error {
-
 	feeds, err := s.DB.GetFeedFollowsForUser(context.Background(), uuid.NullUUID{UUID: user.ID, Valid: true})
 	if err != nil {
-		fmt.Printf("DB Error for list follows,\nError: %v\n", err)
-		os.Exit(1)
+		return fmt.Errorf("error listing follows: %v", err)
 	}
-	fmt.Printf("Feeds for the user %v:\n", user.Name)
-	for _, feed := range feeds {
-		fmt.Printf("* FeedName: %v \n  User: %v\n", feed.FeedName.String, feed.UserName)
+	counts, err := s.DB.GetUnreadCountsForUser(context.Background(), uuid.NullUUID{UUID: user.ID, Valid: true})
+	if err != nil {
+		return fmt.Errorf("error counting unread posts: %v", err)
+	}
+	unread := map[uuid.UUID]int64{}
+	for _, count := range counts {
+		unread[count.FeedID.UUID] = count.Unread
 	}
-	return nil
-}
 
-func HandlerBrowse(s *State, cmd Command, user database.User) error {
-	limit := 2
-	if len(cmd.Args) > 0 {
-		n, err := strconv.Atoi(cmd.Args[0])
-		if err != nil {
-			fmt.Printf("invalid limit %v: %v\nDefaulted to 2\n", cmd.Args[0], err)
-			n = 2
+	searches, err := s.DB.GetSavedSearchesForUser(context.Background(), user.ID)
+	if err != nil {
+		return fmt.Errorf("error listing saved searches: %v", err)
+	}
+
+	if outputFormat(cmd) != output.Text {
+		var records []followRecord
+		for _, feed := range feeds {
+			records = append(records, followRecord{
+				Type:        "feed",
+				Name:        feed.FeedName.String,
+				URL:         feed.FeedUrl.String,
+				DisplayName: feed.DisplayName.String,
+				Folder:      feed.FolderName.String,
+				Unread:      unread[feed.FeedID.UUID],
+				Hidden:      feed.Hidden,
+				Notify:      feed.Notify,
+				FullText:    feed.FullText,
+			})
 		}
-		if n > 20 {
-			fmt.Printf("invalid limit %v too large\nDefaulted to 2\n", cmd.Args[0])
-			n = 2
+		for _, search := range searches {
+			records = append(records, followRecord{
+				Type:   "search",
+				Name:   search.Name,
+				Query:  search.Query,
+				Unread: search.Unread,
+			})
 		}
-		limit = n
-	}
-	posts, err := s.DB.GetPostsForUser(context.Background(), database.GetPostsForUserParams{
-		ID:    user.ID,
-		Limit: int32(limit),
-	})
-	if err != nil {
-		fmt.Printf("DB Error getting list of posts for user,\nError: %v\n", err)
-		os.Exit(1)
+		return writeRecords(cmd, records)
 	}
 
-	for _, post := range posts {
-		fmt.Printf("   -------------------- \n")
-		fmt.Printf(color.YellowString("Title: %v"), "")
-		fmt.Printf(color.GreenString("%v\n"), post.Title.String)
-		fmt.Printf("	Published Date: %v\n", post.PublishedAt.Time)
-		fmt.Printf("	URL: %v\n", post.Url.String)
-		fmt.Printf("	Description: %v\n", post.Description.String)
-
+	fmt.Printf("Feeds for the user %v:\n", user.Name)
+	if !cmd.Bool("tree") {
+		for _, feed := range feeds {
+			fmt.Printf("* FeedName: %v (%v unread)%v\n  User: %v\n", followName(feed), unread[feed.FeedID.UUID], followFlags(feed), feed.UserName)
+			if feed.FolderName.Valid {
+				fmt.Printf("  Folder: %v\n", feed.FolderName.String)
+			}
+		}
+		for _, search := range searches {
+			fmt.Printf("* Search: %v (%v unread)\n  Query: %v\n", search.Name, search.Unread, search.Query)
+		}
+		return nil
 	}
-	fmt.Printf("   --- End of Posts --- \n")
-	return nil
-}
 
-func (c *Commands) Run(s *State, cmd Command) error {
-	err := c.Commands[cmd.Name](s, cmd)
+	folders, err := s.DB.GetFoldersForUser(context.Background(), user.ID)
 	if err != nil {
-		return err
+		return fmt.Errorf("error listing folders: %v", err)
+	}
+	byFolder := map[string][]database.GetFeedFollowsForUserRow{}
+	for _, feed := range feeds {
+		byFolder[feed.FolderName.String] = append(byFolder[feed.FolderName.String], feed)
+	}
+	printFolder := func(name string, feeds []database.GetFeedFollowsForUserRow) {
+		var total int64
+		for _, feed := range feeds {
+			total += unread[feed.FeedID.UUID]
+		}
+		fmt.Printf(color.YellowString("%v")+" (%v unread)\n", name, total)
+		for _, feed := range feeds {
+			fmt.Printf("  * %v (%v unread)%v\n", followName(feed), unread[feed.FeedID.UUID], followFlags(feed))
+		}
+	}
+	if len(byFolder[""]) > 0 {
+		printFolder("Unfiled", byFolder[""])
+	}
+	for _, folder := range folders {
+		printFolder(folder.Name, byFolder[folder.Name])
+	}
+	if len(searches) > 0 {
+		fmt.Println(color.YellowString("Saved Searches"))
+		for _, search := range searches {
+			fmt.Printf("  * %v (%v unread)\n", search.Name, search.Unread)
+		}
 	}
 	return nil
 }
-
-func (c *Commands) Register(name string, f func(*State, Command) error) {
-	c.Commands[name] = f
-}
@@ -0,0 +1,399 @@
+package cli
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/o0n1x/gator/internal/output"
+)
+
+const (
+	exitRuntime = 1
+	exitUsage   = 2
+)
+
+// Spec describes a command: how it is called, what it does and its handler.
+type Spec struct {
+	Name        string
+	Description string
+	Args        []Arg
+	// Flags defines the flags of the command on the flag set it is given.
+	Flags   func(f *flag.FlagSet)
+	Handler func(*State, Command) error
+	// Listing commands take --output and --fields, see output.go.
+	Listing bool
+	// Hidden commands work but are left out of help.
+	Hidden bool
+	// AnySchema commands run whatever the schema version of the database,
+	// the others are refused by Commands.SchemaCheck when it fails.
+	AnySchema bool
+}
+
+// Arg is a positional argument of a command.
+type Arg struct {
+	Name     string
+	Optional bool
+	// Variadic takes every remaining argument, it has to be the last Arg.
+	Variadic bool
+}
+
+// UsageError is returned when a command is called the wrong way, the
+// command itself never ran.
+type UsageError struct {
+	Command string
+	Err     error
+}
+
+func (e *UsageError) Error() string {
+	if e.Command == "" {
+		return e.Err.Error()
+	}
+	return fmt.Sprintf("%v, see gator %v --help", e.Err, e.Command)
+}
+
+func (e *UsageError) Unwrap() error {
+	return e.Err
+}
+
+func usageErrorf(command, format string, a ...interface{}) error {
+	return &UsageError{Command: command, Err: fmt.Errorf(format, a...)}
+}
+
+// ExitCode is the process exit code for an error returned by Run.
+func ExitCode(err error) int {
+	var usage *UsageError
+	if errors.As(err, &usage) {
+		return exitUsage
+	}
+	return exitRuntime
+}
+
+// Usage is the synopsis of the command, like "browse [flags] [limit]".
+func (spec Spec) Usage() string {
+	parts := []string{spec.Name}
+	if spec.Flags != nil || spec.Listing {
+		parts = append(parts, "[flags]")
+	}
+	for _, arg := range spec.Args {
+		name := "<" + arg.Name + ">"
+		if arg.Variadic {
+			name += "..."
+		}
+		if arg.Optional {
+			name = "[" + name + "]"
+		}
+		parts = append(parts, name)
+	}
+	return strings.Join(parts, " ")
+}
+
+func (spec Spec) flagSet() *flag.FlagSet {
+	flags := flag.NewFlagSet(spec.Name, flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+	if spec.Flags != nil {
+		spec.Flags(flags)
+	}
+	if spec.Listing {
+		outputFlags(flags)
+	}
+	return flags
+}
+
+// parse splits args into flags and positional arguments, flags may come
+// before, between or after the arguments until a "--".
+func (spec Spec) parse(args []string) (Command, error) {
+	flags := spec.flagSet()
+	var positional []string
+	for {
+		err := flags.Parse(args)
+		if err != nil {
+			return Command{}, err
+		}
+		rest := flags.Args()
+		consumed := len(args) - len(rest)
+		if consumed > 0 && args[consumed-1] == "--" {
+			positional = append(positional, rest...)
+			break
+		}
+		if len(rest) == 0 {
+			break
+		}
+		positional = append(positional, rest[0])
+		args = rest[1:]
+	}
+
+	var required []string
+	max := 0
+	for _, arg := range spec.Args {
+		if !arg.Optional {
+			required = append(required, arg.Name)
+		}
+		max++
+		if arg.Variadic {
+			max = -1
+			break
+		}
+	}
+	if len(positional) < len(required) {
+		return Command{}, fmt.Errorf("expected arg '%v' but was not found", required[len(positional)])
+	}
+	if max >= 0 && len(positional) > max {
+		return Command{}, fmt.Errorf("unexpected arg '%v'", positional[max])
+	}
+	return Command{Name: spec.Name, Args: positional, Flags: flags}, nil
+}
+
+// Register adds a command, registering a name twice replaces the command.
+func (c *Commands) Register(spec Spec) {
+	if c.Commands == nil {
+		c.Commands = map[string]Spec{}
+	}
+	c.Commands[spec.Name] = spec
+}
+
+func (c *Commands) Run(s *State, cmd Command) error {
+	if cmd.Name == "" {
+		c.PrintHelp(os.Stdout)
+		return &UsageError{Err: errors.New("Invalid input No arguments")}
+	}
+	if strings.HasPrefix(cmd.Name, "-") {
+		moved, err := moveGlobalFlags(cmd)
+		if errors.Is(err, flag.ErrHelp) {
+			c.PrintHelp(os.Stdout)
+			return nil
+		}
+		if err != nil {
+			return &UsageError{Err: err}
+		}
+		cmd = moved
+	}
+	spec, ok := c.Commands[cmd.Name]
+	if !ok {
+		if suggestion := c.Suggest(cmd.Name); suggestion != "" {
+			return &UsageError{Err: fmt.Errorf("unknown command %v, did you mean %v?", cmd.Name, suggestion)}
+		}
+		return &UsageError{Err: fmt.Errorf("unknown command %v, see gator help", cmd.Name)}
+	}
+
+	if name, ok := outputFlagIn(cmd.Args); ok && !spec.Listing {
+		return usageErrorf(spec.Name, "%v lists nothing, --%v only applies to %v", spec.Name, name, strings.Join(c.Listings(), ", "))
+	}
+	parsed, err := spec.parse(cmd.Args)
+	if errors.Is(err, flag.ErrHelp) {
+		spec.PrintHelp(os.Stdout)
+		return nil
+	}
+	if err != nil {
+		return &UsageError{Command: spec.Name, Err: err}
+	}
+	if spec.Listing && parsed.IsSet("fields") && output.Format(parsed.String("output")) == output.Text {
+		return usageErrorf(spec.Name, "--fields picks the fields of the other formats, add --output with one of %v", strings.Join(output.Formats[1:], ", "))
+	}
+	if c.SchemaCheck != nil && !spec.AnySchema {
+		if err := c.SchemaCheck(s); err != nil {
+			return err
+		}
+	}
+	return spec.Handler(s, parsed)
+}
+
+// moveGlobalFlags moves the flags given before the command name, like in
+// gator --output json feeds, to the args of the command.
+func moveGlobalFlags(cmd Command) (Command, error) {
+	args := append([]string{cmd.Name}, cmd.Args...)
+	flags := flag.NewFlagSet("gator", flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+	outputFlags(flags)
+	if err := flags.Parse(args); err != nil {
+		return Command{}, err
+	}
+	rest := flags.Args()
+	if len(rest) == 0 {
+		return Command{}, errors.New("expected a command after the flags, see gator help")
+	}
+	global := args[:len(args)-len(rest)]
+	if n := len(global); global[n-1] == "--" {
+		global = global[:n-1]
+	}
+	return Command{Name: rest[0], Args: append(slices.Clone(global), rest[1:]...)}, nil
+}
+
+// outputFlagIn returns the name of the first --output or --fields flag of
+// args, the flags of listing commands.
+func outputFlagIn(args []string) (string, bool) {
+	for _, arg := range args {
+		if arg == "--" {
+			break
+		}
+		if !strings.HasPrefix(arg, "-") {
+			continue
+		}
+		name, _, _ := strings.Cut(strings.TrimLeft(arg, "-"), "=")
+		if name == "output" || name == "fields" {
+			return name, true
+		}
+	}
+	return "", false
+}
+
+// Listings lists the visible commands taking --output and --fields.
+func (c *Commands) Listings() []string {
+	var names []string
+	for _, name := range c.Names() {
+		if c.Commands[name].Listing {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// Names lists the visible commands in alphabetical order.
+func (c *Commands) Names() []string {
+	var names []string
+	for name, spec := range c.Commands {
+		if !spec.Hidden {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// PrintHelp prints the list of commands.
+func (c *Commands) PrintHelp(w io.Writer) {
+	fmt.Fprintf(w, "usage: gator <command> [flags] [args]\n\ncommands:\n")
+	for _, name := range c.Names() {
+		fmt.Fprintf(w, "  %-14v %v\n", name, c.Commands[name].Description)
+	}
+	fmt.Fprintf(w, "\nrun gator <command> --help for the flags and arguments of a command\n")
+}
+
+// PrintHelp prints the usage, description and flags of the command.
+func (spec Spec) PrintHelp(w io.Writer) {
+	fmt.Fprintf(w, "usage: gator %v\n\n%v\n", spec.Usage(), spec.Description)
+	flags := spec.flagSet()
+	if spec.Flags == nil && !spec.Listing {
+		return
+	}
+	fmt.Fprintf(w, "\nflags:\n")
+	flags.SetOutput(w)
+	flags.PrintDefaults()
+}
+
+// Suggest returns the command closest to a mistyped name, or "" when none is close.
+func (c *Commands) Suggest(name string) string {
+	best, bestDistance := "", len(name)/2+1
+	for _, candidate := range c.Names() {
+		if strings.HasPrefix(candidate, name) && len(name) >= 2 {
+			return candidate
+		}
+		if d := levenshtein(name, candidate); d < bestDistance {
+			best, bestDistance = candidate, d
+		}
+	}
+	return best
+}
+
+// levenshtein is the number of single character edits that turn a into b.
+func levenshtein(a, b string) int {
+	prev := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur := make([]int, len(b)+1)
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev = cur
+	}
+	return prev[len(b)]
+}
+
+// String returns the value of a string flag of the command.
+func (cmd Command) String(name string) string {
+	value, _ := cmd.flag(name).(string)
+	return value
+}
+
+// Bool returns the value of a bool flag of the command.
+func (cmd Command) Bool(name string) bool {
+	value, _ := cmd.flag(name).(bool)
+	return value
+}
+
+// Int returns the value of an int flag of the command.
+func (cmd Command) Int(name string) int {
+	value, _ := cmd.flag(name).(int)
+	return value
+}
+
+// Float returns the value of a float flag of the command.
+func (cmd Command) Float(name string) float64 {
+	value, _ := cmd.flag(name).(float64)
+	return value
+}
+
+// IsSet reports whether a flag was given on the command line.
+func (cmd Command) IsSet(name string) bool {
+	set := false
+	if cmd.Flags != nil {
+		cmd.Flags.Visit(func(f *flag.Flag) {
+			if f.Name == name {
+				set = true
+			}
+		})
+	}
+	return set
+}
+
+func (cmd Command) flag(name string) interface{} {
+	if cmd.Flags == nil {
+		return nil
+	}
+	f := cmd.Flags.Lookup(name)
+	if f == nil {
+		return nil
+	}
+	getter, ok := f.Value.(flag.Getter)
+	if !ok {
+		return nil
+	}
+	return getter.Get()
+}
+
+// choiceFlag is a string flag limited to a set of values.
+type choiceFlag struct {
+	value   string
+	choices []string
+}
+
+func (c *choiceFlag) String() string   { return c.value }
+func (c *choiceFlag) Get() interface{} { return c.value }
+
+func (c *choiceFlag) Set(value string) error {
+	for _, choice := range c.choices {
+		if value == choice {
+			c.value = value
+			return nil
+		}
+	}
+	return fmt.Errorf("expected one of %v", strings.Join(c.choices, ", "))
+}
+
+// choice defines a flag that only accepts one of choices.
+func choice(f *flag.FlagSet, name, value, usage string, choices ...string) {
+	// the back quotes make PrintDefaults show the choices as the value name
+	f.Var(&choiceFlag{value: value, choices: choices}, name, usage+" (`"+strings.Join(choices, "|")+"`)")
+}
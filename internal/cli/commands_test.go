@@ -0,0 +1,185 @@
+package cli
+
+import (
+	"errors"
+	"flag"
+	"reflect"
+	"slices"
+	"testing"
+)
+
+func testSpec() Spec {
+	return Spec{
+		Name: "browse",
+		Args: []Arg{{Name: "name", Optional: true}, {Name: "url"}},
+		Flags: func(f *flag.FlagSet) {
+			f.Bool("all", false, "")
+			f.Int("limit", 2, "")
+			choice(f, "sort", "", "", "published", "feed")
+		},
+	}
+}
+
+func TestSpecParse(t *testing.T) {
+	cases := map[string]struct {
+		args  []string
+		pos   []string
+		all   bool
+		limit int
+		sort  string
+		err   bool
+	}{
+		"flags first":         {args: []string{"--all", "--limit", "5", "go", "https://go.dev"}, pos: []string{"go", "https://go.dev"}, all: true, limit: 5},
+		"flags interspersed":  {args: []string{"go", "--sort=feed", "https://go.dev", "--all"}, pos: []string{"go", "https://go.dev"}, all: true, limit: 2, sort: "feed"},
+		"double dash":         {args: []string{"--all", "--", "-go", "--limit"}, pos: []string{"-go", "--limit"}, all: true, limit: 2},
+		"optional arg absent": {args: []string{"https://go.dev"}, pos: []string{"https://go.dev"}, limit: 2},
+		"missing arg":         {args: []string{"--all"}, err: true},
+		"extra arg":           {args: []string{"a", "b", "c"}, err: true},
+		"unknown flag":        {args: []string{"--nope", "a"}, err: true},
+		"invalid int":         {args: []string{"--limit", "x", "a"}, err: true},
+		"invalid choice":      {args: []string{"--sort", "fetched", "a"}, err: true},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			cmd, err := testSpec().parse(tc.args)
+			if tc.err {
+				if err == nil {
+					t.Errorf("parse Failed, wanted an error for %v", tc.args)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("parse Failed %v", err)
+				return
+			}
+			if !reflect.DeepEqual(cmd.Args, tc.pos) {
+				t.Errorf("Args Mismatch wanted: %v , got: %v", tc.pos, cmd.Args)
+			}
+			if cmd.Bool("all") != tc.all || cmd.Int("limit") != tc.limit || cmd.String("sort") != tc.sort {
+				t.Errorf("Flags Mismatch wanted: %v %v %q , got: %v %v %q", tc.all, tc.limit, tc.sort, cmd.Bool("all"), cmd.Int("limit"), cmd.String("sort"))
+			}
+		})
+	}
+}
+
+func TestRunUsageErrors(t *testing.T) {
+	c := &Commands{}
+	ran := false
+	c.Register(Spec{Name: "browse", Args: []Arg{{Name: "limit"}}, Handler: func(s *State, cmd Command) error {
+		ran = true
+		return errors.New("db is down")
+	}})
+
+	cases := map[string]struct {
+		cmd  Command
+		code int
+		ran  bool
+	}{
+		"unknown command": {cmd: Command{Name: "brwse"}, code: exitUsage},
+		"missing arg":     {cmd: Command{Name: "browse"}, code: exitUsage},
+		"runtime error":   {cmd: Command{Name: "browse", Args: []string{"2"}}, code: exitRuntime, ran: true},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			ran = false
+			err := c.Run(nil, tc.cmd)
+			if err == nil {
+				t.Errorf("Run Failed, wanted an error")
+				return
+			}
+			if code := ExitCode(err); code != tc.code {
+				t.Errorf("ExitCode Mismatch wanted: %v , got: %v", tc.code, code)
+			}
+			if ran != tc.ran {
+				t.Errorf("Handler ran Mismatch wanted: %v , got: %v", tc.ran, ran)
+			}
+		})
+	}
+}
+
+func TestRunOutputFlags(t *testing.T) {
+	var ran Command
+	handler := func(s *State, cmd Command) error {
+		ran = cmd
+		return nil
+	}
+	c := &Commands{}
+	c.Register(Spec{Name: "feeds", Listing: true, Handler: handler})
+	c.Register(Spec{Name: "follow", Args: []Arg{{Name: "url"}}, Handler: handler})
+
+	cases := map[string]struct {
+		cmd    Command
+		output string
+		args   []string
+		err    bool
+	}{
+		"after the command":    {cmd: Command{Name: "feeds", Args: []string{"--output", "csv", "--fields", "name"}}, output: "csv"},
+		"before the command":   {cmd: Command{Name: "--output", Args: []string{"json", "feeds"}}, output: "json"},
+		"before a double dash": {cmd: Command{Name: "--output=json", Args: []string{"--", "feeds"}}, output: "json"},
+		"no command":           {cmd: Command{Name: "--output", Args: []string{"json"}}, err: true},
+		"fields of text":       {cmd: Command{Name: "feeds", Args: []string{"--fields", "name"}}, err: true},
+		"not a listing":        {cmd: Command{Name: "follow", Args: []string{"--output", "json", "https://go.dev"}}, err: true},
+		"not a listing before": {cmd: Command{Name: "--fields", Args: []string{"name", "follow", "https://go.dev"}}, err: true},
+		"arg after a dash":     {cmd: Command{Name: "follow", Args: []string{"--", "--output"}}, args: []string{"--output"}},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			ran = Command{}
+			err := c.Run(nil, tc.cmd)
+			if tc.err {
+				if ExitCode(err) != exitUsage || ran.Name != "" {
+					t.Errorf("Run Mismatch wanted a usage error, got: %v and ran %v", err, ran.Name)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Run Failed %v", err)
+			}
+			if ran.String("output") != tc.output || !slices.Equal(ran.Args, tc.args) {
+				t.Errorf("Command Mismatch wanted: %q %v , got: %q %v", tc.output, tc.args, ran.String("output"), ran.Args)
+			}
+		})
+	}
+}
+
+func TestRunSchemaCheck(t *testing.T) {
+	ran := map[string]bool{}
+	handler := func(s *State, cmd Command) error {
+		ran[cmd.Name] = true
+		return nil
+	}
+	c := &Commands{SchemaCheck: func(s *State) error {
+		return errors.New("the database schema is behind")
+	}}
+	c.Register(Spec{Name: "browse", Handler: handler})
+	c.Register(Spec{Name: "migrate", AnySchema: true, Handler: handler})
+
+	if err := c.Run(nil, Command{Name: "browse"}); err == nil || ran["browse"] {
+		t.Errorf("Run Failed, browse ran on a database that is behind")
+	}
+	if err := c.Run(nil, Command{Name: "migrate"}); err != nil || !ran["migrate"] {
+		t.Errorf("Run Failed, migrate has to run on any schema: %v", err)
+	}
+}
+
+func TestSuggest(t *testing.T) {
+	c := &Commands{}
+	for _, name := range []string{"browse", "follow", "following", "unfollow", "feeds"} {
+		c.Register(Spec{Name: name})
+	}
+	cases := map[string]string{
+		"brwse":    "browse",
+		"folow":    "follow",
+		"followin": "following",
+		"feds":     "feeds",
+		"xyz":      "",
+	}
+	for typo, want := range cases {
+		t.Run(typo, func(t *testing.T) {
+			got := c.Suggest(typo)
+			if got != want {
+				t.Errorf("Suggest Mismatch wanted: %v , got: %v", want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,290 @@
+package cli
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+)
+
+// candidate is a completion of the word being typed.
+type candidate struct {
+	Value string
+	// Description is shown next to the value by zsh, fish and the shell.
+	Description string
+	// Name also completes to Value, like the name of a feed to its url.
+	Name string
+}
+
+func (c candidate) matches(prefix string) bool {
+	if strings.HasPrefix(c.Value, prefix) {
+		return true
+	}
+	return prefix != "" && c.Name != "" && strings.HasPrefix(strings.ToLower(c.Name), strings.ToLower(prefix))
+}
+
+// complete returns the candidates for the last of words, the word being
+// typed: a command name, a flag, a flag value or the value of an argument.
+// values lists the values of an argument or a flag by its name.
+func (c *Commands) complete(words []string, values func(name string) []candidate) []candidate {
+	if len(words) == 0 {
+		return nil
+	}
+	partial := words[len(words)-1]
+	if len(words) == 1 {
+		var names []candidate
+		for _, name := range c.Names() {
+			names = append(names, candidate{Value: name, Description: c.Commands[name].Description})
+		}
+		return matching(names, partial)
+	}
+	spec, ok := c.Commands[words[0]]
+	if !ok {
+		return nil
+	}
+
+	flags := spec.flagSet()
+	position := 0
+	for i := 1; i < len(words)-1; i++ {
+		word := words[i]
+		if word == "--" || !strings.HasPrefix(word, "-") || word == "-" {
+			position++
+			continue
+		}
+		f := flags.Lookup(strings.TrimLeft(word, "-"))
+		if f == nil || isBoolFlag(f) || strings.Contains(word, "=") {
+			continue
+		}
+		if i == len(words)-2 {
+			// the word being typed is the value of this flag
+			if choices, ok := f.Value.(*choiceFlag); ok {
+				return matching(plainCandidates(choices.choices), partial)
+			}
+			if values == nil {
+				return nil
+			}
+			return matching(values(f.Name), partial)
+		}
+		i++
+	}
+
+	if strings.HasPrefix(partial, "-") {
+		var names []candidate
+		flags.VisitAll(func(f *flag.Flag) {
+			names = append(names, candidate{Value: "--" + f.Name, Description: f.Usage})
+		})
+		return matching(names, partial)
+	}
+
+	var arg Arg
+	switch {
+	case position < len(spec.Args):
+		arg = spec.Args[position]
+	case len(spec.Args) > 0 && spec.Args[len(spec.Args)-1].Variadic:
+		arg = spec.Args[len(spec.Args)-1]
+	default:
+		return nil
+	}
+	if strings.Contains(arg.Name, "|") {
+		return matching(plainCandidates(strings.Split(arg.Name, "|")), partial)
+	}
+	if values == nil {
+		return nil
+	}
+	return matching(values(arg.Name), partial)
+}
+
+// completionValues lists the values of the arguments and flags that name a
+// command, a feed, a user, a folder or a saved search, from the registry and
+// the database. Errors leave the list empty, completion never fails.
+func completionValues(c *Commands, s *State) func(name string) []candidate {
+	return func(name string) []candidate {
+		var values []candidate
+		switch name {
+		case "command":
+			for _, command := range c.Names() {
+				values = append(values, candidate{Value: command, Description: c.Commands[command].Description})
+			}
+		case "url", "feed":
+			feeds, err := s.DB.GetFeeds(context.Background())
+			if err != nil {
+				return nil
+			}
+			for _, feed := range feeds {
+				values = append(values, candidate{Value: feed.Url.String, Description: feed.Name.String, Name: feed.Name.String})
+			}
+		case "username", "user":
+			users, err := s.DB.GetUsers(context.Background())
+			if err != nil {
+				return nil
+			}
+			for _, user := range users {
+				values = append(values, candidate{Value: user.Name})
+			}
+		case "folder":
+			user, err := s.DB.GetUser(context.Background(), s.State.CurrentUserName)
+			if err != nil {
+				return nil
+			}
+			folders, err := s.DB.GetFoldersForUser(context.Background(), user.ID)
+			if err != nil {
+				return nil
+			}
+			for _, folder := range folders {
+				values = append(values, candidate{Value: folder.Name})
+			}
+		case "saved":
+			user, err := s.DB.GetUser(context.Background(), s.State.CurrentUserName)
+			if err != nil {
+				return nil
+			}
+			searches, err := s.DB.GetSavedSearchesForUser(context.Background(), user.ID)
+			if err != nil {
+				return nil
+			}
+			for _, search := range searches {
+				values = append(values, candidate{Value: search.Name, Description: search.Query})
+			}
+		}
+		return values
+	}
+}
+
+func isBoolFlag(f *flag.Flag) bool {
+	b, ok := f.Value.(interface{ IsBoolFlag() bool })
+	return ok && b.IsBoolFlag()
+}
+
+func plainCandidates(values []string) []candidate {
+	candidates := make([]candidate, len(values))
+	for i, value := range values {
+		candidates[i] = candidate{Value: value}
+	}
+	return candidates
+}
+
+// matching returns the candidates that match prefix, sorted by value.
+func matching(candidates []candidate, prefix string) []candidate {
+	var matches []candidate
+	for _, candidate := range candidates {
+		if candidate.matches(prefix) {
+			matches = append(matches, candidate)
+		}
+	}
+	sort.Slice(matches, func(i, j int) bool {
+		return matches[i].Value < matches[j].Value
+	})
+	return matches
+}
+
+// handlerComplete prints the completions of the words after "gator __complete --",
+// the last word is the one being typed, one "value<tab>description" per line.
+func handlerComplete(c *Commands, s *State, cmd Command) error {
+	words := cmd.Args
+	if len(words) == 0 {
+		words = []string{""}
+	}
+	for _, candidate := range c.complete(words, completionValues(c, s)) {
+		fmt.Printf("%v\t%v\n", candidate.Value, oneLine(candidate.Description))
+	}
+	return nil
+}
+
+// handlerCompletion prints the completion script of a shell.
+func handlerCompletion(c *Commands, s *State, cmd Command) error {
+	switch cmd.Args[0] {
+	case "bash":
+		return c.writeBashCompletion(os.Stdout)
+	case "zsh":
+		return c.writeZshCompletion(os.Stdout)
+	case "fish":
+		return c.writeFishCompletion(os.Stdout)
+	default:
+		return usageErrorf(cmd.Name, "unknown shell %v, expected bash, zsh or fish", cmd.Args[0])
+	}
+}
+
+func oneLine(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// quoteSingle quotes s for the single quoted strings of the scripts.
+func quoteSingle(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// The scripts list the commands of the registry themselves, everything after
+// the command name is completed by gator __complete.
+
+func (c *Commands) writeBashCompletion(w io.Writer) error {
+	_, err := fmt.Fprintf(w, `# bash completion for gator, load it with: source <(gator completion bash)
+_gator() {
+    local line=${COMP_LINE:0:COMP_POINT}
+    local -a words
+    read -ra words <<< "$line"
+    [[ $line =~ [[:space:]]$ ]] && words+=("")
+    local cur=${words[${#words[@]}-1]}
+    local IFS=$'\n'
+    if (( ${#words[@]} == 2 )); then
+        COMPREPLY=($(compgen -W %v -- "$cur"))
+        return
+    fi
+    COMPREPLY=($(gator __complete -- "${words[@]:1}" 2>/dev/null | cut -f1))
+    # bash splits words at colons, drop what it already has of a url
+    if [[ $cur == *:* ]]; then
+        local prefix=${cur%%"${cur##*:}"}
+        COMPREPLY=("${COMPREPLY[@]#"$prefix"}")
+    fi
+}
+complete -o default -F _gator gator
+`, quoteSingle(strings.Join(c.Names(), "\n")))
+	return err
+}
+
+func (c *Commands) writeZshCompletion(w io.Writer) error {
+	var commands []string
+	for _, name := range c.Names() {
+		commands = append(commands, "    "+quoteSingle(name+":"+oneLine(c.Commands[name].Description)))
+	}
+	_, err := fmt.Fprintf(w, `#compdef gator
+# zsh completion for gator, load it with: source <(gator completion zsh)
+_gator() {
+  local -a commands values
+  commands=(
+%v
+  )
+  if (( CURRENT == 2 )); then
+    _describe -t commands 'gator command' commands
+    return
+  fi
+  local line
+  for line in "${(@f)$(gator __complete -- "${(@)words[2,CURRENT]}" 2>/dev/null)}"; do
+    [[ -n $line ]] || continue
+    values+=("${${line%%%%$'\t'*}//:/\\:}:${line#*$'\t'}")
+  done
+  if (( ${#values} )); then
+    _describe -t values 'value' values
+  else
+    _files
+  fi
+}
+compdef _gator gator
+`, strings.Join(commands, "\n"))
+	return err
+}
+
+func (c *Commands) writeFishCompletion(w io.Writer) error {
+	var b strings.Builder
+	b.WriteString("# fish completion for gator, load it with: gator completion fish | source\n")
+	b.WriteString("complete -c gator -f\n")
+	for _, name := range c.Names() {
+		fmt.Fprintf(&b, "complete -c gator -n __fish_use_subcommand -a %v -d %v\n", name, quoteSingle(oneLine(c.Commands[name].Description)))
+	}
+	b.WriteString("complete -c gator -n 'not __fish_use_subcommand' -a '(gator __complete -- (commandline -opc)[2..-1] (commandline -ct) 2>/dev/null)'\n")
+	b.WriteString("complete -c gator -n '__fish_seen_subcommand_from batch' -F\n")
+	_, err := io.WriteString(w, b.String())
+	return err
+}
@@ -0,0 +1,98 @@
+package cli
+
+import (
+	"bytes"
+	"flag"
+	"os/exec"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestComplete(t *testing.T) {
+	c := &Commands{}
+	c.Register(testSpec())
+	c.Register(Spec{Name: "bookmark", Args: []Arg{{Name: "add|rm"}, {Name: "url", Variadic: true}}})
+	c.Register(Spec{Name: "filter", Flags: func(f *flag.FlagSet) {
+		f.String("feed", "", "")
+	}})
+	c.Register(Spec{Name: "secret", Hidden: true})
+	values := func(name string) []candidate {
+		if name == "url" || name == "feed" {
+			return []candidate{
+				{Value: "https://go.dev/feed", Name: "Go Blog"},
+				{Value: "https://blog.example.com/rss", Name: "Example"},
+			}
+		}
+		return nil
+	}
+
+	cases := map[string]struct {
+		words []string
+		want  []string
+	}{
+		"command names":      {words: []string{"b"}, want: []string{"bookmark", "browse"}},
+		"hidden command":     {words: []string{"sec"}},
+		"flags":              {words: []string{"browse", "--"}, want: []string{"--all", "--limit", "--sort"}},
+		"flag choices":       {words: []string{"browse", "--sort", "p"}, want: []string{"published"}},
+		"flag value":         {words: []string{"browse", "--limit", ""}},
+		"flag values":        {words: []string{"filter", "--feed", "https://g"}, want: []string{"https://go.dev/feed"}},
+		"argument value":     {words: []string{"browse", "go", "https://g"}, want: []string{"https://go.dev/feed"}},
+		"feed name":          {words: []string{"browse", "go", "go b"}, want: []string{"https://go.dev/feed"}},
+		"after a flag value": {words: []string{"browse", "--limit", "5", "go", "https://b"}, want: []string{"https://blog.example.com/rss"}},
+		"after a bool flag":  {words: []string{"browse", "--all", "go", "https://g"}, want: []string{"https://go.dev/feed"}},
+		"after flag=value":   {words: []string{"browse", "--limit=5", "go", "https://g"}, want: []string{"https://go.dev/feed"}},
+		"subcommands":        {words: []string{"bookmark", ""}, want: []string{"add", "rm"}},
+		"variadic":           {words: []string{"bookmark", "add", "https://go.dev/feed", "https://b"}, want: []string{"https://blog.example.com/rss"}},
+		"too many args":      {words: []string{"browse", "go", "https://go.dev/feed", ""}},
+		"unknown command":    {words: []string{"nope", ""}},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var got []string
+			for _, candidate := range c.complete(tc.words, values) {
+				got = append(got, candidate.Value)
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("complete Mismatch wanted: %q , got: %q", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestCompletionScripts(t *testing.T) {
+	c := NewCommands()
+	cases := map[string]func(w *bytes.Buffer) error{
+		"bash": func(w *bytes.Buffer) error { return c.writeBashCompletion(w) },
+		"zsh":  func(w *bytes.Buffer) error { return c.writeZshCompletion(w) },
+		"fish": func(w *bytes.Buffer) error { return c.writeFishCompletion(w) },
+	}
+	for shell, write := range cases {
+		t.Run(shell, func(t *testing.T) {
+			var b bytes.Buffer
+			if err := write(&b); err != nil {
+				t.Fatalf("write Failed %v", err)
+			}
+			script := b.String()
+			for _, name := range c.Names() {
+				if !strings.Contains(script, name) {
+					t.Errorf("script Failed, command %v is missing", name)
+				}
+			}
+			if !strings.Contains(script, "gator __complete --") {
+				t.Errorf("script Failed, it does not call gator __complete")
+			}
+			if strings.Contains(script, "%!") {
+				t.Errorf("script Failed, bad format verb in %v", script)
+			}
+			// the shells are not always installed, check the syntax when they are
+			if path, err := exec.LookPath(shell); err == nil {
+				check := exec.Command(path, "-n")
+				check.Stdin = strings.NewReader(script)
+				if out, err := check.CombinedOutput(); err != nil {
+					t.Errorf("%v -n Failed %v: %s", shell, err, out)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,113 @@
+package cli
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/o0n1x/gator/internal/database"
+	"github.com/o0n1x/gator/internal/extract"
+)
+
+const readWidth = 80
+
+func HandlerRead(s *State, cmd Command, user database.User) error {
+	if len(cmd.Args) < 1 {
+		return usageErrorf(cmd.Name, "expected arg 'post' but was not found")
+	}
+	post, err := findPost(s, user, cmd.Args[0])
+	if err != nil {
+		return err
+	}
+	printPostContent(post.Post, post.FeedName.String)
+	return markRead(s, user, post.Post)
+}
+
+// printPostContent prints a post for reading, its full content when available.
+func printPostContent(post database.Post, feedName string) {
+	text := post.Content.String
+	if !post.Content.Valid || text == "" {
+		text = post.Description.String
+	}
+
+	fmt.Printf(color.YellowString("Title: %v"), "")
+	fmt.Printf(color.GreenString("%v\n"), post.Title.String)
+	fmt.Printf("ID: %v\n", post.ID)
+	fmt.Printf("Feed: %v\n", feedName)
+	fmt.Printf("Published Date: %v\n", post.PublishedAt.Time)
+	fmt.Printf("URL: %v\n\n", post.Url.String)
+	fmt.Println(wrapText(text, readWidth))
+}
+
+func markRead(s *State, user database.User, post database.Post) error {
+	err := s.DB.MarkPostRead(context.Background(), database.MarkPostReadParams{
+		UserID: user.ID,
+		PostID: post.ID,
+		ReadAt: time.Now().UTC(),
+	})
+	if err != nil {
+		return fmt.Errorf("error marking post %v as read: %v", post.ID, err)
+	}
+	return nil
+}
+
+// findPost looks a post of user up by its full id or an unambiguous id
+// prefix, among the posts of followed feeds and the starred or queued ones.
+func findPost(s *State, user database.User, ref string) (database.GetPostsByIDPrefixRow, error) {
+	ref = strings.ToLower(strings.TrimSpace(ref))
+	if len(ref) < 4 {
+		return database.GetPostsByIDPrefixRow{}, fmt.Errorf("post id %v is too short, use at least 4 characters", ref)
+	}
+	if strings.Trim(ref, "0123456789abcdef-") != "" {
+		return database.GetPostsByIDPrefixRow{}, fmt.Errorf("invalid post id %v, expected hex digits and dashes", ref)
+	}
+	posts, err := s.DB.GetPostsByIDPrefix(context.Background(), database.GetPostsByIDPrefixParams{Prefix: ref, UserID: user.ID})
+	if err != nil {
+		return database.GetPostsByIDPrefixRow{}, fmt.Errorf("error looking up post %v: %v", ref, err)
+	}
+	switch len(posts) {
+	case 0:
+		return database.GetPostsByIDPrefixRow{}, fmt.Errorf("post %v does not exist", ref)
+	case 1:
+		return posts[0], nil
+	default:
+		return database.GetPostsByIDPrefixRow{}, fmt.Errorf("post id %v is ambiguous, use more characters", ref)
+	}
+}
+
+// fetchFullContent downloads the article behind a post and stores its extracted text.
+func fetchFullContent(s *State, post database.Post) error {
+	content, err := extract.FetchArticle(context.Background(), post.Url.String)
+	if err != nil {
+		return err
+	}
+	return s.DB.UpdatePostContent(context.Background(), database.UpdatePostContentParams{
+		ID:        post.ID,
+		UpdatedAt: sql.NullTime{Time: time.Now(), Valid: true},
+		Content:   sql.NullString{String: content, Valid: true},
+	})
+}
+
+// wrapText wraps every paragraph of text to width columns.
+func wrapText(text string, width int) string {
+	var out []string
+	for _, paragraph := range strings.Split(text, "\n") {
+		words := strings.Fields(paragraph)
+		line := ""
+		for _, word := range words {
+			if line != "" && len(line)+1+len(word) > width {
+				out = append(out, line)
+				line = ""
+			}
+			if line != "" {
+				line += " "
+			}
+			line += word
+		}
+		out = append(out, line)
+	}
+	return strings.Join(out, "\n")
+}
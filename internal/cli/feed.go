@@ -0,0 +1,177 @@
+package cli
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/o0n1x/gator/internal/database"
+)
+
+func HandlerFeed(s *State, cmd Command, user database.User) error {
+	if len(cmd.Args) < 1 {
+		return usageErrorf(cmd.Name, "expected arg 'rm|rename|set-url|chown' but was not found")
+	}
+	switch cmd.Args[0] {
+	case "rm":
+		if len(cmd.Args) < 2 {
+			return usageErrorf(cmd.Name, "expected arg 'url' but was not found")
+		}
+		var feed database.Feed
+		err := s.DB.InTx(context.Background(), func(tx database.Store) error {
+			var err error
+			feed, err = managedFeed(s, tx, user, cmd.Args[1])
+			if err != nil {
+				return err
+			}
+			if _, err := tx.DeleteFeed(context.Background(), feed.ID); err != nil {
+				return fmt.Errorf("error deleting feed %v: %v", feed.Name.String, err)
+			}
+			// starred and queued posts stay, like after unfollowing
+			if _, err := tx.PruneOrphanedPosts(context.Background()); err != nil {
+				return fmt.Errorf("error deleting posts of %v: %v", feed.Name.String, err)
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		fmt.Printf("deleted feed %v, its followers no longer follow it\n", feed.Name.String)
+		return nil
+
+	case "rename":
+		if len(cmd.Args) < 3 {
+			return usageErrorf(cmd.Name, "expected arg 'url' and 'name' but was not found")
+		}
+		var feed database.Feed
+		name := cmd.Args[2]
+		err := s.DB.InTx(context.Background(), func(tx database.Store) error {
+			var err error
+			feed, err = managedFeed(s, tx, user, cmd.Args[1])
+			if err != nil {
+				return err
+			}
+			_, err = tx.RenameFeed(context.Background(), database.RenameFeedParams{
+				ID:        feed.ID,
+				Name:      sql.NullString{String: name, Valid: true},
+				UpdatedAt: sql.NullTime{Time: time.Now(), Valid: true},
+			})
+			if err != nil {
+				return fmt.Errorf("error renaming feed %v: %v", feed.Name.String, err)
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		fmt.Printf("renamed feed %v to %v\n", feed.Name.String, name)
+		return nil
+
+	case "set-url":
+		if len(cmd.Args) < 3 {
+			return usageErrorf(cmd.Name, "expected arg 'url' and 'new_url' but was not found")
+		}
+		url, err := s.URLs().Normalize(cmd.Args[2])
+		if err != nil {
+			return fmt.Errorf("invalid feed url %v: %v", cmd.Args[2], err)
+		}
+		urlKey, err := s.URLs().Key(url)
+		if err != nil {
+			return fmt.Errorf("invalid feed url %v: %v", cmd.Args[2], err)
+		}
+		var feed database.Feed
+		err = s.DB.InTx(context.Background(), func(tx database.Store) error {
+			var err error
+			feed, err = managedFeed(s, tx, user, cmd.Args[1])
+			if err != nil {
+				return err
+			}
+			existing, err := tx.GetFeedByURLKey(context.Background(), sql.NullString{String: urlKey, Valid: true})
+			if err == nil && existing.ID != feed.ID {
+				return fmt.Errorf("feed already exists as %v (%v)", existing.Name.String, existing.Url.String)
+			}
+			if err != nil && !errors.Is(err, sql.ErrNoRows) {
+				return fmt.Errorf("error looking up feed %v: %v", url, err)
+			}
+			_, err = tx.SetFeedURL(context.Background(), database.SetFeedURLParams{
+				ID:        feed.ID,
+				Url:       sql.NullString{String: url, Valid: true},
+				UrlKey:    sql.NullString{String: urlKey, Valid: true},
+				UpdatedAt: sql.NullTime{Time: time.Now(), Valid: true},
+			})
+			if err != nil {
+				return fmt.Errorf("error changing the url of %v: %v", feed.Name.String, err)
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%v is now fetched from %v\n", feed.Name.String, url)
+		return nil
+
+	case "chown":
+		if len(cmd.Args) < 3 {
+			return usageErrorf(cmd.Name, "expected arg 'url' and 'username' but was not found")
+		}
+		var feed database.Feed
+		var owner database.User
+		err := s.DB.InTx(context.Background(), func(tx database.Store) error {
+			var err error
+			feed, err = managedFeed(s, tx, user, cmd.Args[1])
+			if err != nil {
+				return err
+			}
+			owner, err = tx.GetUser(context.Background(), cmd.Args[2])
+			if err != nil {
+				return fmt.Errorf("user %v does not exist", cmd.Args[2])
+			}
+			_, err = tx.SetFeedOwner(context.Background(), database.SetFeedOwnerParams{
+				ID:        feed.ID,
+				UserID:    uuid.NullUUID{UUID: owner.ID, Valid: true},
+				UpdatedAt: sql.NullTime{Time: time.Now(), Valid: true},
+			})
+			if err != nil {
+				return fmt.Errorf("error changing the owner of %v: %v", feed.Name.String, err)
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%v now owns %v\n", owner.Name, feed.Name.String)
+		return nil
+
+	default:
+		return usageErrorf(cmd.Name, "unknown subcommand %v, expected rm, rename, set-url or chown", cmd.Args[0])
+	}
+}
+
+// managedFeed looks up the feed of url in db for user to change it for every
+// follower, which only its owner and the admins can do. Feeds without an
+// owner are left to the admins. db is the transaction of the change, the feed
+// is locked in it so it can't be handed to someone else in between.
+func managedFeed(s *State, db database.Store, user database.User, url string) (database.Feed, error) {
+	urlKey, err := s.URLs().Key(url)
+	if err != nil {
+		return database.Feed{}, fmt.Errorf("invalid feed url %v: %v", url, err)
+	}
+	feed, err := db.LockFeedByURLKey(context.Background(), sql.NullString{String: urlKey, Valid: true})
+	if err != nil {
+		return database.Feed{}, fmt.Errorf("feed with url: %v does not exist", url)
+	}
+	if user.IsAdmin || (feed.UserID.Valid && feed.UserID.UUID == user.ID) {
+		return feed, nil
+	}
+	if !feed.UserID.Valid {
+		return database.Feed{}, fmt.Errorf("%v has no owner, only an admin can change it", feed.Name.String)
+	}
+	owner, err := db.GetUserByID(context.Background(), feed.UserID.UUID)
+	if err != nil {
+		return database.Feed{}, fmt.Errorf("only the owner of %v or an admin can change it", feed.Name.String)
+	}
+	return database.Feed{}, fmt.Errorf("only %v, the owner of %v, or an admin can change it", owner.Name, feed.Name.String)
+}
@@ -0,0 +1,147 @@
+package cli
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/o0n1x/gator/internal/database"
+	"github.com/o0n1x/gator/internal/output"
+)
+
+// unfiledFolder is the name used for follows that are not in any folder.
+const unfiledFolder = "-"
+
+func HandlerFolder(s *State, cmd Command, user database.User) error {
+	if len(cmd.Args) < 1 {
+		return usageErrorf(cmd.Name, "expected arg 'add|rename|rm|list|move' but was not found")
+	}
+	if err := listOnly(cmd); err != nil {
+		return err
+	}
+	switch cmd.Args[0] {
+	case "add":
+		if len(cmd.Args) < 2 {
+			return usageErrorf(cmd.Name, "expected arg 'name' but was not found")
+		}
+		name := cmd.Args[1]
+		if name == unfiledFolder {
+			return fmt.Errorf("%v is reserved for feeds without a folder", unfiledFolder)
+		}
+		folder, err := s.DB.CreateFolder(context.Background(), database.CreateFolderParams{
+			ID:        uuid.New(),
+			CreatedAt: sql.NullTime{Time: time.Now(), Valid: true},
+			UpdatedAt: sql.NullTime{Time: time.Now(), Valid: true},
+			UserID:    user.ID,
+			Name:      name,
+		})
+		if err != nil {
+			return fmt.Errorf("error creating folder %v: %v", name, err)
+		}
+		fmt.Printf("created folder %v\n", folder.Name)
+		return nil
+
+	case "rename":
+		if len(cmd.Args) < 3 {
+			return usageErrorf(cmd.Name, "expected arg 'name' and 'new_name' but was not found")
+		}
+		if cmd.Args[2] == unfiledFolder {
+			return fmt.Errorf("%v is reserved for feeds without a folder", unfiledFolder)
+		}
+		renamed, err := s.DB.RenameFolder(context.Background(), database.RenameFolderParams{
+			NewName:   cmd.Args[2],
+			UpdatedAt: sql.NullTime{Time: time.Now(), Valid: true},
+			UserID:    user.ID,
+			Name:      cmd.Args[1],
+		})
+		if err != nil {
+			return fmt.Errorf("error renaming folder %v: %v", cmd.Args[1], err)
+		}
+		if renamed == 0 {
+			return fmt.Errorf("folder %v does not exist", cmd.Args[1])
+		}
+		fmt.Printf("renamed folder %v to %v\n", cmd.Args[1], cmd.Args[2])
+		return nil
+
+	case "rm":
+		if len(cmd.Args) < 2 {
+			return usageErrorf(cmd.Name, "expected arg 'name' but was not found")
+		}
+		deleted, err := s.DB.DeleteFolder(context.Background(), database.DeleteFolderParams{
+			UserID: user.ID,
+			Name:   cmd.Args[1],
+		})
+		if err != nil {
+			return fmt.Errorf("error deleting folder %v: %v", cmd.Args[1], err)
+		}
+		if deleted == 0 {
+			return fmt.Errorf("folder %v does not exist", cmd.Args[1])
+		}
+		fmt.Printf("deleted folder %v, its feeds are now unfiled\n", cmd.Args[1])
+		return nil
+
+	case "list":
+		folders, err := s.DB.GetFoldersForUser(context.Background(), user.ID)
+		if err != nil {
+			return fmt.Errorf("error listing folders: %v", err)
+		}
+		if outputFormat(cmd) != output.Text {
+			var records []folderRecord
+			for _, folder := range folders {
+				records = append(records, folderRecord{Name: folder.Name, CreatedAt: timePtr(folder.CreatedAt)})
+			}
+			return writeRecords(cmd, records)
+		}
+		for _, folder := range folders {
+			fmt.Printf("* %v\n", folder.Name)
+		}
+		return nil
+
+	case "move":
+		if len(cmd.Args) < 3 {
+			return usageErrorf(cmd.Name, "expected arg 'url' and 'folder' but was not found")
+		}
+		feed, err := feedByURL(s, cmd.Args[1])
+		if err != nil {
+			return err
+		}
+		folderID := uuid.NullUUID{}
+		if cmd.Args[2] != unfiledFolder {
+			folder, err := folderByName(s, user, cmd.Args[2])
+			if err != nil {
+				return err
+			}
+			folderID = uuid.NullUUID{UUID: folder.ID, Valid: true}
+		}
+		moved, err := s.DB.SetFeedFollowFolder(context.Background(), database.SetFeedFollowFolderParams{
+			UserID:    uuid.NullUUID{UUID: user.ID, Valid: true},
+			FeedID:    uuid.NullUUID{UUID: feed.ID, Valid: true},
+			FolderID:  folderID,
+			UpdatedAt: sql.NullTime{Time: time.Now(), Valid: true},
+		})
+		if err != nil {
+			return fmt.Errorf("error moving %v: %v", feed.Name.String, err)
+		}
+		if moved == 0 {
+			return fmt.Errorf("you do not follow %v", feed.Name.String)
+		}
+		fmt.Printf("moved %v to %v\n", feed.Name.String, cmd.Args[2])
+		return nil
+
+	default:
+		return usageErrorf(cmd.Name, "unknown subcommand %v, expected add, rename, rm, list or move", cmd.Args[0])
+	}
+}
+
+func folderByName(s *State, user database.User, name string) (database.Folder, error) {
+	folder, err := s.DB.GetFolderByName(context.Background(), database.GetFolderByNameParams{
+		UserID: user.ID,
+		Name:   name,
+	})
+	if err != nil {
+		return database.Folder{}, fmt.Errorf("folder %v does not exist", name)
+	}
+	return folder, nil
+}
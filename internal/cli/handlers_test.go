@@ -0,0 +1,595 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/o0n1x/gator/internal/config"
+	"github.com/o0n1x/gator/internal/database"
+)
+
+// testState is a state on the memory store, its config is written in a
+// temporary home.
+func testState(t *testing.T) *State {
+	t.Helper()
+	t.Setenv("HOME", t.TempDir())
+	// the tests read their feeds from files
+	return &State{State: &config.Config{AllowFileFeeds: true}, DB: database.NewMemory()}
+}
+
+// run runs one command line like gator would.
+func run(s *State, line string) error {
+	fields := strings.Fields(line)
+	return NewCommands().Run(s, Command{Name: fields[0], Args: fields[1:]})
+}
+
+func mustRun(t *testing.T, s *State, lines ...string) {
+	t.Helper()
+	for _, line := range lines {
+		if err := run(s, line); err != nil {
+			t.Fatalf("%v Failed %v", line, err)
+		}
+	}
+}
+
+func TestRegisterAndLogin(t *testing.T) {
+	s := testState(t)
+	mustRun(t, s, "register alice", "register bob")
+	if s.State.CurrentUserName != "bob" {
+		t.Errorf("register Mismatch wanted: %v , got: %v", "bob", s.State.CurrentUserName)
+	}
+
+	cases := map[string]struct {
+		line string
+		user string
+		err  bool
+	}{
+		"login":            {line: "login alice", user: "alice"},
+		"unknown user":     {line: "login carol", err: true},
+		"registered twice": {line: "register alice", err: true},
+		"missing name":     {line: "login", err: true},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			s.State.CurrentUserName = "bob"
+			err := run(s, tc.line)
+			if tc.err {
+				if err == nil {
+					t.Errorf("%v Failed, wanted an error", tc.line)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("%v Failed %v", tc.line, err)
+			}
+			if s.State.CurrentUserName != tc.user {
+				t.Errorf("%v Mismatch wanted: %v , got: %v", tc.line, tc.user, s.State.CurrentUserName)
+			}
+		})
+	}
+
+	users, err := s.DB.GetUsers(context.Background())
+	if err != nil || len(users) != 2 {
+		t.Errorf("GetUsers Mismatch wanted: 2 users , got: %v %v", users, err)
+	}
+}
+
+func TestLoggedIn(t *testing.T) {
+	s := testState(t)
+	if err := run(s, "following"); err == nil {
+		t.Errorf("following Failed, wanted an error without a user")
+	}
+	s.State.CurrentUserName = "ghost"
+	if err := run(s, "following"); err == nil {
+		t.Errorf("following Failed, wanted an error for a user that does not exist")
+	}
+}
+
+func TestFollowAndUnfollow(t *testing.T) {
+	s := testState(t)
+	mustRun(t, s, "register alice", "addfeed go https://go.dev/blog/feed.atom", "register bob")
+
+	steps := []struct {
+		line    string
+		follows int
+		err     bool
+	}{
+		{line: "follow https://go.dev/blog/feed.atom", follows: 1},
+		{line: "follow https://go.dev/blog/feed.atom", err: true},
+		{line: "follow https://example.com/feed", err: true},
+		{line: "unfollow https://go.dev/blog/feed.atom", follows: 0},
+		{line: "unfollow https://example.com/feed", err: true},
+		// any url with the same key is the same feed
+		{line: "follow https://GO.dev/blog/feed.atom?utm_source=x", follows: 1},
+		{line: "addfeed again https://go.dev/blog/feed.atom?utm_source=y", err: true},
+	}
+	for _, step := range steps {
+		err := run(s, step.line)
+		if step.err {
+			if err == nil {
+				t.Errorf("%v Failed, wanted an error", step.line)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%v Failed %v", step.line, err)
+		}
+		if follows := followsOf(t, s, "bob"); len(follows) != step.follows {
+			t.Errorf("%v Mismatch wanted: %v follows , got: %v", step.line, step.follows, follows)
+		}
+	}
+
+	feeds, err := s.DB.GetFeeds(context.Background())
+	if err != nil || len(feeds) != 1 {
+		t.Errorf("GetFeeds Mismatch wanted: 1 feed , got: %v %v", feeds, err)
+	}
+}
+
+func TestFolders(t *testing.T) {
+	s := testState(t)
+	mustRun(t, s, "register alice", "addfeed go https://go.dev/blog/feed.atom", "folder add tech")
+
+	steps := []struct {
+		line   string
+		folder string
+		err    bool
+	}{
+		{line: "folder add tech", err: true},
+		{line: "folder add -", err: true},
+		{line: "folder move https://go.dev/blog/feed.atom news", err: true},
+		{line: "folder move https://go.dev/blog/feed.atom tech", folder: "tech"},
+		{line: "folder rename tech dev", folder: "dev"},
+		{line: "folder rename nope other", folder: "dev", err: true},
+		{line: "folder move https://go.dev/blog/feed.atom -"},
+		{line: "folder move https://go.dev/blog/feed.atom dev", folder: "dev"},
+		{line: "folder rm dev"},
+		{line: "folder rm dev", err: true},
+	}
+	for _, step := range steps {
+		err := run(s, step.line)
+		if step.err {
+			if err == nil {
+				t.Errorf("%v Failed, wanted an error", step.line)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%v Failed %v", step.line, err)
+		}
+		follows := followsOf(t, s, "alice")
+		if len(follows) != 1 || follows[0].FolderName.String != step.folder {
+			t.Errorf("%v Mismatch wanted: folder %q , got: %v", step.line, step.folder, follows)
+		}
+	}
+}
+
+func TestReset(t *testing.T) {
+	s := testState(t)
+	mustRun(t, s, "register alice", "addfeed go https://go.dev/blog/feed.atom", "reset")
+	users, err := s.DB.GetUsers(context.Background())
+	if err != nil || len(users) != 0 {
+		t.Errorf("reset Mismatch wanted: no users , got: %v %v", users, err)
+	}
+	feeds, err := s.DB.GetFeeds(context.Background())
+	if err != nil || len(feeds) != 0 {
+		t.Errorf("reset Mismatch wanted: no feeds , got: %v %v", feeds, err)
+	}
+}
+
+func followsOf(t *testing.T, s *State, name string) []database.GetFeedFollowsForUserRow {
+	t.Helper()
+	user, err := s.DB.GetUser(context.Background(), name)
+	if err != nil {
+		t.Fatalf("GetUser Failed %v", err)
+	}
+	follows, err := s.DB.GetFeedFollowsForUser(context.Background(), uuid.NullUUID{UUID: user.ID, Valid: true})
+	if err != nil {
+		t.Fatalf("GetFeedFollowsForUser Failed %v", err)
+	}
+	return follows
+}
+
+var errInjected = errors.New("injected failure")
+
+// failingStore fails every call of the method named fail, in its
+// transactions too.
+type failingStore struct {
+	database.Store
+	fail string
+}
+
+func (f failingStore) InTx(ctx context.Context, fn func(tx database.Store) error) error {
+	return f.Store.InTx(ctx, func(tx database.Store) error {
+		return fn(failingStore{Store: tx, fail: f.fail})
+	})
+}
+
+func (f failingStore) CreateFeedFollow(ctx context.Context, arg database.CreateFeedFollowParams) (database.CreateFeedFollowRow, error) {
+	if f.fail == "CreateFeedFollow" {
+		return database.CreateFeedFollowRow{}, errInjected
+	}
+	return f.Store.CreateFeedFollow(ctx, arg)
+}
+
+func (f failingStore) CreateFeedScraper(ctx context.Context, arg database.CreateFeedScraperParams) (database.FeedScraper, error) {
+	if f.fail == "CreateFeedScraper" {
+		return database.FeedScraper{}, errInjected
+	}
+	return f.Store.CreateFeedScraper(ctx, arg)
+}
+
+func (f failingStore) SetFeedFollowFolder(ctx context.Context, arg database.SetFeedFollowFolderParams) (int64, error) {
+	if f.fail == "SetFeedFollowFolder" {
+		return 0, errInjected
+	}
+	return f.Store.SetFeedFollowFolder(ctx, arg)
+}
+
+func (f failingStore) SetFeedSiteURL(ctx context.Context, arg database.SetFeedSiteURLParams) error {
+	if f.fail == "SetFeedSiteURL" {
+		return errInjected
+	}
+	return f.Store.SetFeedSiteURL(ctx, arg)
+}
+
+func (f failingStore) CreatePageSnapshot(ctx context.Context, arg database.CreatePageSnapshotParams) (database.PageSnapshot, error) {
+	if f.fail == "CreatePageSnapshot" {
+		return database.PageSnapshot{}, errInjected
+	}
+	return f.Store.CreatePageSnapshot(ctx, arg)
+}
+
+func (f failingStore) CreatePost(ctx context.Context, arg database.CreatePostParams) (database.Post, error) {
+	if f.fail == "CreatePost" {
+		return database.Post{}, errInjected
+	}
+	return f.Store.CreatePost(ctx, arg)
+}
+
+func (f failingStore) MarkFeedFetched(ctx context.Context, arg database.MarkFeedFetchedParams) error {
+	if f.fail == "MarkFeedFetched" {
+		return errInjected
+	}
+	return f.Store.MarkFeedFetched(ctx, arg)
+}
+
+// failing makes the store of s fail the method named fail from now on.
+func failing(s *State, fail string) *database.Memory {
+	memory := s.DB.(*database.Memory)
+	s.DB = failingStore{Store: memory, fail: fail}
+	return memory
+}
+
+func TestAddFeedFailures(t *testing.T) {
+	cases := map[string]struct {
+		line string
+		fail string
+	}{
+		"addfeed follow":      {line: "addfeed go https://go.dev/blog/feed.atom", fail: "CreateFeedFollow"},
+		"addscrape follow":    {line: "addscrape --item .post --title h2 --link a blog https://example.com/blog", fail: "CreateFeedFollow"},
+		"addscrape selectors": {line: "addscrape --item .post --title h2 --link a blog https://example.com/blog", fail: "CreateFeedScraper"},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			s := testState(t)
+			mustRun(t, s, "register alice")
+			memory := failing(s, tc.fail)
+			if err := run(s, tc.line); err == nil || !strings.Contains(err.Error(), errInjected.Error()) {
+				t.Errorf("%v Mismatch wanted: %v , got: %v", tc.line, errInjected, err)
+			}
+			// the feed went with the step that failed
+			if feeds, err := memory.GetFeeds(context.Background()); err != nil || len(feeds) != 0 {
+				t.Errorf("GetFeeds Mismatch wanted: no feeds , got: %v %v", feeds, err)
+			}
+		})
+	}
+}
+
+func TestImportFailure(t *testing.T) {
+	s := testState(t)
+	mustRun(t, s, "register alice")
+	path := filepath.Join(t.TempDir(), "feeds.opml")
+	err := os.WriteFile(path, []byte(`<opml version="2.0"><body>
+  <outline text="Go Blog" type="rss" xmlUrl="https://go.dev/blog/feed.atom"/>
+  <outline text="Tech">
+    <outline text="LWN" type="rss" xmlUrl="https://lwn.net/headlines/rss"/>
+  </outline>
+</body></opml>`), 0o600)
+	if err != nil {
+		t.Fatalf("WriteFile Failed %v", err)
+	}
+
+	memory := failing(s, "SetFeedFollowFolder")
+	if err := run(s, "import opml "+path); err == nil {
+		t.Errorf("import Failed, wanted an error for the feed that could not be filed")
+	}
+	// the feed without a folder is imported, nothing of the other one is
+	feeds, err := memory.GetFeeds(context.Background())
+	if err != nil || len(feeds) != 1 || feeds[0].Name.String != "Go Blog" {
+		t.Errorf("GetFeeds Mismatch wanted: Go Blog , got: %v %v", feeds, err)
+	}
+	user, _ := memory.GetUser(context.Background(), "alice")
+	if folders, err := memory.GetFoldersForUser(context.Background(), user.ID); err != nil || len(folders) != 0 {
+		t.Errorf("GetFoldersForUser Mismatch wanted: no folders , got: %v %v", folders, err)
+	}
+
+	// the failed feed imports once the store works again
+	s.DB = memory
+	mustRun(t, s, "import opml "+path)
+	if follows := followsOf(t, s, "alice"); len(follows) != 2 {
+		t.Errorf("import Mismatch wanted: 2 follows , got: %v", follows)
+	}
+}
+
+func TestRefreshFeedFailures(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "feed.xml")
+	err := os.WriteFile(path, []byte(`<rss version="2.0"><channel>
+  <title>Go</title><link>https://go.dev/blog</link>
+  <item><title>Generics</title><link>https://go.dev/blog/generics</link><pubDate>Fri, 03 May 2024 10:00:00 +0000</pubDate></item>
+  <item><title>Iterators</title><link>https://go.dev/blog/iterators</link><pubDate>Sat, 04 May 2024 10:00:00 +0000</pubDate></item>
+</channel></rss>`), 0o600)
+	if err != nil {
+		t.Fatalf("WriteFile Failed %v", err)
+	}
+	badDate := filepath.Join(t.TempDir(), "bad_date.xml")
+	err = os.WriteFile(badDate, []byte(`<rss version="2.0"><channel>
+  <title>Go</title><link>https://go.dev/blog</link>
+  <item><title>Generics</title><link>https://go.dev/blog/generics</link><pubDate>the third of may</pubDate></item>
+  <item><title>Iterators</title><link>https://go.dev/blog/iterators</link><pubDate>Sat, 04 May 2024 10:00:00 +0000</pubDate></item>
+</channel></rss>`), 0o600)
+	if err != nil {
+		t.Fatalf("WriteFile Failed %v", err)
+	}
+	page := filepath.Join(t.TempDir(), "page.html")
+	if err := os.WriteFile(page, []byte("<html><body><p>v2 is out</p></body></html>"), 0o600); err != nil {
+		t.Fatalf("WriteFile Failed %v", err)
+	}
+
+	cases := map[string]struct {
+		url   string
+		kind  string
+		fail  string
+		posts int
+		err   bool
+	}{
+		"refreshed":       {url: "file://" + path, posts: 2},
+		"bad date":        {url: "file://" + badDate, posts: 1},
+		"fetch":           {url: "file://" + path + ".missing", err: true},
+		"site url":        {url: "file://" + path, fail: "SetFeedSiteURL", err: true},
+		"post":            {url: "file://" + path, fail: "CreatePost", err: true},
+		"mark as fetched": {url: "file://" + path, fail: "MarkFeedFetched", err: true},
+		"watch refreshed": {url: "file://" + page, kind: feedKindWatch, posts: 1},
+		"watch snapshot":  {url: "file://" + page, kind: feedKindWatch, fail: "CreatePageSnapshot", err: true},
+		"watch post":      {url: "file://" + page, kind: feedKindWatch, fail: "CreatePost", err: true},
+		"watch fetched":   {url: "file://" + page, kind: feedKindWatch, fail: "MarkFeedFetched", err: true},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			s := testState(t)
+			mustRun(t, s, "register alice")
+			user, _ := s.DB.GetUser(context.Background(), "alice")
+			kind := tc.kind
+			if kind == "" {
+				kind = feedKindRSS
+			}
+			feed, _, err := createFeed(s, s.DB, user, "go", tc.url, kind)
+			if err != nil {
+				t.Fatalf("createFeed Failed %v", err)
+			}
+			if kind == feedKindWatch {
+				if _, err := s.DB.CreateFeedWatch(context.Background(), database.CreateFeedWatchParams{FeedID: feed.ID}); err != nil {
+					t.Fatalf("CreateFeedWatch Failed %v", err)
+				}
+				_, err := s.DB.CreatePageSnapshot(context.Background(), database.CreatePageSnapshotParams{
+					ID: uuid.New(), CreatedAt: time.Now().UTC().Add(-time.Hour), FeedID: feed.ID, Content: "v1 is out\n",
+				})
+				if err != nil {
+					t.Fatalf("CreatePageSnapshot Failed %v", err)
+				}
+			}
+			memory := failing(s, tc.fail)
+
+			_, err = refreshFeed(s, feed, func(string, ...interface{}) {})
+			if tc.err != (err != nil) {
+				t.Fatalf("refreshFeed Mismatch wanted an error: %v , got: %v", tc.err, err)
+			}
+			posts, err := memory.GetPostsForUser(context.Background(), database.GetPostsForUserParams{ID: user.ID, Limit: 5})
+			if err != nil || len(posts) != tc.posts {
+				t.Errorf("GetPostsForUser Mismatch wanted: %v posts , got: %v %v", tc.posts, posts, err)
+			}
+			// a failed feed is marked as fetched too, so it waits for its
+			// next turn, the site url is only kept along with the posts
+			fetched := tc.fail != "MarkFeedFetched"
+			feeds, err := memory.GetFeeds(context.Background())
+			if err != nil || len(feeds) != 1 || feeds[0].LastFetchedAt.Valid != fetched || feeds[0].SiteUrl.Valid == tc.err {
+				t.Errorf("GetFeeds Mismatch wanted fetched: %v and a site url: %v , got: %v %v", fetched, !tc.err, feeds, err)
+			}
+			// a change is only kept along with its post
+			if kind == feedKindWatch {
+				snapshot, err := memory.GetLatestPageSnapshot(context.Background(), feed.ID)
+				if err != nil || strings.Contains(snapshot.Content, "v2") == tc.err {
+					t.Errorf("GetLatestPageSnapshot Mismatch wanted the new page: %v , got: %q %v", !tc.err, snapshot.Content, err)
+				}
+			}
+			if tc.err {
+				return
+			}
+			// the posts of the next fetch are already there
+			if _, err := refreshFeed(s, feeds[0], func(string, ...interface{}) {}); err != nil {
+				t.Errorf("refreshFeed Failed %v", err)
+			}
+			if posts, _ := memory.GetPostsForUser(context.Background(), database.GetPostsForUserParams{ID: user.ID, Limit: 5}); len(posts) != tc.posts {
+				t.Errorf("GetPostsForUser Mismatch wanted: %v posts , got: %v", tc.posts, posts)
+			}
+		})
+	}
+}
+
+func TestFeedCommands(t *testing.T) {
+	s := testState(t)
+	// alice registered first and is the admin
+	mustRun(t, s, "register alice", "register carol", "register bob",
+		"addfeed go https://go.dev/blog/feed.atom", "addfeed rust https://blog.rust-lang.org/feed.xml")
+
+	steps := []struct {
+		user string
+		line string
+		err  bool
+	}{
+		{user: "carol", line: "feed rename https://go.dev/blog/feed.atom mine", err: true},
+		{user: "bob", line: "feed rename https://go.dev/blog/feed.atom golang"},
+		{user: "bob", line: "feed set-url https://go.dev/blog/feed.atom https://blog.rust-lang.org/feed.xml", err: true},
+		{user: "alice", line: "feed set-url https://go.dev/blog/feed.atom https://go.dev/feed.atom?utm_source=x"},
+		{user: "bob", line: "feed chown https://go.dev/feed.atom nobody", err: true},
+		{user: "bob", line: "feed chown https://go.dev/feed.atom carol"},
+		{user: "bob", line: "feed rename https://go.dev/feed.atom again", err: true},
+		{user: "carol", line: "feed rm https://blog.rust-lang.org/feed.xml", err: true},
+		{user: "alice", line: "feed rm https://blog.rust-lang.org/feed.xml"},
+		{user: "alice", line: "feed rm https://blog.rust-lang.org/feed.xml", err: true},
+		{user: "alice", line: "feed move https://go.dev/feed.atom x", err: true},
+	}
+	for _, step := range steps {
+		s.State.CurrentUserName = step.user
+		err := run(s, step.line)
+		if step.err && err == nil {
+			t.Errorf("%v as %v Failed, wanted an error", step.line, step.user)
+		}
+		if !step.err && err != nil {
+			t.Fatalf("%v as %v Failed %v", step.line, step.user, err)
+		}
+	}
+
+	feeds, err := s.DB.GetFeeds(context.Background())
+	if err != nil || len(feeds) != 1 {
+		t.Fatalf("GetFeeds Mismatch wanted: 1 feed , got: %v %v", feeds, err)
+	}
+	carol, _ := s.DB.GetUser(context.Background(), "carol")
+	if feed := feeds[0]; feed.Name.String != "golang" || feed.Url.String != "https://go.dev/feed.atom" || feed.UserID.UUID != carol.ID {
+		t.Errorf("feed Mismatch wanted: golang at https://go.dev/feed.atom owned by carol , got: %+v", feed)
+	}
+	// the follows of a feed go with it
+	if follows := followsOf(t, s, "bob"); len(follows) != 1 {
+		t.Errorf("feed rm Mismatch wanted: 1 follow , got: %v", follows)
+	}
+}
+
+func TestUnregister(t *testing.T) {
+	s := testState(t)
+	mustRun(t, s, "register alice", "register bob",
+		"addfeed go https://go.dev/blog/feed.atom", "addfeed rust https://blog.rust-lang.org/feed.xml",
+		"register carol", "follow https://go.dev/blog/feed.atom")
+
+	steps := []struct {
+		user string
+		line string
+		err  bool
+	}{
+		{user: "carol", line: "unregister bob", err: true},
+		{user: "alice", line: "admin revoke alice", err: true},
+		{user: "bob", line: "admin grant bob", err: true},
+		{user: "bob", line: "unregister"},
+		// nobody owns go anymore, only an admin can change it
+		{user: "carol", line: "feed rename https://go.dev/blog/feed.atom mine", err: true},
+		{user: "alice", line: "feed chown https://go.dev/blog/feed.atom carol"},
+		{user: "carol", line: "feed rename https://go.dev/blog/feed.atom mine"},
+		{user: "alice", line: "admin grant carol"},
+		{user: "carol", line: "admin revoke alice"},
+		{user: "carol", line: "unregister alice"},
+	}
+	for _, step := range steps {
+		s.State.CurrentUserName = step.user
+		err := run(s, step.line)
+		if step.err && err == nil {
+			t.Errorf("%v as %v Failed, wanted an error", step.line, step.user)
+		}
+		if !step.err && err != nil {
+			t.Fatalf("%v as %v Failed %v", step.line, step.user, err)
+		}
+	}
+
+	// the feed carol follows survived bob, the one only he read did not
+	feeds, err := s.DB.GetFeeds(context.Background())
+	if err != nil || len(feeds) != 1 || feeds[0].Name.String != "mine" {
+		t.Errorf("GetFeeds Mismatch wanted: mine , got: %v %v", feeds, err)
+	}
+	users, err := s.DB.GetUsers(context.Background())
+	if err != nil || len(users) != 1 || users[0].Name != "carol" || !users[0].IsAdmin {
+		t.Errorf("GetUsers Mismatch wanted: carol as admin , got: %+v %v", users, err)
+	}
+	if follows := followsOf(t, s, "carol"); len(follows) != 1 {
+		t.Errorf("unregister Mismatch wanted: 1 follow for carol , got: %v", follows)
+	}
+}
+
+func TestScrapeFeedsSkipsFailedFeeds(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "feed.xml")
+	err := os.WriteFile(path, []byte(`<rss version="2.0"><channel>
+  <title>Go</title><link>https://go.dev/blog</link>
+  <item><title>Generics</title><link>https://go.dev/blog/generics</link><pubDate>Fri, 03 May 2024 10:00:00 +0000</pubDate></item>
+</channel></rss>`), 0o600)
+	if err != nil {
+		t.Fatalf("WriteFile Failed %v", err)
+	}
+	s := testState(t)
+	mustRun(t, s, "register alice")
+	// the missing feed is added first, it is picked first
+	mustRun(t, s, "addfeed missing file:///nonexistent/feed.xml")
+	mustRun(t, s, "addfeed go file://"+path)
+
+	if err := scrapeFeeds(s, 0); err == nil {
+		t.Errorf("scrapeFeeds Failed, wanted an error for the missing feed")
+	}
+	if err := scrapeFeeds(s, 0); err != nil {
+		t.Errorf("scrapeFeeds Failed %v", err)
+	}
+	user, _ := s.DB.GetUser(context.Background(), "alice")
+	posts, err := s.DB.GetPostsForUser(context.Background(), database.GetPostsForUserParams{ID: user.ID, Limit: 5})
+	if err != nil || len(posts) != 1 {
+		t.Errorf("GetPostsForUser Mismatch wanted: [Generics] , got: %v %v", posts, err)
+	}
+}
+
+func TestListSubcommandOutput(t *testing.T) {
+	s := testState(t)
+	mustRun(t, s, "register alice", "folder add tech", "savedsearch add go golang")
+
+	cases := map[string]struct {
+		line string
+		err  bool
+	}{
+		"later list":       {line: "later list --output json"},
+		"folder list":      {line: "folder list --output csv --fields name"},
+		"savedsearch list": {line: "savedsearch list --output jsonl"},
+		"later next":       {line: "later next --output json", err: true},
+		"folder add":       {line: "folder add news --output json", err: true},
+		"savedsearch rm":   {line: "savedsearch rm go --output tsv --fields name", err: true},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := run(s, tc.line)
+			if tc.err {
+				if ExitCode(err) != exitUsage {
+					t.Errorf("%v Mismatch wanted a usage error, got: %v", tc.line, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("%v Failed %v", tc.line, err)
+			}
+		})
+	}
+	// the refused subcommands did nothing
+	user, err := s.DB.GetUser(context.Background(), "alice")
+	if err != nil {
+		t.Fatalf("GetUser Failed %v", err)
+	}
+	if folders, err := s.DB.GetFoldersForUser(context.Background(), user.ID); err != nil || len(folders) != 1 {
+		t.Errorf("GetFoldersForUser Mismatch wanted: only tech , got: %v %v", folders, err)
+	}
+}
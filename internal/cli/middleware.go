@@ -3,7 +3,6 @@ package cli
 import (
 	"context"
 	"fmt"
-	"os"
 
 	"github.com/o0n1x/gator/internal/database"
 )
@@ -13,8 +12,7 @@ func MiddlewareLoggedIn(handler func(s *State, cmd Command, user database.User)
 	return func(s *State, cmd Command) error {
 		user, err := s.DB.GetUser(context.Background(), s.State.CurrentUserName)
 		if err != nil {
-			fmt.Printf("Error registering feed as User %v is not found. Error: %v", s.State.CurrentUserName, err)
-			os.Exit(1)
+			return fmt.Errorf("user %v is not found, login or register first: %v", s.State.CurrentUserName, err)
 		}
 		return handler(s, cmd, user)
 	}
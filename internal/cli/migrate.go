@@ -0,0 +1,103 @@
+package cli
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/o0n1x/gator/internal/database"
+	"github.com/o0n1x/gator/internal/migrate"
+)
+
+func migrateFlags(f *flag.FlagSet) {
+	f.Int("to", 0, "apply the migrations up to this version only, up applies every one by default")
+}
+
+func HandlerMigrate(s *State, cmd Command) error {
+	migrations, err := migrate.Embedded(database.DialectOf(s.Conn))
+	if err != nil {
+		return err
+	}
+	m := &migrate.Migrator{DB: s.Conn, Migrations: migrations, URLs: s.URLs()}
+	ctx := context.Background()
+	// the next command checks the schema again
+	s.schemaChecked = false
+
+	switch cmd.Args[0] {
+	case "up":
+		done, err := m.Up(ctx, int64(cmd.Int("to")))
+		for _, migration := range done {
+			fmt.Printf("applied %v\n", migration.Name)
+		}
+		if err != nil {
+			return err
+		}
+		if len(done) == 0 {
+			fmt.Println("the database is up to date")
+		}
+		return nil
+
+	case "down":
+		migration, err := m.Down(ctx)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("rolled back %v\n", migration.Name)
+		return nil
+
+	case "redo":
+		migration, err := m.Redo(ctx)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("rolled back and applied %v\n", migration.Name)
+		return nil
+
+	case "status":
+		statuses, err := m.Status(ctx)
+		if err != nil {
+			return err
+		}
+		table := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(table, "VERSION\tMIGRATION\tAPPLIED")
+		for _, status := range statuses {
+			applied := "pending"
+			if status.Applied {
+				applied = status.AppliedAt.Local().Format("2006-01-02 15:04")
+			}
+			fmt.Fprintf(table, "%v\t%v\t%v\n", status.Version, status.Name, applied)
+		}
+		return table.Flush()
+
+	default:
+		return usageErrorf(cmd.Name, "unknown subcommand %v, expected up, down, status or redo", cmd.Args[0])
+	}
+}
+
+// checkSchema refuses to run commands on a database whose schema is behind
+// the migrations of this gator, they would fail with sql errors instead.
+func checkSchema(s *State) error {
+	// a store that is not a database has no schema to check
+	if s.schemaChecked || s.Conn == nil {
+		return nil
+	}
+	dialect := database.DialectOf(s.Conn)
+	migrations, err := migrate.Embedded(dialect)
+	if err != nil {
+		return err
+	}
+	current, err := migrate.Current(context.Background(), s.Conn, dialect)
+	if err != nil {
+		return err
+	}
+	if latest := migrate.Latest(migrations); current < latest {
+		if current == 0 {
+			return fmt.Errorf("the database has no tables yet, run gator migrate up first")
+		}
+		return fmt.Errorf("the database schema is at version %v but this gator needs version %v, run gator migrate up first (gator backup saves it before)", current, latest)
+	}
+	s.schemaChecked = true
+	return nil
+}
@@ -0,0 +1,297 @@
+package cli
+
+import (
+	"context"
+	"database/sql"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/o0n1x/gator/internal/database"
+	"github.com/o0n1x/gator/internal/opml"
+)
+
+func importFlags(f *flag.FlagSet) {
+	f.Bool("dry-run", false, "print what would be imported without changing anything")
+	f.Bool("validate", false, "fetch every feed first and skip the ones that are not a valid feed")
+	f.Int("concurrency", 8, "number of feeds validated at the same time")
+}
+
+// import actions of an opml feed
+const (
+	importAdd    = "add"
+	importFollow = "follow"
+	importSkip   = "skip"
+	importFail   = "fail"
+)
+
+// importEntry is a feed of the opml file and what importing it does.
+type importEntry struct {
+	opml.Feed
+	url    string
+	action string
+	reason string
+	// existing is the feed already in the database for the follow action.
+	existing database.Feed
+}
+
+func HandlerImport(s *State, cmd Command, user database.User) error {
+	if cmd.Args[0] != "opml" {
+		return usageErrorf(cmd.Name, "unknown format %v, expected opml", cmd.Args[0])
+	}
+	var in io.Reader = os.Stdin
+	if path := cmd.Args[1]; path != "-" {
+		f, err := os.Open(path)
+		if err != nil {
+			return fmt.Errorf("error opening %v: %v", path, err)
+		}
+		defer f.Close()
+		in = f
+	}
+	doc, err := opml.Parse(in)
+	if err != nil {
+		return err
+	}
+
+	entries, err := planImport(s, user, doc.Feeds())
+	if err != nil {
+		return err
+	}
+	if cmd.Bool("validate") {
+		validateImport(s, entries, cmd.Int("concurrency"))
+	}
+
+	dryRun := cmd.Bool("dry-run")
+	folders := map[string]uuid.NullUUID{}
+	counts := map[string]int{}
+	for i := range entries {
+		entry := &entries[i]
+		if !dryRun && (entry.action == importAdd || entry.action == importFollow) {
+			err := s.DB.InTx(context.Background(), func(tx database.Store) error {
+				return applyImport(s, tx, user, entry, folders)
+			})
+			if err != nil {
+				entry.action, entry.reason = importFail, err.Error()
+				// a folder created for the entry went with its transaction
+				delete(folders, entry.Folder)
+			}
+		}
+		counts[entry.action]++
+		printImport(*entry, dryRun)
+	}
+
+	verb := "imported"
+	if dryRun {
+		verb = "would import"
+	}
+	fmt.Printf("\n%v %v of %v feeds: %v added, %v followed, %v skipped, %v failed\n",
+		verb, counts[importAdd]+counts[importFollow], len(entries), counts[importAdd], counts[importFollow], counts[importSkip], counts[importFail])
+	if counts[importFail] > 0 {
+		return fmt.Errorf("%v feeds could not be imported", counts[importFail])
+	}
+	return nil
+}
+
+// planImport decides the action of every feed: add the feeds that are not in
+// the database, follow the ones that are, and skip the ones already followed
+// or listed twice, compared by normalized url.
+func planImport(s *State, user database.User, feeds []opml.Feed) ([]importEntry, error) {
+	follows, err := s.DB.GetFeedFollowsForUser(context.Background(), uuid.NullUUID{UUID: user.ID, Valid: true})
+	if err != nil {
+		return nil, fmt.Errorf("error listing follows: %v", err)
+	}
+	followed := map[uuid.UUID]bool{}
+	for _, follow := range follows {
+		followed[follow.FeedID.UUID] = true
+	}
+
+	seen := map[string]bool{}
+	var entries []importEntry
+	for _, feed := range feeds {
+		entry := importEntry{Feed: feed, url: feed.XMLURL}
+		entries = append(entries, entry)
+		current := &entries[len(entries)-1]
+
+		url, err := s.URLs().Normalize(feed.XMLURL)
+		if err != nil {
+			current.action, current.reason = importFail, fmt.Sprintf("invalid url: %v", err)
+			continue
+		}
+		current.url = url
+		key, err := s.URLs().Key(url)
+		if err != nil {
+			current.action, current.reason = importFail, fmt.Sprintf("invalid url: %v", err)
+			continue
+		}
+		if seen[key] {
+			current.action, current.reason = importSkip, "listed twice"
+			continue
+		}
+		seen[key] = true
+
+		existing, err := s.DB.GetFeedByURLKey(context.Background(), sql.NullString{String: key, Valid: true})
+		switch {
+		case err == nil && followed[existing.ID]:
+			current.action, current.reason = importSkip, "already followed"
+		case err == nil:
+			current.action, current.existing = importFollow, existing
+		case err == sql.ErrNoRows:
+			current.action = importAdd
+		default:
+			return nil, fmt.Errorf("error looking up %v: %v", url, err)
+		}
+	}
+	return entries, nil
+}
+
+// validateImport fetches the feeds that would be added or followed,
+// concurrency at a time, and fails the ones that do not parse.
+func validateImport(s *State, entries []importEntry, concurrency int) {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	slots := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for i := range entries {
+		entry := &entries[i]
+		if entry.action != importAdd && entry.action != importFollow {
+			continue
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			slots <- struct{}{}
+			defer func() { <-slots }()
+			feed := database.Feed{Url: sql.NullString{String: entry.url, Valid: true}, Kind: feedKindRSS}
+			if _, _, err := fetchFeed(context.Background(), s, feed); err != nil {
+				entry.action, entry.reason = importFail, fmt.Sprintf("not a valid feed: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+// applyImport adds or follows the feed of entry on db and files it in its
+// folder, folders caches the folders already looked up or created.
+func applyImport(s *State, db database.Store, user database.User, entry *importEntry, folders map[string]uuid.NullUUID) error {
+	var feed database.Feed
+	if entry.action == importAdd {
+		name := entry.Title
+		if name == "" {
+			name = entry.url
+		}
+		created, _, err := createFeed(s, db, user, name, entry.url, feedKindRSS)
+		if err != nil {
+			return err
+		}
+		feed = created
+		if entry.HTMLURL != "" {
+			err = db.SetFeedSiteURL(context.Background(), database.SetFeedSiteURLParams{
+				ID:        feed.ID,
+				SiteUrl:   sql.NullString{String: entry.HTMLURL, Valid: true},
+				UpdatedAt: sql.NullTime{Time: time.Now(), Valid: true},
+			})
+			if err != nil {
+				return fmt.Errorf("error saving site url: %v", err)
+			}
+		}
+	} else {
+		feed = entry.existing
+		_, err := db.CreateFeedFollow(context.Background(), database.CreateFeedFollowParams{
+			ID:        uuid.New(),
+			CreatedAt: sql.NullTime{Time: time.Now(), Valid: true},
+			UpdatedAt: sql.NullTime{Time: time.Now(), Valid: true},
+			UserID:    uuid.NullUUID{UUID: user.ID, Valid: true},
+			FeedID:    uuid.NullUUID{UUID: feed.ID, Valid: true},
+		})
+		if err != nil {
+			return fmt.Errorf("error following: %v", err)
+		}
+	}
+
+	if entry.Folder == "" || entry.Folder == unfiledFolder {
+		return nil
+	}
+	folderID, ok := folders[entry.Folder]
+	if !ok {
+		folder, err := db.GetFolderByName(context.Background(), database.GetFolderByNameParams{UserID: user.ID, Name: entry.Folder})
+		if err == sql.ErrNoRows {
+			folder, err = db.CreateFolder(context.Background(), database.CreateFolderParams{
+				ID:        uuid.New(),
+				CreatedAt: sql.NullTime{Time: time.Now(), Valid: true},
+				UpdatedAt: sql.NullTime{Time: time.Now(), Valid: true},
+				UserID:    user.ID,
+				Name:      entry.Folder,
+			})
+		}
+		if err != nil {
+			return fmt.Errorf("error creating folder %v: %v", entry.Folder, err)
+		}
+		folderID = uuid.NullUUID{UUID: folder.ID, Valid: true}
+		folders[entry.Folder] = folderID
+	}
+	_, err := db.SetFeedFollowFolder(context.Background(), database.SetFeedFollowFolderParams{
+		UserID:    uuid.NullUUID{UUID: user.ID, Valid: true},
+		FeedID:    uuid.NullUUID{UUID: feed.ID, Valid: true},
+		FolderID:  folderID,
+		UpdatedAt: sql.NullTime{Time: time.Now(), Valid: true},
+	})
+	if err != nil {
+		return fmt.Errorf("error moving to folder %v: %v", entry.Folder, err)
+	}
+	return nil
+}
+
+func printImport(entry importEntry, dryRun bool) {
+	labels := map[string]string{importAdd: "added", importFollow: "followed", importSkip: "skipped", importFail: "failed"}
+	if dryRun {
+		labels[importAdd], labels[importFollow] = "add", "follow"
+	}
+	fmt.Printf("%-8v %v (%v)", labels[entry.action], entry.Title, entry.url)
+	if entry.Folder != "" && entry.action != importSkip && entry.action != importFail {
+		fmt.Printf(" in %v", entry.Folder)
+	}
+	if entry.reason != "" {
+		fmt.Printf(": %v", entry.reason)
+	}
+	fmt.Printf("\n")
+}
+
+func exportFlags(f *flag.FlagSet) {
+	f.String("user", "", "export the follows of this user instead of the logged in one")
+}
+
+// HandlerExport writes the follows of a user as an opml document to stdout.
+func HandlerExport(s *State, cmd Command) error {
+	if cmd.Args[0] != "opml" {
+		return usageErrorf(cmd.Name, "unknown format %v, expected opml", cmd.Args[0])
+	}
+	name := s.State.CurrentUserName
+	if cmd.IsSet("user") {
+		name = cmd.String("user")
+	}
+	user, err := s.DB.GetUser(context.Background(), name)
+	if err != nil {
+		return fmt.Errorf("user %v does not exist", name)
+	}
+	follows, err := s.DB.GetFeedFollowsForUser(context.Background(), uuid.NullUUID{UUID: user.ID, Valid: true})
+	if err != nil {
+		return fmt.Errorf("error listing follows: %v", err)
+	}
+
+	var feeds []opml.Feed
+	for _, follow := range follows {
+		feeds = append(feeds, opml.Feed{
+			Title:   follow.FeedName.String,
+			XMLURL:  follow.FeedUrl.String,
+			HTMLURL: follow.FeedSiteUrl.String,
+			Folder:  follow.FolderName.String,
+		})
+	}
+	doc := opml.FromFeeds(fmt.Sprintf("gator subscriptions of %v", user.Name), time.Now(), feeds)
+	return doc.Write(os.Stdout)
+}
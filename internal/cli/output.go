@@ -0,0 +1,165 @@
+package cli
+
+import (
+	"database/sql"
+	"flag"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/o0n1x/gator/internal/database"
+	"github.com/o0n1x/gator/internal/output"
+)
+
+// The records below are the documented schema of the --output formats,
+// rename or remove a field only with a note in the README.
+
+type userRecord struct {
+	Name      string     `json:"name"`
+	Current   bool       `json:"current"`
+	Admin     bool       `json:"admin"`
+	CreatedAt *time.Time `json:"created_at"`
+}
+
+type feedRecord struct {
+	Name          string     `json:"name"`
+	URL           string     `json:"url"`
+	Kind          string     `json:"kind"`
+	User          string     `json:"user"`
+	DisplayName   string     `json:"display_name"`
+	LastFetchedAt *time.Time `json:"last_fetched_at"`
+}
+
+type followRecord struct {
+	// Type is feed, or search for saved searches which only have Name, Query and Unread.
+	Type        string `json:"type"`
+	Name        string `json:"name"`
+	URL         string `json:"url"`
+	Query       string `json:"query"`
+	DisplayName string `json:"display_name"`
+	Folder      string `json:"folder"`
+	Unread      int64  `json:"unread"`
+	Hidden      bool   `json:"hidden"`
+	Notify      bool   `json:"notify"`
+	FullText    bool   `json:"full_text"`
+}
+
+type postRecord struct {
+	ID          string     `json:"id"`
+	Title       string     `json:"title"`
+	URL         string     `json:"url"`
+	Feed        string     `json:"feed"`
+	Author      string     `json:"author"`
+	PublishedAt *time.Time `json:"published_at"`
+	Read        bool       `json:"read"`
+	Description string     `json:"description"`
+	// Cursor continues browse after this post with --after.
+	Cursor string `json:"cursor"`
+}
+
+type searchRecord struct {
+	ID          string     `json:"id"`
+	Title       string     `json:"title"`
+	URL         string     `json:"url"`
+	Feed        string     `json:"feed"`
+	PublishedAt *time.Time `json:"published_at"`
+	Rank        float32    `json:"rank"`
+	Snippet     string     `json:"snippet"`
+}
+
+type starredRecord struct {
+	ID          string     `json:"id"`
+	Title       string     `json:"title"`
+	URL         string     `json:"url"`
+	Feed        string     `json:"feed"`
+	PublishedAt *time.Time `json:"published_at"`
+	StarredAt   time.Time  `json:"starred_at"`
+}
+
+type laterRecord struct {
+	// Position is 1 for the post later next reads.
+	Position    int        `json:"position"`
+	ID          string     `json:"id"`
+	Title       string     `json:"title"`
+	URL         string     `json:"url"`
+	Feed        string     `json:"feed"`
+	PublishedAt *time.Time `json:"published_at"`
+}
+
+type folderRecord struct {
+	Name      string     `json:"name"`
+	CreatedAt *time.Time `json:"created_at"`
+}
+
+type savedSearchRecord struct {
+	Name   string `json:"name"`
+	Query  string `json:"query"`
+	Unread int64  `json:"unread"`
+}
+
+func outputFlags(f *flag.FlagSet) {
+	choice(f, "output", string(output.Text), "output format", output.Formats...)
+	f.String("fields", "", "comma separated fields to output and their order, all by default")
+}
+
+// outputFormat returns the format asked for with --output, text by default.
+func outputFormat(cmd Command) output.Format {
+	format := output.Format(cmd.String("output"))
+	if format == "" {
+		return output.Text
+	}
+	if format != output.Text {
+		// colors are for people, fatih/color already turns them off for pipes and NO_COLOR
+		color.NoColor = true
+	}
+	return format
+}
+
+// writeRecords writes records in the format and fields asked for by cmd.
+func writeRecords(cmd Command, records interface{}) error {
+	var fields []string
+	if list := cmd.String("fields"); list != "" {
+		fields = strings.Split(list, ",")
+	}
+	err := output.Write(os.Stdout, outputFormat(cmd), records, fields)
+	if err != nil {
+		return usageErrorf(cmd.Name, "%v", err)
+	}
+	return nil
+}
+
+// listOnly refuses --output and --fields for the subcommands other than list
+// of later, folder and savedsearch, they print no list.
+func listOnly(cmd Command) error {
+	if len(cmd.Args) == 0 || cmd.Args[0] == "list" {
+		return nil
+	}
+	for _, name := range []string{"output", "fields"} {
+		if cmd.IsSet(name) {
+			return usageErrorf(cmd.Name, "--%v only applies to %v list", name, cmd.Name)
+		}
+	}
+	return nil
+}
+
+// timePtr is nil for a null time, so it is written as null or an empty cell.
+func timePtr(t sql.NullTime) *time.Time {
+	if !t.Valid {
+		return nil
+	}
+	return &t.Time
+}
+
+func newPostRecord(post database.Post, feedName string, read bool) postRecord {
+	return postRecord{
+		ID:          post.ID.String(),
+		Title:       post.Title.String,
+		URL:         post.Url.String,
+		Feed:        feedName,
+		Author:      post.Author.String,
+		PublishedAt: timePtr(post.PublishedAt),
+		Read:        read,
+		Description: post.Description.String,
+	}
+}
@@ -0,0 +1,77 @@
+package cli
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"time"
+
+	"github.com/o0n1x/gator/internal/database"
+)
+
+var dateArgLayouts = []string{
+	time.RFC3339,
+	"2006-01-02 15:04",
+	"2006-01-02",
+}
+
+func markReadFlags(f *flag.FlagSet) {
+	f.String("feed", "", "url of the feed whose posts are marked read")
+	f.String("before", "", "only mark posts published before this date (YYYY-MM-DD or RFC3339)")
+}
+
+func HandlerMarkRead(s *State, cmd Command, user database.User) error {
+	feedURL := cmd.String("feed")
+	if feedURL == "" {
+		return usageErrorf(cmd.Name, "expected flag --feed but was not found, use mark-all-read for every feed")
+	}
+
+	var err error
+	before := time.Now().UTC()
+	if beforeArg := cmd.String("before"); beforeArg != "" {
+		before, err = parseDateArg(beforeArg)
+		if err != nil {
+			return err
+		}
+	}
+
+	feed, err := feedByURL(s, feedURL)
+	if err != nil {
+		return err
+	}
+
+	marked, err := s.DB.MarkFeedPostsReadBefore(context.Background(), database.MarkFeedPostsReadBeforeParams{
+		UserID: user.ID,
+		ReadAt: time.Now().UTC(),
+		FeedID: feed.ID,
+		Before: before,
+	})
+	if err != nil {
+		return fmt.Errorf("error marking posts of %v as read: %v", feed.Name.String, err)
+	}
+	fmt.Printf("marked %v posts of %v as read\n", marked, feed.Name.String)
+	return nil
+}
+
+func HandlerMarkAllRead(s *State, cmd Command, user database.User) error {
+	marked, err := s.DB.MarkAllPostsRead(context.Background(), database.MarkAllPostsReadParams{
+		ReadAt: time.Now().UTC(),
+		UserID: user.ID,
+	})
+	if err != nil {
+		return fmt.Errorf("error marking posts as read: %v", err)
+	}
+	fmt.Printf("marked %v posts as read\n", marked)
+	return nil
+}
+
+// parseDateArg parses a date given on the command line as UTC.
+func parseDateArg(s string) (time.Time, error) {
+	for _, layout := range dateArgLayouts {
+		t, err := time.Parse(layout, s)
+		if err == nil {
+			return t.UTC(), nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid date %v, expected YYYY-MM-DD or RFC3339", s)
+}
@@ -0,0 +1,281 @@
+package cli
+
+import (
+	"flag"
+	"os"
+)
+
+// NewCommands returns the registry of every gator command.
+func NewCommands() *Commands {
+	c := &Commands{Commands: map[string]Spec{}, SchemaCheck: checkSchema}
+
+	c.Register(Spec{
+		Name:        "help",
+		Description: "list the commands, or show the flags and arguments of one",
+		Args:        []Arg{{Name: "command", Optional: true}},
+		AnySchema:   true,
+		Handler: func(s *State, cmd Command) error {
+			if len(cmd.Args) == 0 {
+				c.PrintHelp(os.Stdout)
+				return nil
+			}
+			spec, ok := c.Commands[cmd.Args[0]]
+			if !ok {
+				return c.Run(s, Command{Name: cmd.Args[0]})
+			}
+			spec.PrintHelp(os.Stdout)
+			return nil
+		},
+	})
+	c.Register(Spec{
+		Name:        "login",
+		Description: "login as user",
+		Args:        []Arg{{Name: "username"}},
+		Handler:     HandlerLogin,
+	})
+	c.Register(Spec{
+		Name:        "register",
+		Description: "register user and login",
+		Args:        []Arg{{Name: "username"}},
+		Handler:     HandlerRegister,
+	})
+	c.Register(Spec{
+		Name:        "reset",
+		Description: "delete every user, feed and post",
+		Handler:     HandlerReset,
+	})
+	c.Register(Spec{
+		Name:        "users",
+		Description: "list registered users",
+		Listing:     true,
+		Handler:     HandlerUsers,
+	})
+	c.Register(Spec{
+		Name:        "unregister",
+		Description: "delete your user, or another one as an admin, feeds others follow stay without an owner",
+		Args:        []Arg{{Name: "username", Optional: true}},
+		Handler:     MiddlewareLoggedIn(HandlerUnregister),
+	})
+	c.Register(Spec{
+		Name:        "admin",
+		Description: "grant or revoke the admin rights of a user, admins manage every feed",
+		Args:        []Arg{{Name: "grant|revoke"}, {Name: "username"}},
+		Handler:     MiddlewareLoggedIn(HandlerAdmin),
+	})
+	c.Register(Spec{
+		Name:        "agg",
+		Description: "fetch the feeds every time_between_reqs, like 1m or 30s",
+		Args:        []Arg{{Name: "time_between_reqs"}},
+		Handler:     HandlerAgg,
+	})
+	c.Register(Spec{
+		Name:        "addfeed",
+		Description: "add a feed and follow it",
+		Args:        []Arg{{Name: "name"}, {Name: "url"}},
+		Handler:     MiddlewareLoggedIn(HandlerAddFeed),
+	})
+	c.Register(Spec{
+		Name:        "addscrape",
+		Description: "add a feed scraped from a web page with css selectors and follow it",
+		Args:        []Arg{{Name: "name", Optional: true}, {Name: "url"}},
+		Flags:       addScrapeFlags,
+		Handler:     MiddlewareLoggedIn(HandlerAddScrape),
+	})
+	c.Register(Spec{
+		Name:        "addwatch",
+		Description: "add a feed that posts the changes of a web page and follow it",
+		Args:        []Arg{{Name: "name"}, {Name: "url"}},
+		Flags:       addWatchFlags,
+		Handler:     MiddlewareLoggedIn(HandlerAddWatch),
+	})
+	c.Register(Spec{
+		Name:        "feeds",
+		Description: "list every feed",
+		Listing:     true,
+		Handler:     HandlerFeeds,
+	})
+	c.Register(Spec{
+		Name:        "feed",
+		Description: "delete, rename, move or give away a feed for every follower, as its owner or an admin",
+		Args:        []Arg{{Name: "rm|rename|set-url|chown"}, {Name: "url"}, {Name: "value", Optional: true}},
+		Handler:     MiddlewareLoggedIn(HandlerFeed),
+	})
+	c.Register(Spec{
+		Name:        "follow",
+		Description: "follow a feed",
+		Args:        []Arg{{Name: "url"}},
+		Handler:     MiddlewareLoggedIn(HandlerFollow),
+	})
+	c.Register(Spec{
+		Name:        "following",
+		Description: "list followed feeds and saved searches with their unread count",
+		Flags: func(f *flag.FlagSet) {
+			f.Bool("tree", false, "group the feeds by folder")
+		},
+		Listing: true,
+		Handler: MiddlewareLoggedIn(HandlerFollowing),
+	})
+	c.Register(Spec{
+		Name:        "unfollow",
+		Description: "unfollow a feed",
+		Args:        []Arg{{Name: "url"}},
+		Handler:     MiddlewareLoggedIn(HandlerUnfollow),
+	})
+	c.Register(Spec{
+		Name:        "browse",
+		Description: "list the latest unread posts of followed feeds",
+		Args:        []Arg{{Name: "limit", Optional: true}},
+		Flags:       browseFlags,
+		Listing:     true,
+		Handler:     MiddlewareLoggedIn(HandlerBrowse),
+	})
+	c.Register(Spec{
+		Name:        "read",
+		Description: "read a post in the terminal and mark it read, an id prefix is enough",
+		Args:        []Arg{{Name: "post"}},
+		Handler:     MiddlewareLoggedIn(HandlerRead),
+	})
+	c.Register(Spec{
+		Name:        "mark-read",
+		Description: "mark the posts of a feed as read",
+		Flags:       markReadFlags,
+		Handler:     MiddlewareLoggedIn(HandlerMarkRead),
+	})
+	c.Register(Spec{
+		Name:        "mark-all-read",
+		Description: "mark every post of followed feeds as read",
+		Handler:     MiddlewareLoggedIn(HandlerMarkAllRead),
+	})
+	c.Register(Spec{
+		Name:        "star",
+		Description: "star a post, starred posts are kept when their feed is removed",
+		Args:        []Arg{{Name: "post"}},
+		Handler:     MiddlewareLoggedIn(HandlerStar),
+	})
+	c.Register(Spec{
+		Name:        "unstar",
+		Description: "remove the star of a post",
+		Args:        []Arg{{Name: "post"}},
+		Handler:     MiddlewareLoggedIn(HandlerUnstar),
+	})
+	c.Register(Spec{
+		Name:        "starred",
+		Description: "list starred posts",
+		Listing:     true,
+		Handler:     MiddlewareLoggedIn(HandlerStarred),
+	})
+	c.Register(Spec{
+		Name:        "later",
+		Description: "queue posts to read later, next reads the first one and done removes it",
+		Args:        []Arg{{Name: "add|list|next|done"}, {Name: "post", Optional: true}},
+		Listing:     true,
+		Handler:     MiddlewareLoggedIn(HandlerLater),
+	})
+	c.Register(Spec{
+		Name:        "folder",
+		Description: "organize followed feeds in folders, - is the unfiled folder",
+		Args:        []Arg{{Name: "add|rename|rm|list|move"}, {Name: "args", Optional: true, Variadic: true}},
+		Listing:     true,
+		Handler:     MiddlewareLoggedIn(HandlerFolder),
+	})
+	c.Register(Spec{
+		Name:        "sub",
+		Description: "show and change your own settings of a followed feed",
+		Args:        []Arg{{Name: "show|set"}, {Name: "args", Optional: true, Variadic: true}},
+		Handler:     MiddlewareLoggedIn(HandlerSubscription),
+	})
+	c.Register(Spec{
+		Name:        "search",
+		Description: `full text search of posts, supports "exact phrases", or and -excluded words`,
+		Args:        []Arg{{Name: "query", Variadic: true}},
+		Flags:       searchFlags,
+		Listing:     true,
+		Handler:     MiddlewareLoggedIn(HandlerSearch),
+	})
+	c.Register(Spec{
+		Name:        "savedsearch",
+		Description: "save a search query under a name to browse it like a feed",
+		Args:        []Arg{{Name: "add|list|rm"}, {Name: "args", Optional: true, Variadic: true}},
+		Listing:     true,
+		Handler:     MiddlewareLoggedIn(HandlerSavedSearch),
+	})
+	c.Register(Spec{
+		Name:        "tui",
+		Description: "read posts in a full screen terminal reader",
+		Handler:     MiddlewareLoggedIn(HandlerTUI),
+	})
+	c.Register(Spec{
+		Name:        "import",
+		Description: "import the feeds of an opml file and follow them, - reads stdin",
+		Args:        []Arg{{Name: "opml"}, {Name: "file"}},
+		Flags:       importFlags,
+		Handler:     MiddlewareLoggedIn(HandlerImport),
+	})
+	c.Register(Spec{
+		Name:        "export",
+		Description: "write the followed feeds as an opml document, like gator export opml > feeds.opml",
+		Args:        []Arg{{Name: "opml"}},
+		Flags:       exportFlags,
+		Handler:     HandlerExport,
+	})
+	c.Register(Spec{
+		Name:        "backup",
+		Description: "write every user, feed, post and setting to a compressed archive, - writes to stdout",
+		Args:        []Arg{{Name: "file"}},
+		AnySchema:   true,
+		Handler:     HandlerBackup,
+	})
+	c.Register(Spec{
+		Name:        "restore",
+		Description: "restore a backup into this database, existing rows are kept unless --policy says otherwise",
+		Args:        []Arg{{Name: "file"}},
+		Flags:       restoreFlags,
+		Handler:     HandlerRestore,
+	})
+	c.Register(Spec{
+		Name:        "migrate",
+		Description: "apply or roll back the database migrations built into gator",
+		Args:        []Arg{{Name: "up|down|status|redo"}},
+		Flags:       migrateFlags,
+		AnySchema:   true,
+		Handler:     HandlerMigrate,
+	})
+	c.Register(Spec{
+		Name:        "shell",
+		Description: "run commands from a prompt with history and tab completion, keeping the connection",
+		AnySchema:   true,
+		Handler: func(s *State, cmd Command) error {
+			return runShell(c, s, cmd)
+		},
+	})
+	c.Register(Spec{
+		Name:        "batch",
+		Description: "run the commands of a script file, one per line, - reads stdin",
+		Args:        []Arg{{Name: "file"}},
+		Flags:       batchFlags,
+		AnySchema:   true,
+		Handler: func(s *State, cmd Command) error {
+			return runBatch(c, s, cmd)
+		},
+	})
+	c.Register(Spec{
+		Name:        "completion",
+		Description: "print the completion script of a shell, like source <(gator completion bash)",
+		Args:        []Arg{{Name: "bash|zsh|fish"}},
+		AnySchema:   true,
+		Handler: func(s *State, cmd Command) error {
+			return handlerCompletion(c, s, cmd)
+		},
+	})
+	c.Register(Spec{
+		Name:        "__complete",
+		Description: "print the completions of the words after --, used by the completion scripts",
+		Args:        []Arg{{Name: "words", Optional: true, Variadic: true}},
+		Hidden:      true,
+		AnySchema:   true,
+		Handler: func(s *State, cmd Command) error {
+			return handlerComplete(c, s, cmd)
+		},
+	})
+	return c
+}
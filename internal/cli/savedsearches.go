@@ -0,0 +1,90 @@
+package cli
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/o0n1x/gator/internal/database"
+	"github.com/o0n1x/gator/internal/output"
+)
+
+func HandlerSavedSearch(s *State, cmd Command, user database.User) error {
+	if len(cmd.Args) < 1 {
+		return usageErrorf(cmd.Name, "expected arg 'add|list|rm' but was not found")
+	}
+	if err := listOnly(cmd); err != nil {
+		return err
+	}
+	switch cmd.Args[0] {
+	case "add":
+		if len(cmd.Args) < 3 {
+			return usageErrorf(cmd.Name, "expected arg 'name' and 'query' but was not found")
+		}
+		query := strings.TrimSpace(strings.Join(cmd.Args[2:], " "))
+		search, err := s.DB.CreateSavedSearch(context.Background(), database.CreateSavedSearchParams{
+			ID:        uuid.New(),
+			CreatedAt: sql.NullTime{Time: time.Now(), Valid: true},
+			UpdatedAt: sql.NullTime{Time: time.Now(), Valid: true},
+			UserID:    user.ID,
+			Name:      cmd.Args[1],
+			Query:     query,
+		})
+		if err != nil {
+			return fmt.Errorf("error saving search %v: %v", cmd.Args[1], err)
+		}
+		fmt.Printf("saved search %v for %q, browse it with browse --saved %v\n", search.Name, search.Query, search.Name)
+		return nil
+
+	case "list":
+		searches, err := s.DB.GetSavedSearchesForUser(context.Background(), user.ID)
+		if err != nil {
+			return fmt.Errorf("error listing saved searches: %v", err)
+		}
+		if outputFormat(cmd) != output.Text {
+			var records []savedSearchRecord
+			for _, search := range searches {
+				records = append(records, savedSearchRecord{Name: search.Name, Query: search.Query, Unread: search.Unread})
+			}
+			return writeRecords(cmd, records)
+		}
+		for _, search := range searches {
+			fmt.Printf("* %v (%v unread)\n  Query: %v\n", search.Name, search.Unread, search.Query)
+		}
+		return nil
+
+	case "rm":
+		if len(cmd.Args) < 2 {
+			return usageErrorf(cmd.Name, "expected arg 'name' but was not found")
+		}
+		deleted, err := s.DB.DeleteSavedSearch(context.Background(), database.DeleteSavedSearchParams{
+			UserID: user.ID,
+			Name:   cmd.Args[1],
+		})
+		if err != nil {
+			return fmt.Errorf("error deleting saved search %v: %v", cmd.Args[1], err)
+		}
+		if deleted == 0 {
+			return fmt.Errorf("saved search %v does not exist", cmd.Args[1])
+		}
+		fmt.Printf("deleted saved search %v\n", cmd.Args[1])
+		return nil
+
+	default:
+		return usageErrorf(cmd.Name, "unknown subcommand %v, expected add, list or rm", cmd.Args[0])
+	}
+}
+
+func savedSearchByName(s *State, user database.User, name string) (database.SavedSearch, error) {
+	search, err := s.DB.GetSavedSearchByName(context.Background(), database.GetSavedSearchByNameParams{
+		UserID: user.ID,
+		Name:   name,
+	})
+	if err != nil {
+		return database.SavedSearch{}, fmt.Errorf("saved search %v does not exist", name)
+	}
+	return search, nil
+}
@@ -0,0 +1,100 @@
+package cli
+
+import (
+	"context"
+	"database/sql"
+	"flag"
+	"fmt"
+
+	"github.com/fatih/color"
+	"github.com/o0n1x/gator/internal/database"
+	"github.com/o0n1x/gator/internal/scrape"
+)
+
+func addScrapeFlags(f *flag.FlagSet) {
+	f.String("item", "", "css selector of every item container")
+	f.String("title", "", "css selector of the title inside an item")
+	f.String("link", "", "css selector of the link inside an item")
+	f.String("date", "", "css selector of the date inside an item")
+	f.String("date-format", "", "go time layout of the date")
+	f.String("summary", "", "css selector of the summary inside an item")
+	f.Bool("dry-run", false, "preview the extracted items without saving the feed, only the url is needed")
+}
+
+func HandlerAddScrape(s *State, cmd Command, user database.User) error {
+	sel := scrape.Selectors{
+		Item:       cmd.String("item"),
+		Title:      cmd.String("title"),
+		Link:       cmd.String("link"),
+		Date:       cmd.String("date"),
+		DateFormat: cmd.String("date-format"),
+		Summary:    cmd.String("summary"),
+	}
+	if err := sel.Validate(); err != nil {
+		return usageErrorf(cmd.Name, "expected flags --item, --title and --link but were not found")
+	}
+
+	if cmd.Bool("dry-run") {
+		url := cmd.Args[len(cmd.Args)-1]
+		items, err := scrapeItems(context.Background(), s, url, sel)
+		if err != nil {
+			return fmt.Errorf("error scraping %v: %v", url, err)
+		}
+		for _, item := range items {
+			fmt.Printf("   -------------------- \n")
+			fmt.Printf(color.YellowString("Title: %v"), "")
+			fmt.Printf(color.GreenString("%v\n"), item.Title)
+			fmt.Printf("	Published Date: %v\n", item.PubDate)
+			fmt.Printf("	URL: %v\n", item.Link)
+			fmt.Printf("	Description: %v\n", item.Description)
+		}
+		fmt.Printf("   --- %v items extracted, nothing saved --- \n", len(items))
+		return nil
+	}
+
+	if len(cmd.Args) < 2 {
+		return usageErrorf(cmd.Name, "expected arg 'name' and 'url' but was not found")
+	}
+	name := cmd.Args[0]
+	url := cmd.Args[1]
+
+	var feed database.Feed
+	var feedfollow database.CreateFeedFollowRow
+	err := s.DB.InTx(context.Background(), func(tx database.Store) error {
+		var err error
+		feed, feedfollow, err = createFeed(s, tx, user, name, url, feedKindScrape)
+		if err != nil {
+			return err
+		}
+		_, err = tx.CreateFeedScraper(context.Background(), database.CreateFeedScraperParams{
+			FeedID:          feed.ID,
+			ItemSelector:    sel.Item,
+			TitleSelector:   sel.Title,
+			LinkSelector:    sel.Link,
+			DateSelector:    sql.NullString{String: sel.Date, Valid: sel.Date != ""},
+			DateFormat:      sql.NullString{String: sel.DateFormat, Valid: sel.DateFormat != ""},
+			SummarySelector: sql.NullString{String: sel.Summary, Valid: sel.Summary != ""},
+		})
+		if err != nil {
+			return fmt.Errorf("error saving selectors of %v: %v", name, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Created Scrape Feed:\nName: %v\nURL: %v\nUsername: %v\n", feed.Name.String, feed.Url.String, user.Name)
+	fmt.Printf("%v successfully followed %v\n", feedfollow.UserName, feedfollow.FeedName.String)
+	return nil
+}
+
+func scraperSelectors(scraper database.FeedScraper) scrape.Selectors {
+	return scrape.Selectors{
+		Item:       scraper.ItemSelector,
+		Title:      scraper.TitleSelector,
+		Link:       scraper.LinkSelector,
+		Date:       scraper.DateSelector.String,
+		DateFormat: scraper.DateFormat.String,
+		Summary:    scraper.SummarySelector.String,
+	}
+}
@@ -0,0 +1,126 @@
+package cli
+
+import (
+	"context"
+	"database/sql"
+	"flag"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/google/uuid"
+	"github.com/o0n1x/gator/internal/database"
+	"github.com/o0n1x/gator/internal/output"
+)
+
+const (
+	searchLimit = 10
+
+	// markers ts_headline puts around matched words, see searchposts.sql
+	snippetStart = "[["
+	snippetStop  = "]]"
+)
+
+func searchFlags(f *flag.FlagSet) {
+	f.String("feed", "", "only search the posts of this feed")
+	f.String("since", "", "only posts published since this time (date, 24h, 7d, today, yesterday)")
+	f.String("until", "", "only posts published before this time (date, 24h, 7d, today, yesterday)")
+	f.Bool("all", false, "search every feed instead of only followed ones")
+	f.Int("limit", searchLimit, "maximum number of results")
+}
+
+func HandlerSearch(s *State, cmd Command, user database.User) error {
+	query := strings.TrimSpace(strings.Join(cmd.Args, " "))
+	if query == "" {
+		return usageErrorf(cmd.Name, "expected arg 'query' but was not found")
+	}
+
+	limit := cmd.Int("limit")
+	if limit < 1 {
+		return usageErrorf(cmd.Name, "invalid limit %v, expected a number from 1", limit)
+	}
+
+	params := database.SearchPostsParams{
+		Query:    query,
+		UserID:   uuid.NullUUID{UUID: user.ID, Valid: true},
+		AllFeeds: cmd.Bool("all"),
+		MaxPosts: int32(limit),
+	}
+	if feedURL := cmd.String("feed"); feedURL != "" {
+		feed, err := feedByURL(s, feedURL)
+		if err != nil {
+			return err
+		}
+		params.FeedID = uuid.NullUUID{UUID: feed.ID, Valid: true}
+	}
+	now := time.Now()
+	if sinceArg := cmd.String("since"); sinceArg != "" {
+		since, err := parseTimeArg(sinceArg, now)
+		if err != nil {
+			return err
+		}
+		params.Since = sql.NullTime{Time: since, Valid: true}
+	}
+	if untilArg := cmd.String("until"); untilArg != "" {
+		until, err := parseTimeArg(untilArg, now)
+		if err != nil {
+			return err
+		}
+		params.Until = sql.NullTime{Time: until, Valid: true}
+	}
+
+	results, err := s.DB.SearchPosts(context.Background(), params)
+	if err != nil {
+		return fmt.Errorf("error searching posts: %v", err)
+	}
+	if outputFormat(cmd) != output.Text {
+		var records []searchRecord
+		for _, result := range results {
+			records = append(records, searchRecord{
+				ID:          result.Post.ID.String(),
+				Title:       result.Post.Title.String,
+				URL:         result.Post.Url.String,
+				Feed:        result.FeedName,
+				PublishedAt: timePtr(result.Post.PublishedAt),
+				Rank:        result.Rank,
+				Snippet:     plainSnippet(result.Snippet),
+			})
+		}
+		return writeRecords(cmd, records)
+	}
+	for _, result := range results {
+		printPostSummary(result.Post, result.FeedName)
+		fmt.Printf("	Rank: %.3f\n", result.Rank)
+		fmt.Printf("	%v\n", highlightSnippet(result.Snippet))
+	}
+	fmt.Printf("   --- %v results for %q --- \n", len(results), query)
+	return nil
+}
+
+// plainSnippet removes the match markers of a ts_headline snippet.
+func plainSnippet(snippet string) string {
+	snippet = strings.NewReplacer(snippetStart, "", snippetStop, "").Replace(snippet)
+	return strings.Join(strings.Fields(snippet), " ")
+}
+
+// highlightSnippet turns the match markers of a ts_headline snippet into color.
+func highlightSnippet(snippet string) string {
+	match := color.New(color.FgYellow, color.Bold).SprintFunc()
+	var b strings.Builder
+	for {
+		start := strings.Index(snippet, snippetStart)
+		if start < 0 {
+			break
+		}
+		stop := strings.Index(snippet[start:], snippetStop)
+		if stop < 0 {
+			break
+		}
+		b.WriteString(snippet[:start])
+		b.WriteString(match(snippet[start+len(snippetStart) : start+stop]))
+		snippet = snippet[start+stop+len(snippetStop):]
+	}
+	b.WriteString(snippet)
+	return strings.Join(strings.Fields(b.String()), " ")
+}
@@ -0,0 +1,199 @@
+package cli
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/peterh/liner"
+)
+
+const historyFileName = ".gator_history"
+
+// runShell reads commands from a prompt until exit or ctrl-d, every command
+// runs on the same State so the connection and the logged in user are kept.
+func runShell(c *Commands, s *State, cmd Command) error {
+	line := liner.NewLiner()
+	defer line.Close()
+	line.SetCtrlCAborts(true)
+	line.SetWordCompleter(func(input string, pos int) (string, []string, string) {
+		head := input[:pos]
+		start := strings.LastIndexAny(head, " \t") + 1
+		words := append(strings.Fields(head[:start]), head[start:])
+		var values []string
+		for _, candidate := range c.complete(words, completionValues(c, s)) {
+			values = append(values, candidate.Value)
+		}
+		return head[:start], values, input[pos:]
+	})
+
+	historyPath := ""
+	if home, err := os.UserHomeDir(); err == nil {
+		historyPath = filepath.Join(home, historyFileName)
+		if f, err := os.Open(historyPath); err == nil {
+			line.ReadHistory(f)
+			f.Close()
+		}
+	}
+
+	fmt.Println("gator shell, type help for the commands and exit or ctrl-d to leave")
+	for {
+		prompt := "gator> "
+		if s.State.CurrentUserName != "" {
+			prompt = s.State.CurrentUserName + "@gator> "
+		}
+		input, err := line.Prompt(prompt)
+		if errors.Is(err, liner.ErrPromptAborted) {
+			continue
+		}
+		if errors.Is(err, io.EOF) {
+			fmt.Println()
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("error reading command: %v", err)
+		}
+
+		words, err := splitLine(input)
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			continue
+		}
+		if len(words) == 0 {
+			continue
+		}
+		line.AppendHistory(input)
+		if words[0] == "exit" || words[0] == "quit" {
+			break
+		}
+		if words[0] == cmd.Name {
+			fmt.Printf("Error: %v\n", nestingError(cmd, words[0]))
+			continue
+		}
+		if err := c.Run(s, Command{Name: words[0], Args: words[1:]}); err != nil {
+			fmt.Printf("Error: %v\n", err)
+		}
+	}
+
+	if historyPath != "" {
+		if f, err := os.Create(historyPath); err == nil {
+			line.WriteHistory(f)
+			f.Close()
+		}
+	}
+	return nil
+}
+
+// runBatch runs the commands of a script, one per line, - reads the script
+// from stdin. Blank lines and lines starting with # are skipped.
+func runBatch(c *Commands, s *State, cmd Command) error {
+	var in io.Reader = os.Stdin
+	if path := cmd.Args[0]; path != "-" {
+		f, err := os.Open(path)
+		if err != nil {
+			return fmt.Errorf("error opening script: %v", err)
+		}
+		defer f.Close()
+		in = f
+	}
+
+	failed := 0
+	scanner := bufio.NewScanner(in)
+	for number := 1; scanner.Scan(); number++ {
+		words, err := splitLine(scanner.Text())
+		if err == nil && len(words) == 0 {
+			continue
+		}
+		if err == nil {
+			if cmd.Bool("echo") {
+				fmt.Printf("> %v\n", strings.Join(words, " "))
+			}
+			if words[0] == "shell" || words[0] == "batch" {
+				// a script running itself would never end
+				err = nestingError(cmd, words[0])
+			} else {
+				err = c.Run(s, Command{Name: words[0], Args: words[1:]})
+			}
+		}
+		if err == nil {
+			continue
+		}
+		if !cmd.Bool("continue") {
+			return fmt.Errorf("line %v: %w", number, err)
+		}
+		fmt.Printf("Error: line %v: %v\n", number, err)
+		failed++
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("error reading script: %v", err)
+	}
+	if failed > 0 {
+		return fmt.Errorf("%v commands of the script failed", failed)
+	}
+	return nil
+}
+
+// nestingError refuses to run name, the shell or a script, inside cmd.
+func nestingError(cmd Command, name string) error {
+	return usageErrorf(cmd.Name, "%v can not run inside %v", name, cmd.Name)
+}
+
+func batchFlags(f *flag.FlagSet) {
+	f.Bool("continue", false, "run the next commands when one fails, instead of stopping")
+	f.Bool("echo", false, "print every command before running it")
+}
+
+// splitLine splits a command line into words like a shell does: quotes
+// group words, a backslash escapes the next character and # starts a comment.
+func splitLine(line string) ([]string, error) {
+	var words []string
+	var word strings.Builder
+	inWord := false
+	var quote rune
+	escaped := false
+	for _, r := range line {
+		switch {
+		case escaped:
+			word.WriteRune(r)
+			escaped = false
+		case r == '\\' && quote != '\'':
+			escaped = true
+			inWord = true
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			} else {
+				word.WriteRune(r)
+			}
+		case r == '"' || r == '\'':
+			quote = r
+			inWord = true
+		case r == ' ' || r == '\t':
+			if inWord {
+				words = append(words, word.String())
+				word.Reset()
+				inWord = false
+			}
+		case r == '#' && !inWord:
+			return words, nil
+		default:
+			word.WriteRune(r)
+			inWord = true
+		}
+	}
+	if quote != 0 {
+		return nil, fmt.Errorf("unterminated %c quote", quote)
+	}
+	if escaped {
+		return nil, errors.New("line ends with a backslash")
+	}
+	if inWord {
+		words = append(words, word.String())
+	}
+	return words, nil
+}
@@ -0,0 +1,126 @@
+package cli
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestSplitLine(t *testing.T) {
+	cases := map[string]struct {
+		line  string
+		words []string
+		err   bool
+	}{
+		"words":         {line: "  follow\thttps://go.dev/feed ", words: []string{"follow", "https://go.dev/feed"}},
+		"double quotes": {line: `sub set url name "My Label"`, words: []string{"sub", "set", "url", "name", "My Label"}},
+		"single quotes": {line: `search '"exact phrase" go'`, words: []string{"search", `"exact phrase" go`}},
+		"escape":        {line: `folder add My\ Folder`, words: []string{"folder", "add", "My Folder"}},
+		"empty quotes":  {line: `sub set url name ""`, words: []string{"sub", "set", "url", "name", ""}},
+		"comment":       {line: "browse 5 # latest posts", words: []string{"browse", "5"}},
+		"hash in word":  {line: "follow https://go.dev/#feed", words: []string{"follow", "https://go.dev/#feed"}},
+		"blank":         {line: "   "},
+		"only comment":  {line: "# setup"},
+		"open quote":    {line: `search "go`, err: true},
+		"open escape":   {line: `search go\`, err: true},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			words, err := splitLine(tc.line)
+			if tc.err {
+				if err == nil {
+					t.Errorf("splitLine Failed, wanted an error for %q", tc.line)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("splitLine Failed %v", err)
+				return
+			}
+			if !reflect.DeepEqual(words, tc.words) {
+				t.Errorf("words Mismatch wanted: %q , got: %q", tc.words, words)
+			}
+		})
+	}
+}
+
+func TestRunBatch(t *testing.T) {
+	script := "# setup\nok one\n\nfail\nok two\n"
+	path := filepath.Join(t.TempDir(), "script.gator")
+	if err := os.WriteFile(path, []byte(script), 0600); err != nil {
+		t.Fatalf("WriteFile Failed %v", err)
+	}
+
+	cases := map[string]struct {
+		continueOnError bool
+		ran             []string
+	}{
+		"stops on error":     {ran: []string{"one", "fail"}},
+		"continues on error": {continueOnError: true, ran: []string{"one", "fail", "two"}},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var ran []string
+			c := &Commands{}
+			c.Register(Spec{Name: "ok", Args: []Arg{{Name: "name"}}, Handler: func(s *State, cmd Command) error {
+				ran = append(ran, cmd.Args[0])
+				return nil
+			}})
+			c.Register(Spec{Name: "fail", Handler: func(s *State, cmd Command) error {
+				ran = append(ran, "fail")
+				return errors.New("failed")
+			}})
+			spec := Spec{Name: "batch", Args: []Arg{{Name: "file"}}, Flags: batchFlags}
+			args := []string{path}
+			if tc.continueOnError {
+				args = append(args, "--continue")
+			}
+			cmd, err := spec.parse(args)
+			if err != nil {
+				t.Fatalf("parse Failed %v", err)
+			}
+
+			err = runBatch(c, &State{}, cmd)
+			if err == nil {
+				t.Errorf("runBatch Failed, wanted the error of the failed command")
+			}
+			if !reflect.DeepEqual(ran, tc.ran) {
+				t.Errorf("commands Mismatch wanted: %v , got: %v", tc.ran, ran)
+			}
+		})
+	}
+
+	t.Run("nested", func(t *testing.T) {
+		for _, line := range []string{"shell", "batch " + path} {
+			nested := filepath.Join(t.TempDir(), "nested.gator")
+			if err := os.WriteFile(nested, []byte(line+"\n"), 0600); err != nil {
+				t.Fatalf("WriteFile Failed %v", err)
+			}
+			ran := false
+			c := &Commands{}
+			for _, name := range []string{"shell", "batch"} {
+				c.Register(Spec{Name: name, Args: []Arg{{Name: "file", Optional: true}}, Handler: func(s *State, cmd Command) error {
+					ran = true
+					return nil
+				}})
+			}
+			err := runBatch(c, &State{}, Command{Name: "batch", Args: []string{nested}})
+			if code := ExitCode(err); code != exitUsage || ran {
+				t.Errorf("%v Mismatch wanted a usage error, got: %v and ran %v", line, err, ran)
+			}
+		}
+	})
+
+	t.Run("usage error exit code", func(t *testing.T) {
+		bad := filepath.Join(t.TempDir(), "bad.gator")
+		if err := os.WriteFile(bad, []byte("nope\n"), 0600); err != nil {
+			t.Fatalf("WriteFile Failed %v", err)
+		}
+		err := runBatch(&Commands{}, &State{}, Command{Name: "batch", Args: []string{bad}})
+		if code := ExitCode(err); code != exitUsage {
+			t.Errorf("ExitCode Mismatch wanted: %v , got: %v", exitUsage, code)
+		}
+	})
+}
@@ -0,0 +1,106 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	"github.com/o0n1x/gator/internal/database"
+	"github.com/o0n1x/gator/internal/rss"
+	"github.com/o0n1x/gator/internal/scrape"
+)
+
+// Source produces the items of a feed. It is selected by the feed kind, the document
+// behind the feed url is retrieved by the fetcher registered for the url scheme.
+type Source interface {
+	Items(ctx context.Context, feed database.Feed) (*rss.RSSFeed, error)
+}
+
+// changeSource is a Source whose items are the changes since a snapshot it
+// keeps. Changes also returns the new snapshot, nil when there is none, which
+// is saved along with the items so a change is never recorded without them.
+type changeSource interface {
+	Changes(ctx context.Context, feed database.Feed) (*rss.RSSFeed, *database.CreatePageSnapshotParams, error)
+}
+
+// sourceFor returns the source of feeds of the given kind.
+func sourceFor(s *State, kind string) (Source, error) {
+	switch kind {
+	case feedKindRSS, "":
+		return documentSource{s}, nil
+	case feedKindScrape:
+		return scrapeSource{s}, nil
+	case feedKindWatch:
+		return watchSource{s}, nil
+	default:
+		return nil, fmt.Errorf("unknown feed kind %v", kind)
+	}
+}
+
+func fetchFeed(ctx context.Context, s *State, feed database.Feed) (*rss.RSSFeed, *database.CreatePageSnapshotParams, error) {
+	src, err := sourceFor(s, feed.Kind)
+	if err != nil {
+		return nil, nil, err
+	}
+	if changes, ok := src.(changeSource); ok {
+		return changes.Changes(ctx, feed)
+	}
+	items, err := src.Items(ctx, feed)
+	return items, nil, err
+}
+
+// documentSource reads feeds whose url points to an rss or json feed document.
+type documentSource struct {
+	s *State
+}
+
+func (src documentSource) Items(ctx context.Context, feed database.Feed) (*rss.RSSFeed, error) {
+	data, err := src.s.Fetchers().Fetch(ctx, feed.Url.String)
+	if err != nil {
+		return nil, err
+	}
+	return rss.Parse(data)
+}
+
+// scrapeSource extracts items from an html page with the css selectors of the feed.
+type scrapeSource struct {
+	s *State
+}
+
+func (src scrapeSource) Items(ctx context.Context, feed database.Feed) (*rss.RSSFeed, error) {
+	scraper, err := src.s.DB.GetFeedScraper(ctx, feed.ID)
+	if err != nil {
+		return nil, fmt.Errorf("missing scrape selectors: %v", err)
+	}
+	items, err := scrapeItems(ctx, src.s, feed.Url.String, scraperSelectors(scraper))
+	if err != nil {
+		return nil, err
+	}
+	page := &rss.RSSFeed{}
+	page.Channel.Title = feed.Name.String
+	page.Channel.Link = feed.Url.String
+	page.Channel.Item = items
+	return page, nil
+}
+
+func scrapeItems(ctx context.Context, s *State, pageURL string, sel scrape.Selectors) ([]rss.RSSItem, error) {
+	page, err := s.Fetchers().Fetch(ctx, pageURL)
+	if err != nil {
+		return nil, err
+	}
+	return scrape.Items(bytes.NewReader(page), pageURL, sel)
+}
+
+// watchSource turns changes of a watched page into items, see watchFeed.
+type watchSource struct {
+	s *State
+}
+
+func (src watchSource) Items(ctx context.Context, feed database.Feed) (*rss.RSSFeed, error) {
+	page, _, err := watchFeed(ctx, src.s, feed)
+	return page, err
+}
+
+func (src watchSource) Changes(ctx context.Context, feed database.Feed) (*rss.RSSFeed, *database.CreatePageSnapshotParams, error) {
+	return watchFeed(ctx, src.s, feed)
+}
@@ -0,0 +1,199 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/o0n1x/gator/internal/database"
+	"github.com/o0n1x/gator/internal/output"
+)
+
+func HandlerStar(s *State, cmd Command, user database.User) error {
+	if len(cmd.Args) < 1 {
+		return usageErrorf(cmd.Name, "expected arg 'post' but was not found")
+	}
+	post, err := findPost(s, user, cmd.Args[0])
+	if err != nil {
+		return err
+	}
+	err = s.DB.StarPost(context.Background(), database.StarPostParams{
+		UserID:    user.ID,
+		PostID:    post.Post.ID,
+		StarredAt: time.Now().UTC(),
+	})
+	if err != nil {
+		return fmt.Errorf("error starring post %v: %v", post.Post.ID, err)
+	}
+	fmt.Printf("starred %v\n", post.Post.Title.String)
+	return nil
+}
+
+func HandlerUnstar(s *State, cmd Command, user database.User) error {
+	if len(cmd.Args) < 1 {
+		return usageErrorf(cmd.Name, "expected arg 'post' but was not found")
+	}
+	post, err := findPost(s, user, cmd.Args[0])
+	if err != nil {
+		return err
+	}
+	removed, err := s.DB.UnstarPost(context.Background(), database.UnstarPostParams{
+		UserID: user.ID,
+		PostID: post.Post.ID,
+	})
+	if err != nil {
+		return fmt.Errorf("error unstarring post %v: %v", post.Post.ID, err)
+	}
+	if removed == 0 {
+		return fmt.Errorf("post %v is not starred", post.Post.ID)
+	}
+	fmt.Printf("unstarred %v\n", post.Post.Title.String)
+	return nil
+}
+
+func HandlerStarred(s *State, cmd Command, user database.User) error {
+	posts, err := s.DB.GetStarredPosts(context.Background(), user.ID)
+	if err != nil {
+		return fmt.Errorf("error listing starred posts: %v", err)
+	}
+	if outputFormat(cmd) != output.Text {
+		var records []starredRecord
+		for _, post := range posts {
+			records = append(records, starredRecord{
+				ID:          post.Post.ID.String(),
+				Title:       post.Post.Title.String,
+				URL:         post.Post.Url.String,
+				Feed:        post.FeedName.String,
+				PublishedAt: timePtr(post.Post.PublishedAt),
+				StarredAt:   post.StarredAt,
+			})
+		}
+		return writeRecords(cmd, records)
+	}
+	for _, post := range posts {
+		printPostSummary(post.Post, post.FeedName.String)
+		fmt.Printf("	Starred: %v\n", post.StarredAt)
+	}
+	fmt.Printf("   --- %v starred posts --- \n", len(posts))
+	return nil
+}
+
+func HandlerLater(s *State, cmd Command, user database.User) error {
+	if len(cmd.Args) < 1 {
+		return usageErrorf(cmd.Name, "expected arg 'add|list|next|done' but was not found")
+	}
+	if err := listOnly(cmd); err != nil {
+		return err
+	}
+	switch cmd.Args[0] {
+	case "add":
+		if len(cmd.Args) < 2 {
+			return usageErrorf(cmd.Name, "expected arg 'post' but was not found")
+		}
+		post, err := findPost(s, user, cmd.Args[1])
+		if err != nil {
+			return err
+		}
+		added, err := s.DB.AddToReadLater(context.Background(), database.AddToReadLaterParams{
+			UserID:  user.ID,
+			PostID:  post.Post.ID,
+			AddedAt: time.Now().UTC(),
+		})
+		if err != nil {
+			return fmt.Errorf("error queueing post %v: %v", post.Post.ID, err)
+		}
+		if added == 0 {
+			return fmt.Errorf("post %v is already queued", post.Post.ID)
+		}
+		fmt.Printf("queued %v\n", post.Post.Title.String)
+		return nil
+
+	case "list":
+		queue, err := s.DB.GetReadLater(context.Background(), user.ID)
+		if err != nil {
+			return fmt.Errorf("error listing read later queue: %v", err)
+		}
+		if outputFormat(cmd) != output.Text {
+			var records []laterRecord
+			for i, item := range queue {
+				records = append(records, laterRecord{
+					Position:    i + 1,
+					ID:          item.Post.ID.String(),
+					Title:       item.Post.Title.String,
+					URL:         item.Post.Url.String,
+					Feed:        item.FeedName.String,
+					PublishedAt: timePtr(item.Post.PublishedAt),
+				})
+			}
+			return writeRecords(cmd, records)
+		}
+		for i, item := range queue {
+			fmt.Printf("%v. ", i+1)
+			printPostSummary(item.Post, item.FeedName.String)
+		}
+		fmt.Printf("   --- %v queued posts --- \n", len(queue))
+		return nil
+
+	case "next":
+		queue, err := s.DB.GetReadLater(context.Background(), user.ID)
+		if err != nil {
+			return fmt.Errorf("error listing read later queue: %v", err)
+		}
+		if len(queue) == 0 {
+			fmt.Println("read later queue is empty")
+			return nil
+		}
+		printPostContent(queue[0].Post, queue[0].FeedName.String)
+		return markRead(s, user, queue[0].Post)
+
+	case "done":
+		var post database.Post
+		if len(cmd.Args) > 1 {
+			found, err := findPost(s, user, cmd.Args[1])
+			if err != nil {
+				return err
+			}
+			post = found.Post
+		} else {
+			queue, err := s.DB.GetReadLater(context.Background(), user.ID)
+			if err != nil {
+				return fmt.Errorf("error listing read later queue: %v", err)
+			}
+			if len(queue) == 0 {
+				return errors.New("read later queue is empty")
+			}
+			post = queue[0].Post
+		}
+		removed, err := s.DB.RemoveFromReadLater(context.Background(), database.RemoveFromReadLaterParams{
+			UserID: user.ID,
+			PostID: post.ID,
+		})
+		if err != nil {
+			return fmt.Errorf("error removing post %v from the queue: %v", post.ID, err)
+		}
+		if removed == 0 {
+			return fmt.Errorf("post %v is not queued", post.ID)
+		}
+		fmt.Printf("done with %v\n", post.Title.String)
+		return nil
+
+	default:
+		return usageErrorf(cmd.Name, "unknown subcommand %v, expected add, list, next or done", cmd.Args[0])
+	}
+}
+
+// printPostSummary prints a post the way browse lists it.
+func printPostSummary(post database.Post, feedName string) {
+	if feedName == "" {
+		feedName = "(feed removed)"
+	}
+	fmt.Printf("   -------------------- \n")
+	fmt.Printf(color.YellowString("Title: %v"), "")
+	fmt.Printf(color.GreenString("%v\n"), post.Title.String)
+	fmt.Printf("	ID: %v\n", post.ID)
+	fmt.Printf("	Feed: %v\n", feedName)
+	fmt.Printf("	Published Date: %v\n", post.PublishedAt.Time)
+	fmt.Printf("	URL: %v\n", post.Url.String)
+}
@@ -0,0 +1,150 @@
+package cli
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/o0n1x/gator/internal/database"
+)
+
+const (
+	sortNewest = "newest"
+	sortOldest = "oldest"
+)
+
+func HandlerSubscription(s *State, cmd Command, user database.User) error {
+	if len(cmd.Args) < 1 {
+		return usageErrorf(cmd.Name, "expected arg 'show|set' but was not found")
+	}
+	switch cmd.Args[0] {
+	case "show":
+		if len(cmd.Args) < 2 {
+			return usageErrorf(cmd.Name, "expected arg 'url' but was not found")
+		}
+		feed, follow, err := subscription(s, user, cmd.Args[1])
+		if err != nil {
+			return err
+		}
+		fmt.Printf("* Feed: %v\n  URL: %v\n", feed.Name.String, feed.Url.String)
+		fmt.Printf("  name: %v\n", follow.DisplayName.String)
+		fmt.Printf("  hidden: %v\n", onOff(follow.Hidden))
+		fmt.Printf("  notify: %v\n", onOff(follow.Notify))
+		fmt.Printf("  fulltext: %v\n", onOff(follow.FullText))
+		fmt.Printf("  sort: %v\n", follow.DefaultSort)
+		return nil
+
+	case "set":
+		if len(cmd.Args) < 4 {
+			return usageErrorf(cmd.Name, "expected arg 'url', 'setting' and 'value' but was not found")
+		}
+		feed, follow, err := subscription(s, user, cmd.Args[1])
+		if err != nil {
+			return err
+		}
+		params := database.UpdateFeedFollowSettingsParams{
+			UserID:      follow.UserID,
+			FeedID:      follow.FeedID,
+			DisplayName: follow.DisplayName,
+			Hidden:      follow.Hidden,
+			Notify:      follow.Notify,
+			FullText:    follow.FullText,
+			DefaultSort: follow.DefaultSort,
+			UpdatedAt:   sql.NullTime{Time: time.Now(), Valid: true},
+		}
+		value := strings.Join(cmd.Args[3:], " ")
+		switch cmd.Args[2] {
+		case "name":
+			// "-" goes back to the name of the feed
+			params.DisplayName = sql.NullString{String: value, Valid: value != "-"}
+		case "hidden":
+			params.Hidden, err = parseOnOff(value)
+		case "notify":
+			params.Notify, err = parseOnOff(value)
+		case "fulltext":
+			params.FullText, err = parseOnOff(value)
+		case "sort":
+			if value != sortNewest && value != sortOldest {
+				return usageErrorf(cmd.Name, "invalid sort %v, expected newest or oldest", value)
+			}
+			params.DefaultSort = value
+		default:
+			return usageErrorf(cmd.Name, "unknown setting %v, expected name, hidden, notify, fulltext or sort", cmd.Args[2])
+		}
+		if err != nil {
+			return err
+		}
+		_, err = s.DB.UpdateFeedFollowSettings(context.Background(), params)
+		if err != nil {
+			return fmt.Errorf("error updating subscription to %v: %v", feed.Name.String, err)
+		}
+		fmt.Printf("set %v of %v to %v\n", cmd.Args[2], feed.Name.String, value)
+		return nil
+
+	default:
+		return usageErrorf(cmd.Name, "unknown subcommand %v, expected show or set", cmd.Args[0])
+	}
+}
+
+// subscription returns the feed behind url and the user's follow of it.
+func subscription(s *State, user database.User, url string) (database.Feed, database.FeedFollow, error) {
+	feed, err := feedByURL(s, url)
+	if err != nil {
+		return database.Feed{}, database.FeedFollow{}, err
+	}
+	follow, err := s.DB.GetFeedFollow(context.Background(), database.GetFeedFollowParams{
+		UserID: uuid.NullUUID{UUID: user.ID, Valid: true},
+		FeedID: uuid.NullUUID{UUID: feed.ID, Valid: true},
+	})
+	if err != nil {
+		return database.Feed{}, database.FeedFollow{}, fmt.Errorf("you do not follow %v", feed.Name.String)
+	}
+	return feed, follow, nil
+}
+
+// followName is the name the user gave the feed, or the feed name.
+func followName(follow database.GetFeedFollowsForUserRow) string {
+	if follow.DisplayName.Valid {
+		return follow.DisplayName.String
+	}
+	return follow.FeedName.String
+}
+
+// followFlags lists the non default settings of a follow for listings.
+func followFlags(follow database.GetFeedFollowsForUserRow) string {
+	var flags []string
+	if follow.Hidden {
+		flags = append(flags, "hidden")
+	}
+	if follow.Notify {
+		flags = append(flags, "notify")
+	}
+	if follow.FullText {
+		flags = append(flags, "fulltext")
+	}
+	if len(flags) == 0 {
+		return ""
+	}
+	return " [" + strings.Join(flags, ", ") + "]"
+}
+
+func parseOnOff(value string) (bool, error) {
+	switch strings.ToLower(value) {
+	case "on", "true", "yes":
+		return true, nil
+	case "off", "false", "no":
+		return false, nil
+	default:
+		return false, fmt.Errorf("invalid value %v, expected on or off", value)
+	}
+}
+
+func onOff(b bool) string {
+	if b {
+		return "on"
+	}
+	return "off"
+}
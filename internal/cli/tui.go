@@ -0,0 +1,23 @@
+package cli
+
+import (
+	"github.com/o0n1x/gator/internal/database"
+	"github.com/o0n1x/gator/internal/tui"
+)
+
+func HandlerTUI(s *State, cmd Command, user database.User) error {
+	return tui.Run(tui.Options{
+		DB:          s.DB,
+		User:        user,
+		OpenCommand: s.State.OpenCommand,
+		Refresh: func(url string) error {
+			feed, err := feedByURL(s, url)
+			if err != nil {
+				return err
+			}
+			// the reader owns the screen, skipped posts are not reported
+			_, err = refreshFeed(s, feed, func(string, ...interface{}) {})
+			return err
+		},
+	})
+}
@@ -0,0 +1,116 @@
+package cli
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/o0n1x/gator/internal/database"
+)
+
+// HandlerUnregister deletes the logged in user, or another one for an admin.
+// The feeds the user added that others follow stay, without an owner.
+func HandlerUnregister(s *State, cmd Command, user database.User) error {
+	target := user
+	if len(cmd.Args) > 0 && cmd.Args[0] != user.Name {
+		if !user.IsAdmin {
+			return fmt.Errorf("only an admin can unregister another user")
+		}
+		var err error
+		target, err = s.DB.GetUser(context.Background(), cmd.Args[0])
+		if err != nil {
+			return fmt.Errorf("user %v does not exist", cmd.Args[0])
+		}
+	}
+	if err := keepAnAdmin(s, target); err != nil {
+		return err
+	}
+
+	var deleted int64
+	err := s.DB.InTx(context.Background(), func(tx database.Store) error {
+		var err error
+		deleted, err = tx.DeleteUnsharedFeeds(context.Background(), target.ID)
+		if err != nil {
+			return fmt.Errorf("error deleting the feeds of %v: %v", target.Name, err)
+		}
+		if _, err := tx.DeleteUser(context.Background(), target.ID); err != nil {
+			return fmt.Errorf("error deleting user %v: %v", target.Name, err)
+		}
+		if _, err := tx.PruneOrphanedPosts(context.Background()); err != nil {
+			return fmt.Errorf("error deleting posts: %v", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if target.Name == s.State.CurrentUserName {
+		if err := s.State.SetUser(""); err != nil {
+			return err
+		}
+	}
+	fmt.Printf("unregistered %v, deleted %v feeds nobody else follows\n", target.Name, deleted)
+	return nil
+}
+
+func HandlerAdmin(s *State, cmd Command, user database.User) error {
+	if len(cmd.Args) < 2 {
+		return usageErrorf(cmd.Name, "expected arg 'grant|revoke' and 'username' but was not found")
+	}
+	var admin bool
+	switch cmd.Args[0] {
+	case "grant":
+		admin = true
+	case "revoke":
+	default:
+		return usageErrorf(cmd.Name, "unknown subcommand %v, expected grant or revoke", cmd.Args[0])
+	}
+	if !user.IsAdmin {
+		return fmt.Errorf("only an admin can %v admin rights", cmd.Args[0])
+	}
+	target, err := s.DB.GetUser(context.Background(), cmd.Args[1])
+	if err != nil {
+		return fmt.Errorf("user %v does not exist", cmd.Args[1])
+	}
+	if !admin {
+		if err := keepAnAdmin(s, target); err != nil {
+			return err
+		}
+	}
+	_, err = s.DB.SetUserAdmin(context.Background(), database.SetUserAdminParams{
+		ID:        target.ID,
+		IsAdmin:   admin,
+		UpdatedAt: sql.NullTime{Time: time.Now(), Valid: true},
+	})
+	if err != nil {
+		return fmt.Errorf("error changing the admin rights of %v: %v", target.Name, err)
+	}
+	if admin {
+		fmt.Printf("%v is now an admin\n", target.Name)
+	} else {
+		fmt.Printf("%v is no longer an admin\n", target.Name)
+	}
+	return nil
+}
+
+// keepAnAdmin refuses to take away the last admin while other users are left,
+// nobody could manage the feeds without an owner anymore.
+func keepAnAdmin(s *State, leaving database.User) error {
+	if !leaving.IsAdmin {
+		return nil
+	}
+	users, err := s.DB.GetUsers(context.Background())
+	if err != nil {
+		return fmt.Errorf("error retrieving users: %v", err)
+	}
+	for _, user := range users {
+		if user.IsAdmin && user.ID != leaving.ID {
+			return nil
+		}
+	}
+	if len(users) > 1 {
+		return fmt.Errorf("%v is the last admin, grant admin rights to another user first", leaving.Name)
+	}
+	return nil
+}
@@ -0,0 +1,126 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"errors"
+	"flag"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/o0n1x/gator/internal/database"
+	"github.com/o0n1x/gator/internal/rss"
+	"github.com/o0n1x/gator/internal/watch"
+)
+
+func addWatchFlags(f *flag.FlagSet) {
+	f.String("selector", "", "css selector narrowing the watched part of the page")
+	f.Float64("threshold", 0, "share of changed lines (0-1) needed before a post is created")
+}
+
+func HandlerAddWatch(s *State, cmd Command, user database.User) error {
+	selector := cmd.String("selector")
+	threshold := cmd.Float("threshold")
+	if threshold < 0 || threshold >= 1 {
+		return usageErrorf(cmd.Name, "invalid threshold %v, expected a value from 0 up to 1", threshold)
+	}
+	name := cmd.Args[0]
+	url := cmd.Args[1]
+
+	// the first snapshot is the baseline, taking it now also validates the selector
+	text, err := pageText(context.Background(), s, url, selector)
+	if err != nil {
+		return fmt.Errorf("error watching %v: %v", url, err)
+	}
+
+	var feed database.Feed
+	var feedfollow database.CreateFeedFollowRow
+	err = s.DB.InTx(context.Background(), func(tx database.Store) error {
+		var err error
+		feed, feedfollow, err = createFeed(s, tx, user, name, url, feedKindWatch)
+		if err != nil {
+			return err
+		}
+		_, err = tx.CreateFeedWatch(context.Background(), database.CreateFeedWatchParams{
+			FeedID:    feed.ID,
+			Selector:  sql.NullString{String: selector, Valid: selector != ""},
+			Threshold: threshold,
+		})
+		if err != nil {
+			return fmt.Errorf("error saving watch settings of %v: %v", name, err)
+		}
+		_, err = tx.CreatePageSnapshot(context.Background(), database.CreatePageSnapshotParams{
+			ID:        uuid.New(),
+			CreatedAt: time.Now().UTC(),
+			FeedID:    feed.ID,
+			Content:   text,
+		})
+		if err != nil {
+			return fmt.Errorf("error saving first snapshot of %v: %v", name, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Created Watch Feed:\nName: %v\nURL: %v\nUsername: %v\n", feed.Name.String, feed.Url.String, user.Name)
+	fmt.Printf("%v successfully followed %v\n", feedfollow.UserName, feedfollow.FeedName.String)
+	return nil
+}
+
+// watchFeed snapshots the watched page and returns a single item holding the diff
+// when it changed more than the feed threshold since the last snapshot. The new
+// snapshot is returned to be saved along with the item, nil when there is none.
+func watchFeed(ctx context.Context, s *State, feed database.Feed) (*rss.RSSFeed, *database.CreatePageSnapshotParams, error) {
+	settings, err := s.DB.GetFeedWatch(ctx, feed.ID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("missing watch settings: %v", err)
+	}
+	text, err := pageText(ctx, s, feed.Url.String, settings.Selector.String)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	page := &rss.RSSFeed{}
+	page.Channel.Title = feed.Name.String
+	page.Channel.Link = feed.Url.String
+
+	last, err := s.DB.GetLatestPageSnapshot(ctx, feed.ID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, nil, err
+	}
+	hasLast := err == nil
+	ratio := watch.ChangeRatio(last.Content, text)
+	if hasLast && (ratio == 0 || ratio <= settings.Threshold) {
+		return page, nil, nil
+	}
+
+	snapshot := &database.CreatePageSnapshotParams{
+		ID:        uuid.New(),
+		CreatedAt: time.Now().UTC(),
+		FeedID:    feed.ID,
+		Content:   text,
+	}
+	if !hasLast {
+		return page, snapshot, nil
+	}
+
+	diff := watch.Diff(last.Content, text, last.CreatedAt.Format(time.RFC3339), snapshot.CreatedAt.Format(time.RFC3339))
+	page.Channel.Item = []rss.RSSItem{{
+		Title:       fmt.Sprintf("%v changed (%.0f%% of lines)", feed.Name.String, ratio*100),
+		Link:        fmt.Sprintf("%v#snapshot-%v", feed.Url.String, snapshot.ID),
+		Description: diff,
+		PubDate:     snapshot.CreatedAt.Format(time.RFC3339),
+	}}
+	return page, snapshot, nil
+}
+
+func pageText(ctx context.Context, s *State, url, selector string) (string, error) {
+	page, err := s.Fetchers().Fetch(ctx, url)
+	if err != nil {
+		return "", err
+	}
+	return watch.Text(bytes.NewReader(page), selector)
+}
@@ -11,8 +11,14 @@ const configFileName = ".gatorconfig.json"
 var testconfigFilePath = ""
 
 type Config struct {
-	DB_URL          string `json:"db_url"`
-	CurrentUserName string `json:"current_user_name"`
+	DB_URL           string   `json:"db_url"`
+	CurrentUserName  string   `json:"current_user_name"`
+	TrackingParams   []string `json:"tracking_params,omitempty"`
+	ResolveRedirects bool     `json:"resolve_redirects,omitempty"`
+	AllowFileFeeds   bool     `json:"allow_file_feeds,omitempty"`
+	AllowExecFeeds   bool     `json:"allow_exec_feeds,omitempty"`
+	// OpenCommand opens links from the tui, the url is added as its last argument.
+	OpenCommand string `json:"open_command,omitempty"`
 }
 
 func Read() (Config, error) {
@@ -0,0 +1,343 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// BrowseSort is the order of the posts returned by Browse.
+type BrowseSort string
+
+const (
+	// BrowseByPublished lists the newest published posts first.
+	BrowseByPublished BrowseSort = "published"
+	// BrowseByFetched lists the most recently fetched posts first.
+	BrowseByFetched BrowseSort = "fetched"
+	// BrowseByFeed lists posts by feed name, newest first within a feed.
+	BrowseByFeed BrowseSort = "feed"
+)
+
+type BrowseParams struct {
+	UserID        uuid.UUID
+	UnreadOnly    bool
+	IncludeHidden bool
+	FolderID      uuid.NullUUID
+	FeedID        uuid.NullUUID
+	// Search is a websearch_to_tsquery query, as used by saved searches.
+	Search sql.NullString
+	Since  sql.NullTime
+	Until  sql.NullTime
+	// Author and Keyword match case insensitive substrings.
+	Author  string
+	Keyword string
+	Sort    BrowseSort
+	Reverse bool
+	// After is a cursor returned by BrowseCursor, the page starts after its post.
+	After string
+	Limit int32
+}
+
+type BrowseRow struct {
+	Post      Post
+	FeedName  string
+	IsRead    bool
+	IsStarred bool
+}
+
+type browseCursor struct {
+	Sort    BrowseSort `json:"s"`
+	Reverse bool       `json:"r"`
+	Keys    []string   `json:"k"`
+}
+
+type sortKey struct {
+	expr string
+	desc bool
+	// value is the cursor value of the key for a row
+	value func(row BrowseRow) string
+	// isTime keys are compared as times, the cursor holds them as text
+	isTime bool
+}
+
+const browseFeedName = "COALESCE(feed_follows.display_name, feeds.name, '')"
+
+func browseTime(t sql.NullTime) string {
+	return t.Time.Format(time.RFC3339Nano)
+}
+
+func sortKeys(sort BrowseSort, reverse bool) ([]sortKey, error) {
+	var keys []sortKey
+	switch sort {
+	case BrowseByPublished, "":
+		keys = []sortKey{
+			{"posts.published_at", true, func(row BrowseRow) string { return browseTime(row.Post.PublishedAt) }, true},
+		}
+	case BrowseByFetched:
+		keys = []sortKey{
+			{"posts.created_at", true, func(row BrowseRow) string { return browseTime(row.Post.CreatedAt) }, true},
+		}
+	case BrowseByFeed:
+		keys = []sortKey{
+			{browseFeedName, false, func(row BrowseRow) string { return row.FeedName }, false},
+			{"posts.published_at", true, func(row BrowseRow) string { return browseTime(row.Post.PublishedAt) }, true},
+		}
+	default:
+		return nil, fmt.Errorf("unknown sort %v, expected published, fetched or feed", sort)
+	}
+	// the id breaks ties so every post has a stable place between pages
+	keys = append(keys, sortKey{"posts.id", true, func(row BrowseRow) string { return row.Post.ID.String() }, false})
+	if reverse {
+		for i := range keys {
+			keys[i].desc = !keys[i].desc
+		}
+	}
+	return keys, nil
+}
+
+// BrowseCursor returns the cursor continuing a browse after row.
+func BrowseCursor(arg BrowseParams, row BrowseRow) string {
+	keys, err := sortKeys(arg.Sort, arg.Reverse)
+	if err != nil {
+		return ""
+	}
+	cursor := browseCursor{Sort: arg.Sort, Reverse: arg.Reverse}
+	if cursor.Sort == "" {
+		cursor.Sort = BrowseByPublished
+	}
+	for _, key := range keys {
+		cursor.Keys = append(cursor.Keys, key.value(row))
+	}
+	data, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodeBrowseCursor(s string) (browseCursor, error) {
+	var cursor browseCursor
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err == nil {
+		err = json.Unmarshal(data, &cursor)
+	}
+	if err != nil {
+		return browseCursor{}, errors.New("invalid cursor")
+	}
+	return cursor, nil
+}
+
+// browseQuery collects the sql and the arguments of a browse.
+type browseQuery struct {
+	dialect Dialect
+	where   []string
+	args    []interface{}
+}
+
+func (b *browseQuery) arg(value interface{}) string {
+	b.args = append(b.args, value)
+	if b.dialect == SQLite {
+		return fmt.Sprintf("?%d", len(b.args))
+	}
+	return fmt.Sprintf("$%d", len(b.args))
+}
+
+// search is the condition of the posts matching a websearch query.
+func (b *browseQuery) search(query string) string {
+	if b.dialect == SQLite {
+		return `posts.id IN (
+        SELECT posts_search_ids.post_id FROM posts_search
+        INNER JOIN posts_search_ids ON posts_search_ids.search_id = posts_search.rowid
+        WHERE posts_search MATCH websearch_to_fts(` + b.arg(query) + `)
+    )`
+	}
+	return "posts.search_vector @@ websearch_to_tsquery('english', " + b.arg(query) + ")"
+}
+
+// like is the condition of expr matching pattern case insensitively,
+// postgres escapes LIKE patterns with a backslash by default and sqlite LIKE
+// ignores case.
+func (b *browseQuery) like(expr, pattern string) string {
+	if b.dialect == SQLite {
+		return fmt.Sprintf(`%v LIKE %v ESCAPE '\'`, expr, pattern)
+	}
+	return fmt.Sprintf("%v ILIKE %v", expr, pattern)
+}
+
+// afterCursor adds the condition that keeps only rows after the cursor in
+// the order of keys, comparing key by key since directions can differ.
+func (b *browseQuery) afterCursor(keys []sortKey, cursor []string) error {
+	values := make([]interface{}, len(keys))
+	for i, key := range keys {
+		values[i] = cursor[i]
+		if key.isTime {
+			t, err := time.Parse(time.RFC3339Nano, cursor[i])
+			if err != nil {
+				return errors.New("invalid cursor")
+			}
+			values[i] = t
+		}
+	}
+
+	var or []string
+	for i, key := range keys {
+		var and []string
+		for j, prev := range keys[:i] {
+			and = append(and, fmt.Sprintf("%v = %v", prev.expr, b.arg(values[j])))
+		}
+		op := ">"
+		if key.desc {
+			op = "<"
+		}
+		and = append(and, fmt.Sprintf("%v %v %v", key.expr, op, b.arg(values[i])))
+		or = append(or, "("+strings.Join(and, " AND ")+")")
+	}
+	b.where = append(b.where, "("+strings.Join(or, " OR ")+")")
+	return nil
+}
+
+// likePattern matches s anywhere, with the wildcards in s taken literally.
+func likePattern(s string) string {
+	return "%" + strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s) + "%"
+}
+
+// browseOrder returns the sort keys of a browse and the key values of its
+// cursor, none when it starts at the first post.
+func browseOrder(arg BrowseParams) ([]sortKey, []string, error) {
+	keys, err := sortKeys(arg.Sort, arg.Reverse)
+	if err != nil || arg.After == "" {
+		return keys, nil, err
+	}
+	cursor, err := decodeBrowseCursor(arg.After)
+	if err != nil {
+		return nil, nil, err
+	}
+	sort := arg.Sort
+	if sort == "" {
+		sort = BrowseByPublished
+	}
+	if cursor.Sort != sort || cursor.Reverse != arg.Reverse || len(cursor.Keys) != len(keys) {
+		return nil, nil, errors.New("cursor belongs to a different sort order")
+	}
+	for i, key := range keys {
+		if _, err := time.Parse(time.RFC3339Nano, cursor.Keys[i]); key.isTime && err != nil {
+			return nil, nil, errors.New("invalid cursor")
+		}
+	}
+	return keys, cursor.Keys, nil
+}
+
+func buildBrowse(arg BrowseParams, dialect Dialect) (string, []interface{}, error) {
+	keys, cursor, err := browseOrder(arg)
+	if err != nil {
+		return "", nil, err
+	}
+
+	b := &browseQuery{dialect: dialect}
+	b.where = append(b.where, "feed_follows.user_id = "+b.arg(arg.UserID))
+	if arg.UnreadOnly {
+		b.where = append(b.where, "post_reads.post_id IS NULL")
+	}
+	if !arg.IncludeHidden {
+		b.where = append(b.where, "NOT feed_follows.hidden")
+	}
+	if arg.FolderID.Valid {
+		b.where = append(b.where, "feed_follows.folder_id = "+b.arg(arg.FolderID.UUID))
+	}
+	if arg.FeedID.Valid {
+		b.where = append(b.where, "posts.feed_id = "+b.arg(arg.FeedID.UUID))
+	}
+	if arg.Search.Valid {
+		b.where = append(b.where, b.search(arg.Search.String))
+	}
+	if arg.Since.Valid {
+		b.where = append(b.where, "posts.published_at >= "+b.arg(arg.Since.Time))
+	}
+	if arg.Until.Valid {
+		b.where = append(b.where, "posts.published_at < "+b.arg(arg.Until.Time))
+	}
+	if arg.Author != "" {
+		b.where = append(b.where, b.like("posts.author", b.arg(likePattern(arg.Author))))
+	}
+	if arg.Keyword != "" {
+		pattern := b.arg(likePattern(arg.Keyword))
+		b.where = append(b.where, "("+b.like("posts.title", pattern)+" OR "+b.like("posts.description", pattern)+")")
+	}
+	if cursor != nil {
+		if err := b.afterCursor(keys, cursor); err != nil {
+			return "", nil, err
+		}
+	}
+
+	var order []string
+	for _, key := range keys {
+		dir := "ASC"
+		if key.desc {
+			dir = "DESC"
+		}
+		order = append(order, key.expr+" "+dir)
+	}
+
+	query := `SELECT posts.id, posts.created_at, posts.updated_at, posts.title, posts.url, posts.description, posts.published_at, posts.feed_id, posts.content, posts.author, ` + browseFeedName + `, (post_reads.post_id IS NOT NULL), (post_stars.post_id IS NOT NULL)
+FROM posts
+INNER JOIN feeds ON posts.feed_id = feeds.id
+INNER JOIN feed_follows ON feed_follows.feed_id = feeds.id
+LEFT JOIN post_reads ON post_reads.post_id = posts.id AND post_reads.user_id = feed_follows.user_id
+LEFT JOIN post_stars ON post_stars.post_id = posts.id AND post_stars.user_id = feed_follows.user_id
+WHERE ` + strings.Join(b.where, "\n    AND ") + `
+ORDER BY ` + strings.Join(order, ", ") + `
+LIMIT ` + b.arg(arg.Limit)
+	return query, b.args, nil
+}
+
+// Browse lists posts of the user's followed feeds. Unlike the generated
+// queries its sql is built at runtime, since the filters and the order are
+// picked on the command line.
+func (q *Queries) Browse(ctx context.Context, arg BrowseParams) ([]BrowseRow, error) {
+	return browse(ctx, q.db, Postgres, arg)
+}
+
+func browse(ctx context.Context, db DBTX, dialect Dialect, arg BrowseParams) ([]BrowseRow, error) {
+	query, args, err := buildBrowse(arg, dialect)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []BrowseRow
+	for rows.Next() {
+		var i BrowseRow
+		if err := rows.Scan(
+			&i.Post.ID,
+			&i.Post.CreatedAt,
+			&i.Post.UpdatedAt,
+			&i.Post.Title,
+			&i.Post.Url,
+			&i.Post.Description,
+			&i.Post.PublishedAt,
+			&i.Post.FeedID,
+			&i.Post.Content,
+			&i.Post.Author,
+			&i.FeedName,
+			&i.IsRead,
+			&i.IsStarred,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
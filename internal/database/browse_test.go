@@ -0,0 +1,121 @@
+package database
+
+import (
+	"database/sql"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestBuildBrowseCursor(t *testing.T) {
+	row := BrowseRow{
+		Post: Post{
+			ID:          uuid.MustParse("6f1c3f0e-3b1a-4c59-9d7e-2f3c1d0a9b11"),
+			PublishedAt: sql.NullTime{Time: time.Date(2024, 5, 1, 12, 30, 0, 123000, time.UTC), Valid: true},
+		},
+		FeedName: "Go Blog",
+	}
+
+	cases := map[string]struct {
+		sort    BrowseSort
+		reverse bool
+		where   string
+		order   string
+		values  []interface{}
+	}{
+		"published": {
+			sort:   BrowseByPublished,
+			where:  "((posts.published_at < $2) OR (posts.published_at = $3 AND posts.id < $4))",
+			order:  "ORDER BY posts.published_at DESC, posts.id DESC",
+			values: []interface{}{row.Post.PublishedAt.Time, row.Post.PublishedAt.Time, row.Post.ID.String()},
+		},
+		"feed reversed": {
+			sort:    BrowseByFeed,
+			reverse: true,
+			where:   "((" + browseFeedName + " < $2) OR (" + browseFeedName + " = $3 AND posts.published_at > $4) OR (" + browseFeedName + " = $5 AND posts.published_at = $6 AND posts.id > $7))",
+			order:   "ORDER BY " + browseFeedName + " DESC, posts.published_at ASC, posts.id ASC",
+			values:  []interface{}{"Go Blog", "Go Blog", row.Post.PublishedAt.Time, "Go Blog", row.Post.PublishedAt.Time, row.Post.ID.String()},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			arg := BrowseParams{Sort: tc.sort, Reverse: tc.reverse, IncludeHidden: true, Limit: 5}
+			arg.After = BrowseCursor(arg, row)
+			query, args, err := buildBrowse(arg, Postgres)
+			if err != nil {
+				t.Errorf("buildBrowse Failed %v", err)
+				return
+			}
+			if !strings.Contains(query, tc.where) {
+				t.Errorf("where Mismatch wanted: %v , got: %v", tc.where, query)
+			}
+			if !strings.Contains(query, tc.order) {
+				t.Errorf("order Mismatch wanted: %v , got: %v", tc.order, query)
+			}
+			// user id first, limit last
+			got := args[1 : len(args)-1]
+			if len(got) != len(tc.values) {
+				t.Errorf("args Mismatch wanted: %v , got: %v", tc.values, got)
+				return
+			}
+			for i := range got {
+				if got[i] != tc.values[i] {
+					t.Errorf("args Mismatch wanted: %v , got: %v", tc.values, got)
+				}
+			}
+		})
+	}
+}
+
+func TestBuildBrowseCursorSortMismatch(t *testing.T) {
+	arg := BrowseParams{Sort: BrowseByFetched}
+	arg.After = BrowseCursor(BrowseParams{Sort: BrowseByPublished}, BrowseRow{})
+	_, _, err := buildBrowse(arg, Postgres)
+	if err == nil {
+		t.Errorf("buildBrowse accepted a cursor of another sort")
+	}
+}
+
+func TestBuildBrowseDialects(t *testing.T) {
+	arg := BrowseParams{Search: sql.NullString{String: "go", Valid: true}, Author: "rob", Keyword: "generics", Limit: 5}
+	cases := map[string]struct {
+		dialect Dialect
+		want    []string
+	}{
+		"postgres": {dialect: Postgres, want: []string{
+			"posts.search_vector @@ websearch_to_tsquery('english', $2)",
+			"posts.author ILIKE $3",
+			"(posts.title ILIKE $4 OR posts.description ILIKE $4)",
+			"LIMIT $5",
+		}},
+		"sqlite": {dialect: SQLite, want: []string{
+			"WHERE posts_search MATCH websearch_to_fts(?2)",
+			`posts.author LIKE ?3 ESCAPE '\'`,
+			`(posts.title LIKE ?4 ESCAPE '\' OR posts.description LIKE ?4 ESCAPE '\')`,
+			"LIMIT ?5",
+		}},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			query, _, err := buildBrowse(arg, tc.dialect)
+			if err != nil {
+				t.Fatalf("buildBrowse Failed %v", err)
+			}
+			for _, want := range tc.want {
+				if !strings.Contains(query, want) {
+					t.Errorf("query Mismatch wanted: %v , got: %v", want, query)
+				}
+			}
+		})
+	}
+}
+
+func TestLikePattern(t *testing.T) {
+	got := likePattern(`100%_sure\`)
+	want := `%100\%\_sure\\%`
+	if got != want {
+		t.Errorf("likePattern Mismatch wanted: %v , got: %v", want, got)
+	}
+}
@@ -21,9 +21,9 @@ VALUES (
     $4,
     $5
 )
-RETURNING id, created_at, updated_at, user_id, feed_id
+RETURNING id, created_at, updated_at, user_id, feed_id, folder_id, display_name, hidden, notify, full_text, default_sort
 )
-SELECT inserted_feed_follows.id, inserted_feed_follows.created_at, inserted_feed_follows.updated_at, inserted_feed_follows.user_id, inserted_feed_follows.feed_id , users.name AS user_name , feeds.name AS feed_name
+SELECT inserted_feed_follows.id, inserted_feed_follows.created_at, inserted_feed_follows.updated_at, inserted_feed_follows.user_id, inserted_feed_follows.feed_id, inserted_feed_follows.folder_id, inserted_feed_follows.display_name, inserted_feed_follows.hidden, inserted_feed_follows.notify, inserted_feed_follows.full_text, inserted_feed_follows.default_sort , users.name AS user_name , feeds.name AS feed_name
 FROM inserted_feed_follows
 INNER JOIN users ON inserted_feed_follows.user_id = users.id 
 INNER JOIN feeds ON inserted_feed_follows.feed_id = feeds.id
@@ -38,13 +38,19 @@ type CreateFeedFollowParams struct {
 }
 
 type CreateFeedFollowRow struct {
-	ID        uuid.UUID
-	CreatedAt sql.NullTime
-	UpdatedAt sql.NullTime
-	UserID    uuid.NullUUID
-	FeedID    uuid.NullUUID
-	UserName  string
-	FeedName  sql.NullString
+	ID          uuid.UUID
+	CreatedAt   sql.NullTime
+	UpdatedAt   sql.NullTime
+	UserID      uuid.NullUUID
+	FeedID      uuid.NullUUID
+	FolderID    uuid.NullUUID
+	DisplayName sql.NullString
+	Hidden      bool
+	Notify      bool
+	FullText    bool
+	DefaultSort string
+	UserName    string
+	FeedName    sql.NullString
 }
 
 func (q *Queries) CreateFeedFollow(ctx context.Context, arg CreateFeedFollowParams) (CreateFeedFollowRow, error) {
@@ -62,6 +68,12 @@ func (q *Queries) CreateFeedFollow(ctx context.Context, arg CreateFeedFollowPara
 		&i.UpdatedAt,
 		&i.UserID,
 		&i.FeedID,
+		&i.FolderID,
+		&i.DisplayName,
+		&i.Hidden,
+		&i.Notify,
+		&i.FullText,
+		&i.DefaultSort,
 		&i.UserName,
 		&i.FeedName,
 	)
@@ -0,0 +1,51 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	_ "github.com/lib/pq"
+)
+
+// Dialect is the kind of database behind a connection.
+type Dialect string
+
+const (
+	Postgres Dialect = "postgres"
+	SQLite   Dialect = "sqlite"
+)
+
+// sqliteScheme starts the db_url of a sqlite database, sqlite:///path/to/gator.db.
+const sqliteScheme = "sqlite://"
+
+// Open connects to the database of dbURL, a postgres url or sqlite:// and
+// the path of a sqlite file, which is created when it does not exist.
+func Open(dbURL string) (*sql.DB, error) {
+	path, ok := strings.CutPrefix(dbURL, sqliteScheme)
+	if !ok {
+		return sql.Open("postgres", dbURL)
+	}
+	if path == "" {
+		return nil, fmt.Errorf("db_url %v has no path, use sqlite:///path/to/gator.db", dbURL)
+	}
+	// foreign keys are off in sqlite unless asked for, immediate transactions
+	// wait for other writers instead of failing when they start to write
+	return sql.Open(sqliteDriverName, "file:"+path+"?_pragma=foreign_keys(1)&_pragma=busy_timeout(10000)&_pragma=journal_mode(WAL)&_txlock=immediate")
+}
+
+// DialectOf returns the dialect of a connection opened by Open.
+func DialectOf(db *sql.DB) Dialect {
+	if _, ok := db.Driver().(sqliteDriver); ok {
+		return SQLite
+	}
+	return Postgres
+}
+
+// NewStore returns the Store of a connection opened by Open.
+func NewStore(db *sql.DB) Store {
+	if DialectOf(db) == SQLite {
+		return NewSQLiteQueries(db)
+	}
+	return New(db)
+}
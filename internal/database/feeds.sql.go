@@ -13,16 +13,18 @@ import (
 )
 
 const createFeed = `-- name: CreateFeed :one
-INSERT INTO feeds (id, created_at, updated_at, name, url , user_id)
+INSERT INTO feeds (id, created_at, updated_at, name, url , user_id, url_key, kind)
 VALUES (
     $1,
     $2,
     $3,
     $4,
     $5,
-    $6
+    $6,
+    $7,
+    $8
 )
-RETURNING id, created_at, updated_at, name, url, user_id, last_fetched_at
+RETURNING id, created_at, updated_at, name, url, user_id, last_fetched_at, url_key, kind, site_url
 `
 
 type CreateFeedParams struct {
@@ -32,6 +34,8 @@ type CreateFeedParams struct {
 	Name      sql.NullString
 	Url       sql.NullString
 	UserID    uuid.NullUUID
+	UrlKey    sql.NullString
+	Kind      string
 }
 
 func (q *Queries) CreateFeed(ctx context.Context, arg CreateFeedParams) (Feed, error) {
@@ -42,6 +46,8 @@ func (q *Queries) CreateFeed(ctx context.Context, arg CreateFeedParams) (Feed, e
 		arg.Name,
 		arg.Url,
 		arg.UserID,
+		arg.UrlKey,
+		arg.Kind,
 	)
 	var i Feed
 	err := row.Scan(
@@ -52,6 +58,131 @@ func (q *Queries) CreateFeed(ctx context.Context, arg CreateFeedParams) (Feed, e
 		&i.Url,
 		&i.UserID,
 		&i.LastFetchedAt,
+		&i.UrlKey,
+		&i.Kind,
+		&i.SiteUrl,
 	)
 	return i, err
 }
+
+const deleteFeed = `-- name: DeleteFeed :execrows
+DELETE FROM feeds
+WHERE id = $1
+`
+
+func (q *Queries) DeleteFeed(ctx context.Context, id uuid.UUID) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteFeed, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const deleteFeeds = `-- name: DeleteFeeds :exec
+DELETE FROM feeds
+`
+
+func (q *Queries) DeleteFeeds(ctx context.Context) error {
+	_, err := q.db.ExecContext(ctx, deleteFeeds)
+	return err
+}
+
+const deleteUnsharedFeeds = `-- name: DeleteUnsharedFeeds :execrows
+DELETE FROM feeds
+WHERE feeds.user_id = $1::uuid AND NOT EXISTS (
+    SELECT 1 FROM feed_follows
+    WHERE feed_follows.feed_id = feeds.id AND feed_follows.user_id <> $1::uuid
+)
+`
+
+// the feeds of a user that nobody else follows
+func (q *Queries) DeleteUnsharedFeeds(ctx context.Context, userID uuid.UUID) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteUnsharedFeeds, userID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const renameFeed = `-- name: RenameFeed :execrows
+UPDATE feeds
+SET name = $2, updated_at = $3
+WHERE id = $1
+`
+
+type RenameFeedParams struct {
+	ID        uuid.UUID
+	Name      sql.NullString
+	UpdatedAt sql.NullTime
+}
+
+func (q *Queries) RenameFeed(ctx context.Context, arg RenameFeedParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, renameFeed, arg.ID, arg.Name, arg.UpdatedAt)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const setFeedOwner = `-- name: SetFeedOwner :execrows
+UPDATE feeds
+SET user_id = $2, updated_at = $3
+WHERE id = $1
+`
+
+type SetFeedOwnerParams struct {
+	ID        uuid.UUID
+	UserID    uuid.NullUUID
+	UpdatedAt sql.NullTime
+}
+
+func (q *Queries) SetFeedOwner(ctx context.Context, arg SetFeedOwnerParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, setFeedOwner, arg.ID, arg.UserID, arg.UpdatedAt)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const setFeedSiteURL = `-- name: SetFeedSiteURL :exec
+UPDATE feeds
+SET site_url = $2, updated_at = $3
+WHERE id = $1
+`
+
+type SetFeedSiteURLParams struct {
+	ID        uuid.UUID
+	SiteUrl   sql.NullString
+	UpdatedAt sql.NullTime
+}
+
+func (q *Queries) SetFeedSiteURL(ctx context.Context, arg SetFeedSiteURLParams) error {
+	_, err := q.db.ExecContext(ctx, setFeedSiteURL, arg.ID, arg.SiteUrl, arg.UpdatedAt)
+	return err
+}
+
+const setFeedURL = `-- name: SetFeedURL :execrows
+UPDATE feeds
+SET url = $2, url_key = $3, updated_at = $4
+WHERE id = $1
+`
+
+type SetFeedURLParams struct {
+	ID        uuid.UUID
+	Url       sql.NullString
+	UrlKey    sql.NullString
+	UpdatedAt sql.NullTime
+}
+
+func (q *Queries) SetFeedURL(ctx context.Context, arg SetFeedURLParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, setFeedURL,
+		arg.ID,
+		arg.Url,
+		arg.UrlKey,
+		arg.UpdatedAt,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
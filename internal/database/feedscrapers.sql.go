@@ -0,0 +1,80 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: feedscrapers.sql
+
+package database
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/google/uuid"
+)
+
+const createFeedScraper = `-- name: CreateFeedScraper :one
+INSERT INTO feed_scrapers (feed_id, item_selector, title_selector, link_selector, date_selector, date_format, summary_selector)
+VALUES (
+    $1,
+    $2,
+    $3,
+    $4,
+    $5,
+    $6,
+    $7
+)
+RETURNING feed_id, item_selector, title_selector, link_selector, date_selector, date_format, summary_selector
+`
+
+type CreateFeedScraperParams struct {
+	FeedID          uuid.UUID
+	ItemSelector    string
+	TitleSelector   string
+	LinkSelector    string
+	DateSelector    sql.NullString
+	DateFormat      sql.NullString
+	SummarySelector sql.NullString
+}
+
+func (q *Queries) CreateFeedScraper(ctx context.Context, arg CreateFeedScraperParams) (FeedScraper, error) {
+	row := q.db.QueryRowContext(ctx, createFeedScraper,
+		arg.FeedID,
+		arg.ItemSelector,
+		arg.TitleSelector,
+		arg.LinkSelector,
+		arg.DateSelector,
+		arg.DateFormat,
+		arg.SummarySelector,
+	)
+	var i FeedScraper
+	err := row.Scan(
+		&i.FeedID,
+		&i.ItemSelector,
+		&i.TitleSelector,
+		&i.LinkSelector,
+		&i.DateSelector,
+		&i.DateFormat,
+		&i.SummarySelector,
+	)
+	return i, err
+}
+
+const getFeedScraper = `-- name: GetFeedScraper :one
+SELECT feed_id, item_selector, title_selector, link_selector, date_selector, date_format, summary_selector FROM feed_scrapers
+WHERE feed_id = $1
+`
+
+func (q *Queries) GetFeedScraper(ctx context.Context, feedID uuid.UUID) (FeedScraper, error) {
+	row := q.db.QueryRowContext(ctx, getFeedScraper, feedID)
+	var i FeedScraper
+	err := row.Scan(
+		&i.FeedID,
+		&i.ItemSelector,
+		&i.TitleSelector,
+		&i.LinkSelector,
+		&i.DateSelector,
+		&i.DateFormat,
+		&i.SummarySelector,
+	)
+	return i, err
+}
@@ -0,0 +1,48 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: feedwatches.sql
+
+package database
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/google/uuid"
+)
+
+const createFeedWatch = `-- name: CreateFeedWatch :one
+INSERT INTO feed_watches (feed_id, selector, threshold)
+VALUES (
+    $1,
+    $2,
+    $3
+)
+RETURNING feed_id, selector, threshold
+`
+
+type CreateFeedWatchParams struct {
+	FeedID    uuid.UUID
+	Selector  sql.NullString
+	Threshold float64
+}
+
+func (q *Queries) CreateFeedWatch(ctx context.Context, arg CreateFeedWatchParams) (FeedWatch, error) {
+	row := q.db.QueryRowContext(ctx, createFeedWatch, arg.FeedID, arg.Selector, arg.Threshold)
+	var i FeedWatch
+	err := row.Scan(&i.FeedID, &i.Selector, &i.Threshold)
+	return i, err
+}
+
+const getFeedWatch = `-- name: GetFeedWatch :one
+SELECT feed_id, selector, threshold FROM feed_watches
+WHERE feed_id = $1
+`
+
+func (q *Queries) GetFeedWatch(ctx context.Context, feedID uuid.UUID) (FeedWatch, error) {
+	row := q.db.QueryRowContext(ctx, getFeedWatch, feedID)
+	var i FeedWatch
+	err := row.Scan(&i.FeedID, &i.Selector, &i.Threshold)
+	return i, err
+}
@@ -11,7 +11,7 @@ import (
 )
 
 const getFeedByURL = `-- name: GetFeedByURL :one
-SELECT id, created_at, updated_at, name, url, user_id, last_fetched_at FROM feeds
+SELECT id, created_at, updated_at, name, url, user_id, last_fetched_at, url_key, kind, site_url FROM feeds
 WHERE url = $1
 `
 
@@ -26,6 +26,9 @@ func (q *Queries) GetFeedByURL(ctx context.Context, url sql.NullString) (Feed, e
 		&i.Url,
 		&i.UserID,
 		&i.LastFetchedAt,
+		&i.UrlKey,
+		&i.Kind,
+		&i.SiteUrl,
 	)
 	return i, err
 }
@@ -0,0 +1,58 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: getfeedbyurlkey.sql
+
+package database
+
+import (
+	"context"
+	"database/sql"
+)
+
+const getFeedByURLKey = `-- name: GetFeedByURLKey :one
+SELECT id, created_at, updated_at, name, url, user_id, last_fetched_at, url_key, kind, site_url FROM feeds
+WHERE url_key = $1
+`
+
+func (q *Queries) GetFeedByURLKey(ctx context.Context, urlKey sql.NullString) (Feed, error) {
+	row := q.db.QueryRowContext(ctx, getFeedByURLKey, urlKey)
+	var i Feed
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Name,
+		&i.Url,
+		&i.UserID,
+		&i.LastFetchedAt,
+		&i.UrlKey,
+		&i.Kind,
+		&i.SiteUrl,
+	)
+	return i, err
+}
+
+const lockFeedByURLKey = `-- name: LockFeedByURLKey :one
+SELECT id, created_at, updated_at, name, url, user_id, last_fetched_at, url_key, kind, site_url FROM feeds
+WHERE url_key = $1
+FOR UPDATE
+`
+
+func (q *Queries) LockFeedByURLKey(ctx context.Context, urlKey sql.NullString) (Feed, error) {
+	row := q.db.QueryRowContext(ctx, lockFeedByURLKey, urlKey)
+	var i Feed
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Name,
+		&i.Url,
+		&i.UserID,
+		&i.LastFetchedAt,
+		&i.UrlKey,
+		&i.Kind,
+		&i.SiteUrl,
+	)
+	return i, err
+}
@@ -13,20 +13,31 @@ import (
 )
 
 const getFeedFollowsForUser = `-- name: GetFeedFollowsForUser :many
-SELECT feed_follows.id, feed_follows.created_at, feed_follows.updated_at, feed_follows.user_id, feed_follows.feed_id,users.name AS user_name ,feeds.name AS feed_name FROM feed_follows
+SELECT feed_follows.id, feed_follows.created_at, feed_follows.updated_at, feed_follows.user_id, feed_follows.feed_id, feed_follows.folder_id, feed_follows.display_name, feed_follows.hidden, feed_follows.notify, feed_follows.full_text, feed_follows.default_sort,users.name AS user_name ,feeds.name AS feed_name, feeds.url AS feed_url, feeds.site_url AS feed_site_url, folders.name AS folder_name FROM feed_follows
 INNER JOIN users ON feed_follows.user_id = users.id 
 INNER JOIN feeds ON feed_follows.feed_id = feeds.id
+LEFT JOIN folders ON feed_follows.folder_id = folders.id
 WHERE feed_follows.user_id = $1
+ORDER BY folders.name ASC NULLS FIRST, feeds.name ASC
 `
 
 type GetFeedFollowsForUserRow struct {
-	ID        uuid.UUID
-	CreatedAt sql.NullTime
-	UpdatedAt sql.NullTime
-	UserID    uuid.NullUUID
-	FeedID    uuid.NullUUID
-	UserName  string
-	FeedName  sql.NullString
+	ID          uuid.UUID
+	CreatedAt   sql.NullTime
+	UpdatedAt   sql.NullTime
+	UserID      uuid.NullUUID
+	FeedID      uuid.NullUUID
+	FolderID    uuid.NullUUID
+	DisplayName sql.NullString
+	Hidden      bool
+	Notify      bool
+	FullText    bool
+	DefaultSort string
+	UserName    string
+	FeedName    sql.NullString
+	FeedUrl     sql.NullString
+	FeedSiteUrl sql.NullString
+	FolderName  sql.NullString
 }
 
 func (q *Queries) GetFeedFollowsForUser(ctx context.Context, userID uuid.NullUUID) ([]GetFeedFollowsForUserRow, error) {
@@ -44,8 +55,17 @@ func (q *Queries) GetFeedFollowsForUser(ctx context.Context, userID uuid.NullUUI
 			&i.UpdatedAt,
 			&i.UserID,
 			&i.FeedID,
+			&i.FolderID,
+			&i.DisplayName,
+			&i.Hidden,
+			&i.Notify,
+			&i.FullText,
+			&i.DefaultSort,
 			&i.UserName,
 			&i.FeedName,
+			&i.FeedUrl,
+			&i.FeedSiteUrl,
+			&i.FolderName,
 		); err != nil {
 			return nil, err
 		}
@@ -11,7 +11,7 @@ import (
 
 const getNextFeedToFetch = `-- name: GetNextFeedToFetch :one
 
-SELECT id, created_at, updated_at, name, url, user_id, last_fetched_at FROM feeds
+SELECT id, created_at, updated_at, name, url, user_id, last_fetched_at, url_key, kind, site_url FROM feeds
 ORDER BY last_fetched_at ASC NULLS FIRST
 `
 
@@ -26,6 +26,9 @@ func (q *Queries) GetNextFeedToFetch(ctx context.Context) (Feed, error) {
 		&i.Url,
 		&i.UserID,
 		&i.LastFetchedAt,
+		&i.UrlKey,
+		&i.Kind,
+		&i.SiteUrl,
 	)
 	return i, err
 }
@@ -13,7 +13,7 @@ import (
 
 const getPostsForUser = `-- name: GetPostsForUser :many
 
-SELECT posts.id, posts.created_at, posts.updated_at, posts.title, posts.url, posts.description, posts.published_at, posts.feed_id 
+SELECT posts.id, posts.created_at, posts.updated_at, posts.title, posts.url, posts.description, posts.published_at, posts.feed_id, posts.content, posts.search_vector, posts.author 
 FROM posts
 INNER JOIN feeds ON posts.feed_id = feeds.id
 WHERE feeds.id IN (
@@ -49,6 +49,9 @@ func (q *Queries) GetPostsForUser(ctx context.Context, arg GetPostsForUserParams
 			&i.Description,
 			&i.PublishedAt,
 			&i.FeedID,
+			&i.Content,
+			&i.SearchVector,
+			&i.Author,
 		); err != nil {
 			return nil, err
 		}
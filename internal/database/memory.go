@@ -0,0 +1,738 @@
+package database
+
+import (
+	"cmp"
+	"context"
+	"database/sql"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Memory is a Store keeping everything in memory, for tests. It keeps the
+// primary keys, unique columns, foreign keys, cascades and orderings of the
+// sql schema, and returns sql.ErrNoRows like the generated queries do.
+// Searches match words as written, without the stemming of the databases.
+type Memory struct {
+	mu sync.Mutex
+	// tx is held by the transaction running, one at a time.
+	tx sync.Mutex
+	memoryRows
+}
+
+// memoryRows are the tables of a Memory.
+type memoryRows struct {
+	users     []User
+	feeds     []Feed
+	scrapers  []FeedScraper
+	watches   []FeedWatch
+	snapshots []PageSnapshot
+	folders   []Folder
+	follows   []FeedFollow
+	posts     []Post
+	reads     []PostRead
+	stars     []PostStar
+	readLater []ReadLater
+	searches  []SavedSearch
+}
+
+func NewMemory() *Memory {
+	return &Memory{}
+}
+
+// clone copies the tables, the rows hold no pointers so they are copied too.
+func (r memoryRows) clone() memoryRows {
+	return memoryRows{
+		users:     slices.Clone(r.users),
+		feeds:     slices.Clone(r.feeds),
+		scrapers:  slices.Clone(r.scrapers),
+		watches:   slices.Clone(r.watches),
+		snapshots: slices.Clone(r.snapshots),
+		folders:   slices.Clone(r.folders),
+		follows:   slices.Clone(r.follows),
+		posts:     slices.Clone(r.posts),
+		reads:     slices.Clone(r.reads),
+		stars:     slices.Clone(r.stars),
+		readLater: slices.Clone(r.readLater),
+		searches:  slices.Clone(r.searches),
+	}
+}
+
+// InTx runs fn on m and puts the tables back as they were when fn fails.
+// Transactions run one at a time, but writes made outside of one while fn
+// runs are lost on a rollback as well.
+func (m *Memory) InTx(ctx context.Context, fn func(tx Store) error) error {
+	m.tx.Lock()
+	defer m.tx.Unlock()
+	m.mu.Lock()
+	saved := m.memoryRows.clone()
+	m.mu.Unlock()
+
+	if err := fn(memoryTx{m}); err != nil {
+		m.mu.Lock()
+		m.memoryRows = saved
+		m.mu.Unlock()
+		return err
+	}
+	return nil
+}
+
+// memoryTx is the Memory a transaction runs on.
+type memoryTx struct {
+	*Memory
+}
+
+// InTx joins the transaction running.
+func (tx memoryTx) InTx(ctx context.Context, fn func(tx Store) error) error {
+	return fn(tx)
+}
+
+// memTime is t as a TIMESTAMP column gives it back: the wall clock in UTC,
+// to the microsecond.
+func memTime(t time.Time) time.Time {
+	wall := time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), time.UTC)
+	return wall.Round(time.Microsecond)
+}
+
+func memNullTime(t sql.NullTime) sql.NullTime {
+	if !t.Valid {
+		return sql.NullTime{}
+	}
+	return sql.NullTime{Time: memTime(t.Time), Valid: true}
+}
+
+// sameID is the sql = of two nullable ids, never true for a null.
+func sameID(a, b uuid.NullUUID) bool {
+	return a.Valid && b.Valid && a.UUID == b.UUID
+}
+
+// sameText is the sql = of two nullable strings, never true for a null.
+func sameText(a, b sql.NullString) bool {
+	return a.Valid && b.Valid && a.String == b.String
+}
+
+func errDuplicate(constraint string) error {
+	return fmt.Errorf("duplicate key value violates unique constraint %q", constraint)
+}
+
+func errForeignKey(constraint string) error {
+	return fmt.Errorf("insert or update violates foreign key constraint %q", constraint)
+}
+
+// find returns the first row matching, sql.ErrNoRows when there is none.
+func find[T any](rows []T, match func(T) bool) (T, error) {
+	for _, row := range rows {
+		if match(row) {
+			return row, nil
+		}
+	}
+	var zero T
+	return zero, sql.ErrNoRows
+}
+
+func exists[T any](rows []T, match func(T) bool) bool {
+	_, err := find(rows, match)
+	return err == nil
+}
+
+// update changes the rows matching and counts them.
+func update[T any](rows []T, match func(T) bool, change func(*T)) int64 {
+	var n int64
+	for i := range rows {
+		if match(rows[i]) {
+			change(&rows[i])
+			n++
+		}
+	}
+	return n
+}
+
+// remove deletes the rows matching and counts them.
+func remove[T any](rows *[]T, match func(T) bool) int64 {
+	before := len(*rows)
+	*rows = slices.DeleteFunc(*rows, match)
+	return int64(before - len(*rows))
+}
+
+// compareNulls orders valid values by compare, nulls before them when
+// nullsFirst is set and after them otherwise.
+func compareNulls[T any](a, b T, aValid, bValid, nullsFirst bool, compare func(a, b T) int) int {
+	first := -1
+	if !nullsFirst {
+		first = 1
+	}
+	switch {
+	case !aValid && !bValid:
+		return 0
+	case !aValid:
+		return first
+	case !bValid:
+		return -first
+	}
+	return compare(a, b)
+}
+
+func (m *Memory) userExists(id uuid.UUID) bool {
+	return exists(m.users, func(u User) bool { return u.ID == id })
+}
+
+func (m *Memory) feedExists(id uuid.UUID) bool {
+	return exists(m.feeds, func(f Feed) bool { return f.ID == id })
+}
+
+func (m *Memory) postExists(id uuid.UUID) bool {
+	return exists(m.posts, func(p Post) bool { return p.ID == id })
+}
+
+func (m *Memory) feedName(id uuid.NullUUID) sql.NullString {
+	feed, err := find(m.feeds, func(f Feed) bool { return id.Valid && f.ID == id.UUID })
+	if err != nil {
+		return sql.NullString{}
+	}
+	return feed.Name
+}
+
+// deleteUsers deletes the users matching and cascades like the foreign keys
+// do, their feeds stay without an owner.
+func (m *Memory) deleteUsers(match func(User) bool) int64 {
+	ids := map[uuid.UUID]bool{}
+	for _, user := range m.users {
+		if match(user) {
+			ids[user.ID] = true
+		}
+	}
+	byUser := func(id uuid.NullUUID) bool { return id.Valid && ids[id.UUID] }
+	update(m.feeds, func(f Feed) bool { return byUser(f.UserID) }, func(f *Feed) { f.UserID = uuid.NullUUID{} })
+	m.deleteFolders(func(f Folder) bool { return ids[f.UserID] })
+	remove(&m.follows, func(f FeedFollow) bool { return byUser(f.UserID) })
+	remove(&m.reads, func(r PostRead) bool { return ids[r.UserID] })
+	remove(&m.stars, func(s PostStar) bool { return ids[s.UserID] })
+	remove(&m.readLater, func(r ReadLater) bool { return ids[r.UserID] })
+	remove(&m.searches, func(s SavedSearch) bool { return ids[s.UserID] })
+	return remove(&m.users, match)
+}
+
+// deleteFeeds deletes the feeds matching, their posts stay without a feed.
+func (m *Memory) deleteFeeds(match func(Feed) bool) int64 {
+	ids := map[uuid.UUID]bool{}
+	for _, feed := range m.feeds {
+		if match(feed) {
+			ids[feed.ID] = true
+		}
+	}
+	byFeed := func(id uuid.NullUUID) bool { return id.Valid && ids[id.UUID] }
+	remove(&m.follows, func(f FeedFollow) bool { return byFeed(f.FeedID) })
+	remove(&m.scrapers, func(s FeedScraper) bool { return ids[s.FeedID] })
+	remove(&m.watches, func(w FeedWatch) bool { return ids[w.FeedID] })
+	remove(&m.snapshots, func(s PageSnapshot) bool { return ids[s.FeedID] })
+	update(m.posts, func(p Post) bool { return byFeed(p.FeedID) }, func(p *Post) { p.FeedID = uuid.NullUUID{} })
+	return remove(&m.feeds, match)
+}
+
+// deleteFolders deletes the folders matching, their follows stay without a folder.
+func (m *Memory) deleteFolders(match func(Folder) bool) int64 {
+	ids := map[uuid.UUID]bool{}
+	for _, folder := range m.folders {
+		if match(folder) {
+			ids[folder.ID] = true
+		}
+	}
+	update(m.follows, func(f FeedFollow) bool { return f.FolderID.Valid && ids[f.FolderID.UUID] }, func(f *FeedFollow) { f.FolderID = uuid.NullUUID{} })
+	return remove(&m.folders, match)
+}
+
+// deletePosts deletes the posts matching with what users did with them.
+func (m *Memory) deletePosts(match func(Post) bool) int64 {
+	ids := map[uuid.UUID]bool{}
+	for _, post := range m.posts {
+		if match(post) {
+			ids[post.ID] = true
+		}
+	}
+	remove(&m.reads, func(r PostRead) bool { return ids[r.PostID] })
+	remove(&m.stars, func(s PostStar) bool { return ids[s.PostID] })
+	remove(&m.readLater, func(r ReadLater) bool { return ids[r.PostID] })
+	return remove(&m.posts, match)
+}
+
+func (m *Memory) CreateUser(ctx context.Context, arg CreateUserParams) (User, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.userExists(arg.ID) {
+		return User{}, errDuplicate("users_pkey")
+	}
+	user := User{
+		ID:        arg.ID,
+		CreatedAt: memNullTime(arg.CreatedAt),
+		UpdatedAt: memNullTime(arg.UpdatedAt),
+		Name:      arg.Name,
+		IsAdmin:   arg.IsAdmin,
+	}
+	m.users = append(m.users, user)
+	return user, nil
+}
+
+func (m *Memory) GetUser(ctx context.Context, name string) (User, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return find(m.users, func(u User) bool { return u.Name == name })
+}
+
+func (m *Memory) GetUserByID(ctx context.Context, id uuid.UUID) (User, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return find(m.users, func(u User) bool { return u.ID == id })
+}
+
+func (m *Memory) GetUsers(ctx context.Context) ([]User, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return slices.Clone(m.users), nil
+}
+
+func (m *Memory) SetUserAdmin(ctx context.Context, arg SetUserAdminParams) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return update(m.users, func(u User) bool { return u.ID == arg.ID }, func(u *User) {
+		u.IsAdmin, u.UpdatedAt = arg.IsAdmin, memNullTime(arg.UpdatedAt)
+	}), nil
+}
+
+func (m *Memory) DeleteUser(ctx context.Context, id uuid.UUID) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.deleteUsers(func(u User) bool { return u.ID == id }), nil
+}
+
+func (m *Memory) DeleteUsers(ctx context.Context) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.deleteUsers(func(User) bool { return true })
+	return nil
+}
+
+func (m *Memory) CreateFeed(ctx context.Context, arg CreateFeedParams) (Feed, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	switch {
+	case m.feedExists(arg.ID):
+		return Feed{}, errDuplicate("feeds_pkey")
+	case exists(m.feeds, func(f Feed) bool { return sameText(f.Url, arg.Url) }):
+		return Feed{}, errDuplicate("feeds_url_key")
+	case exists(m.feeds, func(f Feed) bool { return sameText(f.UrlKey, arg.UrlKey) }):
+		return Feed{}, errDuplicate("feeds_url_key_key")
+	case arg.UserID.Valid && !m.userExists(arg.UserID.UUID):
+		return Feed{}, errForeignKey("feeds_user_id_fkey")
+	}
+	feed := Feed{
+		ID:        arg.ID,
+		CreatedAt: memNullTime(arg.CreatedAt),
+		UpdatedAt: memNullTime(arg.UpdatedAt),
+		Name:      arg.Name,
+		Url:       arg.Url,
+		UserID:    arg.UserID,
+		UrlKey:    arg.UrlKey,
+		Kind:      arg.Kind,
+	}
+	m.feeds = append(m.feeds, feed)
+	return feed, nil
+}
+
+func (m *Memory) GetFeedByURL(ctx context.Context, url sql.NullString) (Feed, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return find(m.feeds, func(f Feed) bool { return sameText(f.Url, url) })
+}
+
+func (m *Memory) GetFeedByURLKey(ctx context.Context, urlKey sql.NullString) (Feed, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return find(m.feeds, func(f Feed) bool { return sameText(f.UrlKey, urlKey) })
+}
+
+// LockFeedByURLKey needs no lock of its own, InTx runs one transaction at a time.
+func (m *Memory) LockFeedByURLKey(ctx context.Context, urlKey sql.NullString) (Feed, error) {
+	return m.GetFeedByURLKey(ctx, urlKey)
+}
+
+func (m *Memory) GetFeeds(ctx context.Context) ([]Feed, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return slices.Clone(m.feeds), nil
+}
+
+func (m *Memory) GetNextFeedToFetch(ctx context.Context) (Feed, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if len(m.feeds) == 0 {
+		return Feed{}, sql.ErrNoRows
+	}
+	return slices.MinFunc(m.feeds, func(a, b Feed) int {
+		return compareNulls(a.LastFetchedAt.Time, b.LastFetchedAt.Time, a.LastFetchedAt.Valid, b.LastFetchedAt.Valid, true, time.Time.Compare)
+	}), nil
+}
+
+func (m *Memory) MarkFeedFetched(ctx context.Context, arg MarkFeedFetchedParams) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	update(m.feeds, func(f Feed) bool { return f.ID == arg.ID }, func(f *Feed) {
+		f.UpdatedAt = memNullTime(arg.UpdatedAt)
+		f.LastFetchedAt = f.UpdatedAt
+	})
+	return nil
+}
+
+func (m *Memory) SetFeedSiteURL(ctx context.Context, arg SetFeedSiteURLParams) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	update(m.feeds, func(f Feed) bool { return f.ID == arg.ID }, func(f *Feed) {
+		f.SiteUrl, f.UpdatedAt = arg.SiteUrl, memNullTime(arg.UpdatedAt)
+	})
+	return nil
+}
+
+func (m *Memory) RenameFeed(ctx context.Context, arg RenameFeedParams) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return update(m.feeds, func(f Feed) bool { return f.ID == arg.ID }, func(f *Feed) {
+		f.Name, f.UpdatedAt = arg.Name, memNullTime(arg.UpdatedAt)
+	}), nil
+}
+
+func (m *Memory) SetFeedURL(ctx context.Context, arg SetFeedURLParams) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	other := func(f Feed) bool { return f.ID != arg.ID }
+	switch {
+	case exists(m.feeds, func(f Feed) bool { return other(f) && sameText(f.Url, arg.Url) }):
+		return 0, errDuplicate("feeds_url_key")
+	case exists(m.feeds, func(f Feed) bool { return other(f) && sameText(f.UrlKey, arg.UrlKey) }):
+		return 0, errDuplicate("feeds_url_key_key")
+	}
+	return update(m.feeds, func(f Feed) bool { return f.ID == arg.ID }, func(f *Feed) {
+		f.Url, f.UrlKey, f.UpdatedAt = arg.Url, arg.UrlKey, memNullTime(arg.UpdatedAt)
+	}), nil
+}
+
+func (m *Memory) SetFeedOwner(ctx context.Context, arg SetFeedOwnerParams) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if arg.UserID.Valid && !m.userExists(arg.UserID.UUID) {
+		return 0, errForeignKey("feeds_user_id_fkey")
+	}
+	return update(m.feeds, func(f Feed) bool { return f.ID == arg.ID }, func(f *Feed) {
+		f.UserID, f.UpdatedAt = arg.UserID, memNullTime(arg.UpdatedAt)
+	}), nil
+}
+
+func (m *Memory) DeleteFeed(ctx context.Context, id uuid.UUID) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.deleteFeeds(func(f Feed) bool { return f.ID == id }), nil
+}
+
+func (m *Memory) DeleteFeeds(ctx context.Context) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.deleteFeeds(func(Feed) bool { return true })
+	return nil
+}
+
+func (m *Memory) DeleteUnsharedFeeds(ctx context.Context, userID uuid.UUID) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	shared := func(feed Feed) bool {
+		return exists(m.follows, func(f FeedFollow) bool {
+			return sameID(f.FeedID, uuid.NullUUID{UUID: feed.ID, Valid: true}) && f.UserID.Valid && f.UserID.UUID != userID
+		})
+	}
+	return m.deleteFeeds(func(f Feed) bool { return f.UserID.Valid && f.UserID.UUID == userID && !shared(f) }), nil
+}
+
+func (m *Memory) CreateFeedScraper(ctx context.Context, arg CreateFeedScraperParams) (FeedScraper, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if exists(m.scrapers, func(s FeedScraper) bool { return s.FeedID == arg.FeedID }) {
+		return FeedScraper{}, errDuplicate("feed_scrapers_pkey")
+	}
+	if !m.feedExists(arg.FeedID) {
+		return FeedScraper{}, errForeignKey("feed_scrapers_feed_id_fkey")
+	}
+	scraper := FeedScraper(arg)
+	m.scrapers = append(m.scrapers, scraper)
+	return scraper, nil
+}
+
+func (m *Memory) GetFeedScraper(ctx context.Context, feedID uuid.UUID) (FeedScraper, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return find(m.scrapers, func(s FeedScraper) bool { return s.FeedID == feedID })
+}
+
+func (m *Memory) CreateFeedWatch(ctx context.Context, arg CreateFeedWatchParams) (FeedWatch, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if exists(m.watches, func(w FeedWatch) bool { return w.FeedID == arg.FeedID }) {
+		return FeedWatch{}, errDuplicate("feed_watches_pkey")
+	}
+	if !m.feedExists(arg.FeedID) {
+		return FeedWatch{}, errForeignKey("feed_watches_feed_id_fkey")
+	}
+	watch := FeedWatch(arg)
+	m.watches = append(m.watches, watch)
+	return watch, nil
+}
+
+func (m *Memory) GetFeedWatch(ctx context.Context, feedID uuid.UUID) (FeedWatch, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return find(m.watches, func(w FeedWatch) bool { return w.FeedID == feedID })
+}
+
+func (m *Memory) CreatePageSnapshot(ctx context.Context, arg CreatePageSnapshotParams) (PageSnapshot, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if exists(m.snapshots, func(s PageSnapshot) bool { return s.ID == arg.ID }) {
+		return PageSnapshot{}, errDuplicate("page_snapshots_pkey")
+	}
+	if !m.feedExists(arg.FeedID) {
+		return PageSnapshot{}, errForeignKey("page_snapshots_feed_id_fkey")
+	}
+	snapshot := PageSnapshot{ID: arg.ID, CreatedAt: memTime(arg.CreatedAt), FeedID: arg.FeedID, Content: arg.Content}
+	m.snapshots = append(m.snapshots, snapshot)
+	return snapshot, nil
+}
+
+func (m *Memory) GetLatestPageSnapshot(ctx context.Context, feedID uuid.UUID) (PageSnapshot, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var latest []PageSnapshot
+	for _, snapshot := range m.snapshots {
+		if snapshot.FeedID == feedID {
+			latest = append(latest, snapshot)
+		}
+	}
+	if len(latest) == 0 {
+		return PageSnapshot{}, sql.ErrNoRows
+	}
+	return slices.MaxFunc(latest, func(a, b PageSnapshot) int { return a.CreatedAt.Compare(b.CreatedAt) }), nil
+}
+
+func (m *Memory) CreateFeedFollow(ctx context.Context, arg CreateFeedFollowParams) (CreateFeedFollowRow, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	switch {
+	case exists(m.follows, func(f FeedFollow) bool { return f.ID == arg.ID }):
+		return CreateFeedFollowRow{}, errDuplicate("feed_follows_pkey")
+	case exists(m.follows, func(f FeedFollow) bool { return sameID(f.UserID, arg.UserID) && sameID(f.FeedID, arg.FeedID) }):
+		return CreateFeedFollowRow{}, errDuplicate("feed_follows_user_id_feed_id_key")
+	case arg.UserID.Valid && !m.userExists(arg.UserID.UUID):
+		return CreateFeedFollowRow{}, errForeignKey("feed_follows_user_id_fkey")
+	case arg.FeedID.Valid && !m.feedExists(arg.FeedID.UUID):
+		return CreateFeedFollowRow{}, errForeignKey("feed_follows_feed_id_fkey")
+	}
+	follow := FeedFollow{
+		ID:          arg.ID,
+		CreatedAt:   memNullTime(arg.CreatedAt),
+		UpdatedAt:   memNullTime(arg.UpdatedAt),
+		UserID:      arg.UserID,
+		FeedID:      arg.FeedID,
+		DefaultSort: "newest",
+	}
+	m.follows = append(m.follows, follow)
+
+	// the row joins the user and the feed, a follow without them has none
+	user, err := find(m.users, func(u User) bool { return arg.UserID.Valid && u.ID == arg.UserID.UUID })
+	if err != nil || !arg.FeedID.Valid {
+		return CreateFeedFollowRow{}, sql.ErrNoRows
+	}
+	return CreateFeedFollowRow{
+		ID:          follow.ID,
+		CreatedAt:   follow.CreatedAt,
+		UpdatedAt:   follow.UpdatedAt,
+		UserID:      follow.UserID,
+		FeedID:      follow.FeedID,
+		FolderID:    follow.FolderID,
+		DisplayName: follow.DisplayName,
+		Hidden:      follow.Hidden,
+		Notify:      follow.Notify,
+		FullText:    follow.FullText,
+		DefaultSort: follow.DefaultSort,
+		UserName:    user.Name,
+		FeedName:    m.feedName(follow.FeedID),
+	}, nil
+}
+
+func (m *Memory) DeleteFeedFollow(ctx context.Context, arg DeleteFeedFollowParams) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	remove(&m.follows, func(f FeedFollow) bool { return sameID(f.UserID, arg.UserID) && sameID(f.FeedID, arg.FeedID) })
+	return nil
+}
+
+func (m *Memory) GetFeedFollow(ctx context.Context, arg GetFeedFollowParams) (FeedFollow, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return find(m.follows, func(f FeedFollow) bool { return sameID(f.UserID, arg.UserID) && sameID(f.FeedID, arg.FeedID) })
+}
+
+func (m *Memory) GetFeedFollowsForUser(ctx context.Context, userID uuid.NullUUID) ([]GetFeedFollowsForUserRow, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var rows []GetFeedFollowsForUserRow
+	for _, follow := range m.follows {
+		if !sameID(follow.UserID, userID) {
+			continue
+		}
+		user, err := find(m.users, func(u User) bool { return u.ID == follow.UserID.UUID })
+		if err != nil {
+			continue
+		}
+		feed, err := find(m.feeds, func(f Feed) bool { return follow.FeedID.Valid && f.ID == follow.FeedID.UUID })
+		if err != nil {
+			continue
+		}
+		row := GetFeedFollowsForUserRow{
+			ID:          follow.ID,
+			CreatedAt:   follow.CreatedAt,
+			UpdatedAt:   follow.UpdatedAt,
+			UserID:      follow.UserID,
+			FeedID:      follow.FeedID,
+			FolderID:    follow.FolderID,
+			DisplayName: follow.DisplayName,
+			Hidden:      follow.Hidden,
+			Notify:      follow.Notify,
+			FullText:    follow.FullText,
+			DefaultSort: follow.DefaultSort,
+			UserName:    user.Name,
+			FeedName:    feed.Name,
+			FeedUrl:     feed.Url,
+			FeedSiteUrl: feed.SiteUrl,
+		}
+		if folder, err := find(m.folders, func(f Folder) bool { return follow.FolderID.Valid && f.ID == follow.FolderID.UUID }); err == nil {
+			row.FolderName = sql.NullString{String: folder.Name, Valid: true}
+		}
+		rows = append(rows, row)
+	}
+	// folders ASC NULLS FIRST, then feed names ASC with the nulls last
+	slices.SortStableFunc(rows, func(a, b GetFeedFollowsForUserRow) int {
+		if c := compareNulls(a.FolderName.String, b.FolderName.String, a.FolderName.Valid, b.FolderName.Valid, true, cmp.Compare[string]); c != 0 {
+			return c
+		}
+		return compareNulls(a.FeedName.String, b.FeedName.String, a.FeedName.Valid, b.FeedName.Valid, false, cmp.Compare[string])
+	})
+	return rows, nil
+}
+
+func (m *Memory) UpdateFeedFollowSettings(ctx context.Context, arg UpdateFeedFollowSettingsParams) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return update(m.follows, func(f FeedFollow) bool { return sameID(f.UserID, arg.UserID) && sameID(f.FeedID, arg.FeedID) }, func(f *FeedFollow) {
+		f.DisplayName, f.Hidden, f.Notify, f.FullText = arg.DisplayName, arg.Hidden, arg.Notify, arg.FullText
+		f.DefaultSort = arg.DefaultSort
+		f.UpdatedAt = memNullTime(arg.UpdatedAt)
+	}), nil
+}
+
+func (m *Memory) FeedWantsFullText(ctx context.Context, feedID uuid.NullUUID) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return exists(m.follows, func(f FeedFollow) bool { return sameID(f.FeedID, feedID) && f.FullText }), nil
+}
+
+func (m *Memory) GetNotifyFollowers(ctx context.Context, feedID uuid.NullUUID) ([]GetNotifyFollowersRow, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var rows []GetNotifyFollowersRow
+	for _, follow := range m.follows {
+		if !sameID(follow.FeedID, feedID) || !follow.Notify {
+			continue
+		}
+		user, err := find(m.users, func(u User) bool { return follow.UserID.Valid && u.ID == follow.UserID.UUID })
+		if err != nil {
+			continue
+		}
+		name := follow.DisplayName
+		if !name.Valid {
+			name = m.feedName(follow.FeedID)
+		}
+		rows = append(rows, GetNotifyFollowersRow{UserName: user.Name, FeedName: name.String})
+	}
+	return rows, nil
+}
+
+func (m *Memory) CreateFolder(ctx context.Context, arg CreateFolderParams) (Folder, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	switch {
+	case exists(m.folders, func(f Folder) bool { return f.ID == arg.ID }):
+		return Folder{}, errDuplicate("folders_pkey")
+	case exists(m.folders, func(f Folder) bool { return f.UserID == arg.UserID && f.Name == arg.Name }):
+		return Folder{}, errDuplicate("folders_user_id_name_key")
+	case !m.userExists(arg.UserID):
+		return Folder{}, errForeignKey("folders_user_id_fkey")
+	}
+	folder := Folder{
+		ID:        arg.ID,
+		CreatedAt: memNullTime(arg.CreatedAt),
+		UpdatedAt: memNullTime(arg.UpdatedAt),
+		UserID:    arg.UserID,
+		Name:      arg.Name,
+	}
+	m.folders = append(m.folders, folder)
+	return folder, nil
+}
+
+func (m *Memory) GetFolderByName(ctx context.Context, arg GetFolderByNameParams) (Folder, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return find(m.folders, func(f Folder) bool { return f.UserID == arg.UserID && f.Name == arg.Name })
+}
+
+func (m *Memory) GetFoldersForUser(ctx context.Context, userID uuid.UUID) ([]Folder, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var folders []Folder
+	for _, folder := range m.folders {
+		if folder.UserID == userID {
+			folders = append(folders, folder)
+		}
+	}
+	slices.SortStableFunc(folders, func(a, b Folder) int { return cmp.Compare(a.Name, b.Name) })
+	return folders, nil
+}
+
+func (m *Memory) RenameFolder(ctx context.Context, arg RenameFolderParams) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	match := func(f Folder) bool { return f.UserID == arg.UserID && f.Name == arg.Name }
+	if arg.NewName != arg.Name && exists(m.folders, match) &&
+		exists(m.folders, func(f Folder) bool { return f.UserID == arg.UserID && f.Name == arg.NewName }) {
+		return 0, errDuplicate("folders_user_id_name_key")
+	}
+	return update(m.folders, match, func(f *Folder) {
+		f.Name, f.UpdatedAt = arg.NewName, memNullTime(arg.UpdatedAt)
+	}), nil
+}
+
+func (m *Memory) DeleteFolder(ctx context.Context, arg DeleteFolderParams) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.deleteFolders(func(f Folder) bool { return f.UserID == arg.UserID && f.Name == arg.Name }), nil
+}
+
+func (m *Memory) SetFeedFollowFolder(ctx context.Context, arg SetFeedFollowFolderParams) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	match := func(f FeedFollow) bool { return sameID(f.UserID, arg.UserID) && sameID(f.FeedID, arg.FeedID) }
+	if arg.FolderID.Valid && exists(m.follows, match) &&
+		!exists(m.folders, func(f Folder) bool { return f.ID == arg.FolderID.UUID }) {
+		return 0, errForeignKey("feed_follows_folder_id_fkey")
+	}
+	return update(m.follows, match, func(f *FeedFollow) {
+		f.FolderID, f.UpdatedAt = arg.FolderID, memNullTime(arg.UpdatedAt)
+	}), nil
+}
@@ -0,0 +1,576 @@
+package database
+
+import (
+	"cmp"
+	"context"
+	"database/sql"
+	"slices"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/google/uuid"
+)
+
+// newestFirst orders posts by published_at DESC, which puts the nulls first.
+func newestFirst(a, b Post) int {
+	return compareNulls(b.PublishedAt.Time, a.PublishedAt.Time, b.PublishedAt.Valid, a.PublishedAt.Valid, false, time.Time.Compare)
+}
+
+func (m *Memory) isRead(userID, postID uuid.UUID) bool {
+	return exists(m.reads, func(r PostRead) bool { return r.UserID == userID && r.PostID == postID })
+}
+
+// followed reports whether the user follows the feed of the post.
+func (m *Memory) followed(userID uuid.UUID, post Post) bool {
+	return exists(m.follows, func(f FeedFollow) bool {
+		return f.UserID.Valid && f.UserID.UUID == userID && sameID(f.FeedID, post.FeedID)
+	})
+}
+
+func (m *Memory) CreatePost(ctx context.Context, arg CreatePostParams) (Post, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	switch {
+	case m.postExists(arg.ID):
+		return Post{}, errDuplicate("posts_pkey")
+	case exists(m.posts, func(p Post) bool { return sameText(p.Url, arg.Url) }):
+		// ON CONFLICT (url) DO NOTHING returns no row
+		return Post{}, sql.ErrNoRows
+	case arg.FeedID.Valid && !m.feedExists(arg.FeedID.UUID):
+		return Post{}, errForeignKey("posts_feed_id_fkey")
+	}
+	post := Post{
+		ID:          arg.ID,
+		CreatedAt:   memNullTime(arg.CreatedAt),
+		UpdatedAt:   memNullTime(arg.UpdatedAt),
+		Title:       arg.Title,
+		Url:         arg.Url,
+		Description: arg.Description,
+		PublishedAt: memNullTime(arg.PublishedAt),
+		FeedID:      arg.FeedID,
+		Author:      arg.Author,
+	}
+	m.posts = append(m.posts, post)
+	return post, nil
+}
+
+func (m *Memory) UpdatePostContent(ctx context.Context, arg UpdatePostContentParams) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	update(m.posts, func(p Post) bool { return p.ID == arg.ID }, func(p *Post) {
+		p.Content, p.UpdatedAt = arg.Content, memNullTime(arg.UpdatedAt)
+	})
+	return nil
+}
+
+func (m *Memory) GetPostsForUser(ctx context.Context, arg GetPostsForUserParams) ([]Post, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var posts []Post
+	if !m.userExists(arg.ID) {
+		return posts, nil
+	}
+	for _, post := range m.posts {
+		if m.followed(arg.ID, post) {
+			posts = append(posts, post)
+		}
+	}
+	slices.SortStableFunc(posts, newestFirst)
+	return posts[:min(len(posts), int(max(arg.Limit, 0)))], nil
+}
+
+func (m *Memory) GetPostsByIDPrefix(ctx context.Context, arg GetPostsByIDPrefixParams) ([]GetPostsByIDPrefixRow, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	kept := func(post Post) bool {
+		return m.followed(arg.UserID, post) ||
+			exists(m.stars, func(s PostStar) bool { return s.UserID == arg.UserID && s.PostID == post.ID }) ||
+			exists(m.readLater, func(r ReadLater) bool { return r.UserID == arg.UserID && r.PostID == post.ID })
+	}
+	var rows []GetPostsByIDPrefixRow
+	for _, post := range m.posts {
+		if strings.HasPrefix(post.ID.String(), arg.Prefix) && kept(post) && len(rows) < 2 {
+			rows = append(rows, GetPostsByIDPrefixRow{Post: post, FeedName: m.feedName(post.FeedID)})
+		}
+	}
+	return rows, nil
+}
+
+func (m *Memory) PruneOrphanedPosts(ctx context.Context) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.deletePosts(func(p Post) bool {
+		return !p.FeedID.Valid &&
+			!exists(m.stars, func(s PostStar) bool { return s.PostID == p.ID }) &&
+			!exists(m.readLater, func(r ReadLater) bool { return r.PostID == p.ID })
+	}), nil
+}
+
+// markRead adds the reads that are not there yet and counts them.
+func (m *Memory) markRead(userID uuid.UUID, readAt time.Time, posts []Post) (int64, error) {
+	if !m.userExists(userID) && len(posts) > 0 {
+		return 0, errForeignKey("post_reads_user_id_fkey")
+	}
+	var n int64
+	for _, post := range posts {
+		if !m.isRead(userID, post.ID) {
+			m.reads = append(m.reads, PostRead{UserID: userID, PostID: post.ID, ReadAt: memTime(readAt)})
+			n++
+		}
+	}
+	return n, nil
+}
+
+func (m *Memory) MarkPostRead(ctx context.Context, arg MarkPostReadParams) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	post, err := find(m.posts, func(p Post) bool { return p.ID == arg.PostID })
+	if err != nil {
+		return errForeignKey("post_reads_post_id_fkey")
+	}
+	_, err = m.markRead(arg.UserID, arg.ReadAt, []Post{post})
+	return err
+}
+
+func (m *Memory) MarkPostUnread(ctx context.Context, arg MarkPostUnreadParams) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	remove(&m.reads, func(r PostRead) bool { return r.UserID == arg.UserID && r.PostID == arg.PostID })
+	return nil
+}
+
+func (m *Memory) MarkFeedPostsReadBefore(ctx context.Context, arg MarkFeedPostsReadBeforeParams) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	before := memTime(arg.Before)
+	var posts []Post
+	for _, post := range m.posts {
+		if post.FeedID.Valid && post.FeedID.UUID == arg.FeedID && post.PublishedAt.Valid && post.PublishedAt.Time.Before(before) {
+			posts = append(posts, post)
+		}
+	}
+	return m.markRead(arg.UserID, arg.ReadAt, posts)
+}
+
+func (m *Memory) MarkAllPostsRead(ctx context.Context, arg MarkAllPostsReadParams) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var posts []Post
+	for _, post := range m.posts {
+		if m.followed(arg.UserID, post) {
+			posts = append(posts, post)
+		}
+	}
+	return m.markRead(arg.UserID, arg.ReadAt, posts)
+}
+
+func (m *Memory) GetUnreadCountsForUser(ctx context.Context, userID uuid.NullUUID) ([]GetUnreadCountsForUserRow, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var rows []GetUnreadCountsForUserRow
+	for _, follow := range m.follows {
+		if !sameID(follow.UserID, userID) {
+			continue
+		}
+		var unread int64
+		for _, post := range m.posts {
+			if sameID(post.FeedID, follow.FeedID) && !m.isRead(userID.UUID, post.ID) {
+				unread++
+			}
+		}
+		if unread > 0 {
+			rows = append(rows, GetUnreadCountsForUserRow{FeedID: follow.FeedID, Unread: unread})
+		}
+	}
+	return rows, nil
+}
+
+func (m *Memory) StarPost(ctx context.Context, arg StarPostParams) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	switch {
+	case exists(m.stars, func(s PostStar) bool { return s.UserID == arg.UserID && s.PostID == arg.PostID }):
+		return nil
+	case !m.userExists(arg.UserID):
+		return errForeignKey("post_stars_user_id_fkey")
+	case !m.postExists(arg.PostID):
+		return errForeignKey("post_stars_post_id_fkey")
+	}
+	m.stars = append(m.stars, PostStar{UserID: arg.UserID, PostID: arg.PostID, StarredAt: memTime(arg.StarredAt)})
+	return nil
+}
+
+func (m *Memory) UnstarPost(ctx context.Context, arg UnstarPostParams) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return remove(&m.stars, func(s PostStar) bool { return s.UserID == arg.UserID && s.PostID == arg.PostID }), nil
+}
+
+func (m *Memory) GetStarredPosts(ctx context.Context, userID uuid.UUID) ([]GetStarredPostsRow, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var rows []GetStarredPostsRow
+	for _, star := range m.stars {
+		if star.UserID != userID {
+			continue
+		}
+		post, err := find(m.posts, func(p Post) bool { return p.ID == star.PostID })
+		if err != nil {
+			continue
+		}
+		rows = append(rows, GetStarredPostsRow{Post: post, FeedName: m.feedName(post.FeedID), StarredAt: star.StarredAt})
+	}
+	slices.SortStableFunc(rows, func(a, b GetStarredPostsRow) int { return b.StarredAt.Compare(a.StarredAt) })
+	return rows, nil
+}
+
+func (m *Memory) AddToReadLater(ctx context.Context, arg AddToReadLaterParams) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var last int32
+	for _, later := range m.readLater {
+		if later.UserID != arg.UserID {
+			continue
+		}
+		if later.PostID == arg.PostID {
+			return 0, nil
+		}
+		last = max(last, later.Position)
+	}
+	switch {
+	case !m.userExists(arg.UserID):
+		return 0, errForeignKey("read_later_user_id_fkey")
+	case !m.postExists(arg.PostID):
+		return 0, errForeignKey("read_later_post_id_fkey")
+	}
+	m.readLater = append(m.readLater, ReadLater{UserID: arg.UserID, PostID: arg.PostID, Position: last + 1, AddedAt: memTime(arg.AddedAt)})
+	return 1, nil
+}
+
+func (m *Memory) RemoveFromReadLater(ctx context.Context, arg RemoveFromReadLaterParams) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return remove(&m.readLater, func(r ReadLater) bool { return r.UserID == arg.UserID && r.PostID == arg.PostID }), nil
+}
+
+func (m *Memory) GetReadLater(ctx context.Context, userID uuid.UUID) ([]GetReadLaterRow, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var rows []GetReadLaterRow
+	for _, later := range m.readLater {
+		if later.UserID != userID {
+			continue
+		}
+		post, err := find(m.posts, func(p Post) bool { return p.ID == later.PostID })
+		if err != nil {
+			continue
+		}
+		rows = append(rows, GetReadLaterRow{Post: post, FeedName: m.feedName(post.FeedID), Position: later.Position})
+	}
+	slices.SortStableFunc(rows, func(a, b GetReadLaterRow) int { return cmp.Compare(a.Position, b.Position) })
+	return rows, nil
+}
+
+// memorySearch is a websearch query matched against the words of posts.
+type memorySearch struct {
+	// groups are the words of every term of every group
+	groups   [][][]string
+	excluded [][]string
+}
+
+// words splits text into lowercase words, the way the search indexes do
+// before stemming.
+func words(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+func newMemorySearch(query string) memorySearch {
+	groups, excluded := websearchTerms(query)
+	var search memorySearch
+	for _, terms := range groups {
+		var group [][]string
+		for _, term := range terms {
+			if w := words(term); len(w) > 0 {
+				group = append(group, w)
+			}
+		}
+		if len(group) > 0 {
+			search.groups = append(search.groups, group)
+		}
+	}
+	for _, term := range excluded {
+		if w := words(term); len(w) > 0 {
+			search.excluded = append(search.excluded, w)
+		}
+	}
+	return search
+}
+
+// count is how many times the words of a term follow each other in text.
+func count(text, term []string) int {
+	n := 0
+	for i := 0; i+len(term) <= len(text); i++ {
+		if slices.Equal(text[i:i+len(term)], term) {
+			n++
+		}
+	}
+	return n
+}
+
+// rank scores a post, 0 when it does not match. Title matches weigh more,
+// like the title weight of the indexes.
+func (q memorySearch) rank(post Post) float32 {
+	if len(q.groups) == 0 {
+		return 0
+	}
+	title := words(post.Title.String)
+	body := words(post.Description.String + " " + post.Content.String)
+	for _, term := range q.excluded {
+		if count(title, term)+count(body, term) > 0 {
+			return 0
+		}
+	}
+	var rank float32
+	for _, group := range q.groups {
+		var hits float32
+		for _, term := range group {
+			hits += 2.5*float32(count(title, term)) + float32(count(body, term))
+		}
+		if hits == 0 {
+			return 0
+		}
+		rank += hits
+	}
+	return rank
+}
+
+// snippet is up to 30 words of the post around its first match, with the
+// matching words between [[ and ]].
+func (q memorySearch) snippet(post Post) string {
+	text := post.Title.String
+	for _, s := range []sql.NullString{post.Description, post.Content} {
+		if s.String != "" {
+			text = s.String
+		}
+	}
+	matching := map[string]bool{}
+	for _, group := range q.groups {
+		for _, term := range group {
+			for _, word := range term {
+				matching[word] = true
+			}
+		}
+	}
+	fields := strings.Fields(text)
+	first := -1
+	for i, field := range fields {
+		for _, word := range words(field) {
+			if matching[word] {
+				fields[i] = "[[" + field + "]]"
+				if first < 0 {
+					first = i
+				}
+				break
+			}
+		}
+	}
+	start := max(first-10, 0)
+	return strings.Join(fields[start:min(start+30, len(fields))], " ")
+}
+
+func (m *Memory) SearchPosts(ctx context.Context, arg SearchPostsParams) ([]SearchPostsRow, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	search := newMemorySearch(arg.Query)
+	var rows []SearchPostsRow
+	for _, post := range m.posts {
+		rank := search.rank(post)
+		if rank == 0 {
+			continue
+		}
+		follow, err := find(m.follows, func(f FeedFollow) bool { return sameID(f.FeedID, post.FeedID) && sameID(f.UserID, arg.UserID) })
+		followed := err == nil
+		switch {
+		case !arg.AllFeeds && !followed:
+			continue
+		case arg.FeedID.Valid && !sameID(post.FeedID, arg.FeedID):
+			continue
+		case arg.Since.Valid && !(post.PublishedAt.Valid && !post.PublishedAt.Time.Before(memTime(arg.Since.Time))):
+			continue
+		case arg.Until.Valid && !(post.PublishedAt.Valid && post.PublishedAt.Time.Before(memTime(arg.Until.Time))):
+			continue
+		}
+		name := follow.DisplayName
+		if !name.Valid {
+			name = m.feedName(post.FeedID)
+		}
+		rows = append(rows, SearchPostsRow{Post: post, FeedName: name.String, Rank: rank, Snippet: search.snippet(post)})
+	}
+	slices.SortStableFunc(rows, func(a, b SearchPostsRow) int {
+		if c := cmp.Compare(b.Rank, a.Rank); c != 0 {
+			return c
+		}
+		return newestFirst(a.Post, b.Post)
+	})
+	return rows[:min(len(rows), int(max(arg.MaxPosts, 0)))], nil
+}
+
+func (m *Memory) CreateSavedSearch(ctx context.Context, arg CreateSavedSearchParams) (SavedSearch, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	switch {
+	case exists(m.searches, func(s SavedSearch) bool { return s.ID == arg.ID }):
+		return SavedSearch{}, errDuplicate("saved_searches_pkey")
+	case exists(m.searches, func(s SavedSearch) bool { return s.UserID == arg.UserID && s.Name == arg.Name }):
+		return SavedSearch{}, errDuplicate("saved_searches_user_id_name_key")
+	case !m.userExists(arg.UserID):
+		return SavedSearch{}, errForeignKey("saved_searches_user_id_fkey")
+	}
+	search := SavedSearch{
+		ID:        arg.ID,
+		CreatedAt: memNullTime(arg.CreatedAt),
+		UpdatedAt: memNullTime(arg.UpdatedAt),
+		UserID:    arg.UserID,
+		Name:      arg.Name,
+		Query:     arg.Query,
+	}
+	m.searches = append(m.searches, search)
+	return search, nil
+}
+
+func (m *Memory) GetSavedSearchByName(ctx context.Context, arg GetSavedSearchByNameParams) (SavedSearch, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return find(m.searches, func(s SavedSearch) bool { return s.UserID == arg.UserID && s.Name == arg.Name })
+}
+
+func (m *Memory) GetSavedSearchesForUser(ctx context.Context, userID uuid.UUID) ([]GetSavedSearchesForUserRow, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var rows []GetSavedSearchesForUserRow
+	for _, s := range m.searches {
+		if s.UserID != userID {
+			continue
+		}
+		search := newMemorySearch(s.Query)
+		var unread int64
+		for _, post := range m.posts {
+			if m.followed(userID, post) && !m.isRead(userID, post.ID) && search.rank(post) > 0 {
+				unread++
+			}
+		}
+		rows = append(rows, GetSavedSearchesForUserRow{
+			ID:        s.ID,
+			CreatedAt: s.CreatedAt,
+			UpdatedAt: s.UpdatedAt,
+			UserID:    s.UserID,
+			Name:      s.Name,
+			Query:     s.Query,
+			Unread:    unread,
+		})
+	}
+	slices.SortStableFunc(rows, func(a, b GetSavedSearchesForUserRow) int { return cmp.Compare(a.Name, b.Name) })
+	return rows, nil
+}
+
+func (m *Memory) DeleteSavedSearch(ctx context.Context, arg DeleteSavedSearchParams) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return remove(&m.searches, func(s SavedSearch) bool { return s.UserID == arg.UserID && s.Name == arg.Name }), nil
+}
+
+// compareBrowseKeys orders the key values of two posts the way the keys sort them.
+func compareBrowseKeys(keys []sortKey, a, b []string) int {
+	for i, key := range keys {
+		c := strings.Compare(a[i], b[i])
+		if key.isTime {
+			at, _ := time.Parse(time.RFC3339Nano, a[i])
+			bt, _ := time.Parse(time.RFC3339Nano, b[i])
+			c = at.Compare(bt)
+		}
+		if key.desc {
+			c = -c
+		}
+		if c != 0 {
+			return c
+		}
+	}
+	return 0
+}
+
+// containsFold is ILIKE with a pattern matching s anywhere.
+func containsFold(text sql.NullString, s string) bool {
+	return text.Valid && strings.Contains(strings.ToLower(text.String), strings.ToLower(s))
+}
+
+func (m *Memory) Browse(ctx context.Context, arg BrowseParams) ([]BrowseRow, error) {
+	keys, cursor, err := browseOrder(arg)
+	if err != nil {
+		return nil, err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	search := newMemorySearch(arg.Search.String)
+	type keyed struct {
+		row  BrowseRow
+		keys []string
+	}
+	var rows []keyed
+	for _, follow := range m.follows {
+		if !follow.UserID.Valid || follow.UserID.UUID != arg.UserID || !m.feedExists(follow.FeedID.UUID) {
+			continue
+		}
+		if (follow.Hidden && !arg.IncludeHidden) || (arg.FolderID.Valid && !sameID(follow.FolderID, arg.FolderID)) {
+			continue
+		}
+		name := follow.DisplayName
+		if !name.Valid {
+			name = m.feedName(follow.FeedID)
+		}
+		for _, post := range m.posts {
+			switch {
+			case !sameID(post.FeedID, follow.FeedID):
+				continue
+			case arg.FeedID.Valid && !sameID(post.FeedID, arg.FeedID):
+				continue
+			case arg.Search.Valid && search.rank(post) == 0:
+				continue
+			case arg.Since.Valid && !(post.PublishedAt.Valid && !post.PublishedAt.Time.Before(memTime(arg.Since.Time))):
+				continue
+			case arg.Until.Valid && !(post.PublishedAt.Valid && post.PublishedAt.Time.Before(memTime(arg.Until.Time))):
+				continue
+			case arg.Author != "" && !containsFold(post.Author, arg.Author):
+				continue
+			case arg.Keyword != "" && !containsFold(post.Title, arg.Keyword) && !containsFold(post.Description, arg.Keyword):
+				continue
+			}
+			row := BrowseRow{
+				Post:      post,
+				FeedName:  name.String,
+				IsRead:    m.isRead(arg.UserID, post.ID),
+				IsStarred: exists(m.stars, func(s PostStar) bool { return s.UserID == arg.UserID && s.PostID == post.ID }),
+			}
+			if arg.UnreadOnly && row.IsRead {
+				continue
+			}
+			values := make([]string, len(keys))
+			for i, key := range keys {
+				values[i] = key.value(row)
+			}
+			if cursor != nil && compareBrowseKeys(keys, values, cursor) <= 0 {
+				continue
+			}
+			rows = append(rows, keyed{row, values})
+		}
+	}
+	slices.SortStableFunc(rows, func(a, b keyed) int { return compareBrowseKeys(keys, a.keys, b.keys) })
+
+	var page []BrowseRow
+	for _, row := range rows[:min(len(rows), int(max(arg.Limit, 0)))] {
+		page = append(page, row.row)
+	}
+	return page, nil
+}
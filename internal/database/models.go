@@ -6,6 +6,7 @@ package database
 
 import (
 	"database/sql"
+	"time"
 
 	"github.com/google/uuid"
 )
@@ -18,25 +19,96 @@ type Feed struct {
 	Url           sql.NullString
 	UserID        uuid.NullUUID
 	LastFetchedAt sql.NullTime
+	UrlKey        sql.NullString
+	Kind          string
+	SiteUrl       sql.NullString
 }
 
 type FeedFollow struct {
+	ID          uuid.UUID
+	CreatedAt   sql.NullTime
+	UpdatedAt   sql.NullTime
+	UserID      uuid.NullUUID
+	FeedID      uuid.NullUUID
+	FolderID    uuid.NullUUID
+	DisplayName sql.NullString
+	Hidden      bool
+	Notify      bool
+	FullText    bool
+	DefaultSort string
+}
+
+type FeedScraper struct {
+	FeedID          uuid.UUID
+	ItemSelector    string
+	TitleSelector   string
+	LinkSelector    string
+	DateSelector    sql.NullString
+	DateFormat      sql.NullString
+	SummarySelector sql.NullString
+}
+
+type FeedWatch struct {
+	FeedID    uuid.UUID
+	Selector  sql.NullString
+	Threshold float64
+}
+
+type Folder struct {
 	ID        uuid.UUID
 	CreatedAt sql.NullTime
 	UpdatedAt sql.NullTime
-	UserID    uuid.NullUUID
-	FeedID    uuid.NullUUID
+	UserID    uuid.UUID
+	Name      string
+}
+
+type PageSnapshot struct {
+	ID        uuid.UUID
+	CreatedAt time.Time
+	FeedID    uuid.UUID
+	Content   string
 }
 
 type Post struct {
-	ID          uuid.UUID
-	CreatedAt   sql.NullTime
-	UpdatedAt   sql.NullTime
-	Title       sql.NullString
-	Url         sql.NullString
-	Description sql.NullString
-	PublishedAt sql.NullTime
-	FeedID      uuid.NullUUID
+	ID           uuid.UUID
+	CreatedAt    sql.NullTime
+	UpdatedAt    sql.NullTime
+	Title        sql.NullString
+	Url          sql.NullString
+	Description  sql.NullString
+	PublishedAt  sql.NullTime
+	FeedID       uuid.NullUUID
+	Content      sql.NullString
+	SearchVector interface{}
+	Author       sql.NullString
+}
+
+type PostRead struct {
+	UserID uuid.UUID
+	PostID uuid.UUID
+	ReadAt time.Time
+}
+
+type PostStar struct {
+	UserID    uuid.UUID
+	PostID    uuid.UUID
+	StarredAt time.Time
+}
+
+type ReadLater struct {
+	UserID   uuid.UUID
+	PostID   uuid.UUID
+	Position int32
+	AddedAt  time.Time
+}
+
+type SavedSearch struct {
+	ID        uuid.UUID
+	CreatedAt sql.NullTime
+	UpdatedAt sql.NullTime
+	UserID    uuid.UUID
+	Name      string
+	Query     string
 }
 
 type User struct {
@@ -44,4 +116,5 @@ type User struct {
 	CreatedAt sql.NullTime
 	UpdatedAt sql.NullTime
 	Name      string
+	IsAdmin   bool
 }
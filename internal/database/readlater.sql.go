@@ -0,0 +1,106 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: readlater.sql
+
+package database
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const addToReadLater = `-- name: AddToReadLater :execrows
+INSERT INTO read_later (user_id, post_id, position, added_at)
+SELECT $1::uuid, $2::uuid, COALESCE(MAX(position), 0) + 1, $3::timestamp
+FROM read_later
+WHERE user_id = $1::uuid
+ON CONFLICT (user_id, post_id) DO NOTHING
+`
+
+type AddToReadLaterParams struct {
+	UserID  uuid.UUID
+	PostID  uuid.UUID
+	AddedAt time.Time
+}
+
+func (q *Queries) AddToReadLater(ctx context.Context, arg AddToReadLaterParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, addToReadLater, arg.UserID, arg.PostID, arg.AddedAt)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const getReadLater = `-- name: GetReadLater :many
+SELECT posts.id, posts.created_at, posts.updated_at, posts.title, posts.url, posts.description, posts.published_at, posts.feed_id, posts.content, posts.search_vector, posts.author, feeds.name AS feed_name, read_later.position
+FROM read_later
+INNER JOIN posts ON read_later.post_id = posts.id
+LEFT JOIN feeds ON posts.feed_id = feeds.id
+WHERE read_later.user_id = $1
+ORDER BY read_later.position ASC
+`
+
+type GetReadLaterRow struct {
+	Post     Post
+	FeedName sql.NullString
+	Position int32
+}
+
+func (q *Queries) GetReadLater(ctx context.Context, userID uuid.UUID) ([]GetReadLaterRow, error) {
+	rows, err := q.db.QueryContext(ctx, getReadLater, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetReadLaterRow
+	for rows.Next() {
+		var i GetReadLaterRow
+		if err := rows.Scan(
+			&i.Post.ID,
+			&i.Post.CreatedAt,
+			&i.Post.UpdatedAt,
+			&i.Post.Title,
+			&i.Post.Url,
+			&i.Post.Description,
+			&i.Post.PublishedAt,
+			&i.Post.FeedID,
+			&i.Post.Content,
+			&i.Post.SearchVector,
+			&i.Post.Author,
+			&i.FeedName,
+			&i.Position,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const removeFromReadLater = `-- name: RemoveFromReadLater :execrows
+DELETE FROM read_later
+WHERE user_id = $1 AND post_id = $2
+`
+
+type RemoveFromReadLaterParams struct {
+	UserID uuid.UUID
+	PostID uuid.UUID
+}
+
+func (q *Queries) RemoveFromReadLater(ctx context.Context, arg RemoveFromReadLaterParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, removeFromReadLater, arg.UserID, arg.PostID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
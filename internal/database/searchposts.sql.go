@@ -0,0 +1,97 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: searchposts.sql
+
+package database
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/google/uuid"
+)
+
+const searchPosts = `-- name: SearchPosts :many
+SELECT posts.id, posts.created_at, posts.updated_at, posts.title, posts.url, posts.description, posts.published_at, posts.feed_id, posts.content, posts.search_vector, posts.author,
+    COALESCE(feed_follows.display_name, feeds.name, '')::text AS feed_name,
+    ts_rank(posts.search_vector, websearch_to_tsquery('english', $1))::real AS rank,
+    ts_headline('english',
+        COALESCE(NULLIF(posts.content, ''), NULLIF(posts.description, ''), posts.title, ''),
+        websearch_to_tsquery('english', $1),
+        'StartSel=[[, StopSel=]], MaxWords=30, MinWords=10, MaxFragments=2'
+    )::text AS snippet
+FROM posts
+LEFT JOIN feeds ON posts.feed_id = feeds.id
+LEFT JOIN feed_follows ON feed_follows.feed_id = posts.feed_id AND feed_follows.user_id = $2
+WHERE posts.search_vector @@ websearch_to_tsquery('english', $1)
+    AND ($3::boolean OR feed_follows.user_id IS NOT NULL)
+    AND ($4::uuid IS NULL OR posts.feed_id = $4)
+    AND ($5::timestamp IS NULL OR posts.published_at >= $5)
+    AND ($6::timestamp IS NULL OR posts.published_at < $6)
+ORDER BY rank DESC, posts.published_at DESC
+LIMIT $7
+`
+
+type SearchPostsParams struct {
+	Query    string
+	UserID   uuid.NullUUID
+	AllFeeds bool
+	FeedID   uuid.NullUUID
+	Since    sql.NullTime
+	Until    sql.NullTime
+	MaxPosts int32
+}
+
+type SearchPostsRow struct {
+	Post     Post
+	FeedName string
+	Rank     float32
+	Snippet  string
+}
+
+func (q *Queries) SearchPosts(ctx context.Context, arg SearchPostsParams) ([]SearchPostsRow, error) {
+	rows, err := q.db.QueryContext(ctx, searchPosts,
+		arg.Query,
+		arg.UserID,
+		arg.AllFeeds,
+		arg.FeedID,
+		arg.Since,
+		arg.Until,
+		arg.MaxPosts,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []SearchPostsRow
+	for rows.Next() {
+		var i SearchPostsRow
+		if err := rows.Scan(
+			&i.Post.ID,
+			&i.Post.CreatedAt,
+			&i.Post.UpdatedAt,
+			&i.Post.Title,
+			&i.Post.Url,
+			&i.Post.Description,
+			&i.Post.PublishedAt,
+			&i.Post.FeedID,
+			&i.Post.Content,
+			&i.Post.SearchVector,
+			&i.Post.Author,
+			&i.FeedName,
+			&i.Rank,
+			&i.Snippet,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
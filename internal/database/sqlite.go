@@ -0,0 +1,199 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+
+	"modernc.org/sqlite"
+)
+
+// sqliteDriverName is the database/sql driver of sqlite databases. It wraps
+// the sqlite driver to store times the way postgres does, the queries are
+// the sqlite versions of sql/sqlite/queries run by SQLiteQueries.
+const sqliteDriverName = "gator-sqlite"
+
+// sqliteTime is how times are stored, like a postgres TIMESTAMP the wall
+// clock is kept and the zone dropped. Times in this format sort as text and
+// the sqlite driver reads them back as times for TIMESTAMP columns.
+const sqliteTime = "2006-01-02 15:04:05.999999999"
+
+// sqliteBase is the driver registered by modernc.org/sqlite, the functions
+// registered with sqlite.Register... are added to its connections.
+var sqliteBase driver.Driver
+
+func init() {
+	db, err := sql.Open("sqlite", "")
+	if err != nil {
+		panic(err)
+	}
+	sqliteBase = db.Driver()
+	sqlite.MustRegisterDeterministicScalarFunction("websearch_to_fts", 1, func(ctx *sqlite.FunctionContext, args []driver.Value) (driver.Value, error) {
+		query, _ := args[0].(string)
+		return websearchToFTS(query), nil
+	})
+	sql.Register(sqliteDriverName, sqliteDriver{})
+}
+
+type sqliteDriver struct{}
+
+func (sqliteDriver) Open(name string) (driver.Conn, error) {
+	conn, err := sqliteBase.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	base, ok := conn.(sqliteBaseConn)
+	if !ok {
+		conn.Close()
+		return nil, fmt.Errorf("the sqlite driver connection is a %T, it lacks context support", conn)
+	}
+	return sqliteConn{base}, nil
+}
+
+// sqliteBaseConn is what the connections of the sqlite driver implement.
+type sqliteBaseConn interface {
+	driver.Conn
+	driver.ConnPrepareContext
+	driver.ConnBeginTx
+	driver.ExecerContext
+	driver.QueryerContext
+	driver.Pinger
+	driver.SessionResetter
+	driver.Validator
+}
+
+// sqliteConn translates the arguments of every statement.
+type sqliteConn struct {
+	sqliteBaseConn
+}
+
+func (c sqliteConn) Prepare(query string) (driver.Stmt, error) {
+	return c.PrepareContext(context.Background(), query)
+}
+
+func (c sqliteConn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	stmt, err := c.sqliteBaseConn.PrepareContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	base, ok := stmt.(sqliteBaseStmt)
+	if !ok {
+		stmt.Close()
+		return nil, fmt.Errorf("the sqlite driver statement is a %T, it lacks context support", stmt)
+	}
+	return sqliteStmt{base}, nil
+}
+
+func (c sqliteConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	return c.sqliteBaseConn.ExecContext(ctx, query, sqliteArgs(args))
+}
+
+func (c sqliteConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	return c.sqliteBaseConn.QueryContext(ctx, query, sqliteArgs(args))
+}
+
+type sqliteBaseStmt interface {
+	driver.Stmt
+	driver.StmtExecContext
+	driver.StmtQueryContext
+}
+
+type sqliteStmt struct {
+	sqliteBaseStmt
+}
+
+func (s sqliteStmt) ExecContext(ctx context.Context, args []driver.NamedValue) (driver.Result, error) {
+	return s.sqliteBaseStmt.ExecContext(ctx, sqliteArgs(args))
+}
+
+func (s sqliteStmt) QueryContext(ctx context.Context, args []driver.NamedValue) (driver.Rows, error) {
+	return s.sqliteBaseStmt.QueryContext(ctx, sqliteArgs(args))
+}
+
+func sqliteArgs(args []driver.NamedValue) []driver.NamedValue {
+	out := make([]driver.NamedValue, len(args))
+	for i, arg := range args {
+		if t, ok := arg.Value.(time.Time); ok {
+			arg.Value = t.Format(sqliteTime)
+		}
+		out[i] = arg
+	}
+	return out
+}
+
+// websearchTerms parses a query in the syntax of postgres websearch_to_tsquery,
+// the syntax of gator searches: every word and "quoted phrase" must match, or
+// between two of them matches either one and a -word must not match. A post
+// matches when it has a term of every group and none of the excluded terms.
+func websearchTerms(query string) (groups [][]string, excluded []string) {
+	or := false
+	for rest := strings.TrimSpace(query); rest != ""; rest = strings.TrimSpace(rest) {
+		exclude := false
+		if rest[0] == '-' {
+			exclude, rest = true, rest[1:]
+		}
+		var term string
+		quoted := false
+		if phrase, ok := strings.CutPrefix(rest, `"`); ok {
+			term, rest, _ = strings.Cut(phrase, `"`)
+			quoted = true
+		} else {
+			end := strings.IndexFunc(rest, func(r rune) bool { return unicode.IsSpace(r) || r == '"' })
+			if end < 0 {
+				end = len(rest)
+			}
+			term, rest = rest[:end], rest[end:]
+		}
+
+		if !quoted && !exclude && strings.EqualFold(term, "or") {
+			or = len(groups) > 0
+			continue
+		}
+		if strings.TrimSpace(term) == "" {
+			continue
+		}
+		switch {
+		case exclude:
+			excluded = append(excluded, term)
+		case or:
+			groups[len(groups)-1] = append(groups[len(groups)-1], term)
+		default:
+			groups = append(groups, []string{term})
+		}
+		or = false
+	}
+	return groups, excluded
+}
+
+// websearchToFTS turns a websearch query into an fts5 query. fts5 can not
+// match every row, a query made only of excluded words matches nothing.
+func websearchToFTS(query string) string {
+	groups, excluded := websearchTerms(query)
+	if len(groups) == 0 {
+		return `""`
+	}
+	// quoted, words are never taken for fts5 operators or column names
+	quote := func(term string) string {
+		return `"` + strings.ReplaceAll(term, `"`, `""`) + `"`
+	}
+	clauses := make([]string, len(groups))
+	for i, terms := range groups {
+		quoted := make([]string, len(terms))
+		for j, term := range terms {
+			quoted[j] = quote(term)
+		}
+		clauses[i] = strings.Join(quoted, " OR ")
+		if len(terms) > 1 {
+			clauses[i] = "(" + clauses[i] + ")"
+		}
+	}
+	fts := strings.Join(clauses, " AND ")
+	for _, term := range excluded {
+		fts += " NOT " + quote(term)
+	}
+	return fts
+}
@@ -0,0 +1,380 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/google/uuid"
+	"github.com/o0n1x/gator/internal/database/sqlitedb"
+)
+
+// SQLiteQueries is the Store of sqlite databases. It runs the queries sqlc
+// generates from sql/sqlite/queries, the sqlite versions of the queries of
+// sql/queries, and returns the types of the postgres queries.
+type SQLiteQueries struct {
+	db DBTX
+	q  *sqlitedb.Queries
+}
+
+// NewSQLiteQueries returns the store of a sqlite database opened by Open.
+func NewSQLiteQueries(db DBTX) *SQLiteQueries {
+	return &SQLiteQueries{db: db, q: sqlitedb.New(db)}
+}
+
+// InTx runs fn on s in a transaction, committed when fn returns nil and
+// rolled back otherwise. A store already on a transaction runs fn on it.
+func (s *SQLiteQueries) InTx(ctx context.Context, fn func(tx Store) error) error {
+	db, ok := s.db.(beginner)
+	if !ok {
+		return fn(s)
+	}
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := fn(NewSQLiteQueries(tx)); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// convertRows converts the rows of a sqlite query to the rows of its
+// postgres version.
+func convertRows[S, T any](rows []S, err error, convert func(S) T) ([]T, error) {
+	if err != nil {
+		return nil, err
+	}
+	var items []T
+	for _, row := range rows {
+		items = append(items, convert(row))
+	}
+	return items, nil
+}
+
+func (s *SQLiteQueries) Browse(ctx context.Context, arg BrowseParams) ([]BrowseRow, error) {
+	return browse(ctx, s.db, SQLite, arg)
+}
+
+func (s *SQLiteQueries) AddToReadLater(ctx context.Context, arg AddToReadLaterParams) (int64, error) {
+	return s.q.AddToReadLater(ctx, sqlitedb.AddToReadLaterParams(arg))
+}
+
+func (s *SQLiteQueries) CreateFeed(ctx context.Context, arg CreateFeedParams) (Feed, error) {
+	row, err := s.q.CreateFeed(ctx, sqlitedb.CreateFeedParams(arg))
+	return Feed(row), err
+}
+
+func (s *SQLiteQueries) CreateFeedFollow(ctx context.Context, arg CreateFeedFollowParams) (CreateFeedFollowRow, error) {
+	row, err := s.q.CreateFeedFollow(ctx, sqlitedb.CreateFeedFollowParams(arg))
+	// sqlc names the subqueries of RETURNING after the columns they select
+	return CreateFeedFollowRow{
+		ID:          row.ID,
+		CreatedAt:   row.CreatedAt,
+		UpdatedAt:   row.UpdatedAt,
+		UserID:      row.UserID,
+		FeedID:      row.FeedID,
+		FolderID:    row.FolderID,
+		DisplayName: row.DisplayName,
+		Hidden:      row.Hidden,
+		Notify:      row.Notify,
+		FullText:    row.FullText,
+		DefaultSort: row.DefaultSort,
+		UserName:    row.Name,
+		FeedName:    row.Name_2,
+	}, err
+}
+
+func (s *SQLiteQueries) CreateFeedScraper(ctx context.Context, arg CreateFeedScraperParams) (FeedScraper, error) {
+	row, err := s.q.CreateFeedScraper(ctx, sqlitedb.CreateFeedScraperParams(arg))
+	return FeedScraper(row), err
+}
+
+func (s *SQLiteQueries) CreateFeedWatch(ctx context.Context, arg CreateFeedWatchParams) (FeedWatch, error) {
+	row, err := s.q.CreateFeedWatch(ctx, sqlitedb.CreateFeedWatchParams(arg))
+	return FeedWatch(row), err
+}
+
+func (s *SQLiteQueries) CreateFolder(ctx context.Context, arg CreateFolderParams) (Folder, error) {
+	row, err := s.q.CreateFolder(ctx, sqlitedb.CreateFolderParams(arg))
+	return Folder(row), err
+}
+
+func (s *SQLiteQueries) CreatePageSnapshot(ctx context.Context, arg CreatePageSnapshotParams) (PageSnapshot, error) {
+	row, err := s.q.CreatePageSnapshot(ctx, sqlitedb.CreatePageSnapshotParams(arg))
+	return PageSnapshot(row), err
+}
+
+func (s *SQLiteQueries) CreatePost(ctx context.Context, arg CreatePostParams) (Post, error) {
+	row, err := s.q.CreatePost(ctx, sqlitedb.CreatePostParams(arg))
+	return Post(row), err
+}
+
+func (s *SQLiteQueries) CreateSavedSearch(ctx context.Context, arg CreateSavedSearchParams) (SavedSearch, error) {
+	row, err := s.q.CreateSavedSearch(ctx, sqlitedb.CreateSavedSearchParams(arg))
+	return SavedSearch(row), err
+}
+
+func (s *SQLiteQueries) CreateUser(ctx context.Context, arg CreateUserParams) (User, error) {
+	row, err := s.q.CreateUser(ctx, sqlitedb.CreateUserParams(arg))
+	return User(row), err
+}
+
+func (s *SQLiteQueries) DeleteFeed(ctx context.Context, id uuid.UUID) (int64, error) {
+	return s.q.DeleteFeed(ctx, id)
+}
+
+func (s *SQLiteQueries) DeleteFeedFollow(ctx context.Context, arg DeleteFeedFollowParams) error {
+	return s.q.DeleteFeedFollow(ctx, sqlitedb.DeleteFeedFollowParams(arg))
+}
+
+func (s *SQLiteQueries) DeleteFeeds(ctx context.Context) error {
+	return s.q.DeleteFeeds(ctx)
+}
+
+func (s *SQLiteQueries) DeleteFolder(ctx context.Context, arg DeleteFolderParams) (int64, error) {
+	return s.q.DeleteFolder(ctx, sqlitedb.DeleteFolderParams(arg))
+}
+
+func (s *SQLiteQueries) DeleteSavedSearch(ctx context.Context, arg DeleteSavedSearchParams) (int64, error) {
+	return s.q.DeleteSavedSearch(ctx, sqlitedb.DeleteSavedSearchParams(arg))
+}
+
+func (s *SQLiteQueries) DeleteUnsharedFeeds(ctx context.Context, userID uuid.UUID) (int64, error) {
+	return s.q.DeleteUnsharedFeeds(ctx, uuid.NullUUID{UUID: userID, Valid: true})
+}
+
+func (s *SQLiteQueries) DeleteUser(ctx context.Context, id uuid.UUID) (int64, error) {
+	return s.q.DeleteUser(ctx, id)
+}
+
+func (s *SQLiteQueries) DeleteUsers(ctx context.Context) error {
+	return s.q.DeleteUsers(ctx)
+}
+
+func (s *SQLiteQueries) FeedWantsFullText(ctx context.Context, feedID uuid.NullUUID) (bool, error) {
+	return s.q.FeedWantsFullText(ctx, feedID)
+}
+
+func (s *SQLiteQueries) GetFeedByURL(ctx context.Context, url sql.NullString) (Feed, error) {
+	row, err := s.q.GetFeedByURL(ctx, url)
+	return Feed(row), err
+}
+
+func (s *SQLiteQueries) GetFeedByURLKey(ctx context.Context, urlKey sql.NullString) (Feed, error) {
+	row, err := s.q.GetFeedByURLKey(ctx, urlKey)
+	return Feed(row), err
+}
+
+func (s *SQLiteQueries) LockFeedByURLKey(ctx context.Context, urlKey sql.NullString) (Feed, error) {
+	row, err := s.q.LockFeedByURLKey(ctx, urlKey)
+	return Feed(row), err
+}
+
+func (s *SQLiteQueries) GetFeedFollow(ctx context.Context, arg GetFeedFollowParams) (FeedFollow, error) {
+	row, err := s.q.GetFeedFollow(ctx, sqlitedb.GetFeedFollowParams(arg))
+	return FeedFollow(row), err
+}
+
+func (s *SQLiteQueries) GetFeedFollowsForUser(ctx context.Context, userID uuid.NullUUID) ([]GetFeedFollowsForUserRow, error) {
+	rows, err := s.q.GetFeedFollowsForUser(ctx, userID)
+	return convertRows(rows, err, func(row sqlitedb.GetFeedFollowsForUserRow) GetFeedFollowsForUserRow {
+		return GetFeedFollowsForUserRow(row)
+	})
+}
+
+func (s *SQLiteQueries) GetFeedScraper(ctx context.Context, feedID uuid.UUID) (FeedScraper, error) {
+	row, err := s.q.GetFeedScraper(ctx, feedID)
+	return FeedScraper(row), err
+}
+
+func (s *SQLiteQueries) GetFeedWatch(ctx context.Context, feedID uuid.UUID) (FeedWatch, error) {
+	row, err := s.q.GetFeedWatch(ctx, feedID)
+	return FeedWatch(row), err
+}
+
+func (s *SQLiteQueries) GetFeeds(ctx context.Context) ([]Feed, error) {
+	rows, err := s.q.GetFeeds(ctx)
+	return convertRows(rows, err, func(row sqlitedb.Feed) Feed { return Feed(row) })
+}
+
+func (s *SQLiteQueries) GetFolderByName(ctx context.Context, arg GetFolderByNameParams) (Folder, error) {
+	row, err := s.q.GetFolderByName(ctx, sqlitedb.GetFolderByNameParams(arg))
+	return Folder(row), err
+}
+
+func (s *SQLiteQueries) GetFoldersForUser(ctx context.Context, userID uuid.UUID) ([]Folder, error) {
+	rows, err := s.q.GetFoldersForUser(ctx, userID)
+	return convertRows(rows, err, func(row sqlitedb.Folder) Folder { return Folder(row) })
+}
+
+func (s *SQLiteQueries) GetLatestPageSnapshot(ctx context.Context, feedID uuid.UUID) (PageSnapshot, error) {
+	row, err := s.q.GetLatestPageSnapshot(ctx, feedID)
+	return PageSnapshot(row), err
+}
+
+func (s *SQLiteQueries) GetNextFeedToFetch(ctx context.Context) (Feed, error) {
+	row, err := s.q.GetNextFeedToFetch(ctx)
+	return Feed(row), err
+}
+
+func (s *SQLiteQueries) GetNotifyFollowers(ctx context.Context, feedID uuid.NullUUID) ([]GetNotifyFollowersRow, error) {
+	rows, err := s.q.GetNotifyFollowers(ctx, feedID)
+	return convertRows(rows, err, func(row sqlitedb.GetNotifyFollowersRow) GetNotifyFollowersRow { return GetNotifyFollowersRow(row) })
+}
+
+func (s *SQLiteQueries) GetPostsByIDPrefix(ctx context.Context, arg GetPostsByIDPrefixParams) ([]GetPostsByIDPrefixRow, error) {
+	rows, err := s.q.GetPostsByIDPrefix(ctx, sqlitedb.GetPostsByIDPrefixParams{UserID: arg.UserID, Prefix: arg.Prefix})
+	return convertRows(rows, err, func(row sqlitedb.GetPostsByIDPrefixRow) GetPostsByIDPrefixRow {
+		return GetPostsByIDPrefixRow{Post: Post(row.Post), FeedName: row.FeedName}
+	})
+}
+
+func (s *SQLiteQueries) GetPostsForUser(ctx context.Context, arg GetPostsForUserParams) ([]Post, error) {
+	rows, err := s.q.GetPostsForUser(ctx, sqlitedb.GetPostsForUserParams{ID: arg.ID, Limit: int64(arg.Limit)})
+	return convertRows(rows, err, func(row sqlitedb.Post) Post { return Post(row) })
+}
+
+func (s *SQLiteQueries) GetReadLater(ctx context.Context, userID uuid.UUID) ([]GetReadLaterRow, error) {
+	rows, err := s.q.GetReadLater(ctx, userID)
+	return convertRows(rows, err, func(row sqlitedb.GetReadLaterRow) GetReadLaterRow {
+		return GetReadLaterRow{Post: Post(row.Post), FeedName: row.FeedName, Position: row.Position}
+	})
+}
+
+func (s *SQLiteQueries) GetSavedSearchByName(ctx context.Context, arg GetSavedSearchByNameParams) (SavedSearch, error) {
+	row, err := s.q.GetSavedSearchByName(ctx, sqlitedb.GetSavedSearchByNameParams(arg))
+	return SavedSearch(row), err
+}
+
+func (s *SQLiteQueries) GetSavedSearchesForUser(ctx context.Context, userID uuid.UUID) ([]GetSavedSearchesForUserRow, error) {
+	rows, err := s.q.GetSavedSearchesForUser(ctx, userID)
+	return convertRows(rows, err, func(row sqlitedb.GetSavedSearchesForUserRow) GetSavedSearchesForUserRow {
+		return GetSavedSearchesForUserRow(row)
+	})
+}
+
+func (s *SQLiteQueries) GetStarredPosts(ctx context.Context, userID uuid.UUID) ([]GetStarredPostsRow, error) {
+	rows, err := s.q.GetStarredPosts(ctx, userID)
+	return convertRows(rows, err, func(row sqlitedb.GetStarredPostsRow) GetStarredPostsRow {
+		return GetStarredPostsRow{Post: Post(row.Post), FeedName: row.FeedName, StarredAt: row.StarredAt}
+	})
+}
+
+func (s *SQLiteQueries) GetUnreadCountsForUser(ctx context.Context, userID uuid.NullUUID) ([]GetUnreadCountsForUserRow, error) {
+	rows, err := s.q.GetUnreadCountsForUser(ctx, userID)
+	return convertRows(rows, err, func(row sqlitedb.GetUnreadCountsForUserRow) GetUnreadCountsForUserRow {
+		return GetUnreadCountsForUserRow(row)
+	})
+}
+
+func (s *SQLiteQueries) GetUser(ctx context.Context, name string) (User, error) {
+	row, err := s.q.GetUser(ctx, name)
+	return User(row), err
+}
+
+func (s *SQLiteQueries) GetUserByID(ctx context.Context, id uuid.UUID) (User, error) {
+	row, err := s.q.GetUserByID(ctx, id)
+	return User(row), err
+}
+
+func (s *SQLiteQueries) GetUsers(ctx context.Context) ([]User, error) {
+	rows, err := s.q.GetUsers(ctx)
+	return convertRows(rows, err, func(row sqlitedb.User) User { return User(row) })
+}
+
+func (s *SQLiteQueries) MarkAllPostsRead(ctx context.Context, arg MarkAllPostsReadParams) (int64, error) {
+	return s.q.MarkAllPostsRead(ctx, sqlitedb.MarkAllPostsReadParams{
+		ReadAt: arg.ReadAt,
+		UserID: uuid.NullUUID{UUID: arg.UserID, Valid: true},
+	})
+}
+
+func (s *SQLiteQueries) MarkFeedFetched(ctx context.Context, arg MarkFeedFetchedParams) error {
+	return s.q.MarkFeedFetched(ctx, sqlitedb.MarkFeedFetchedParams(arg))
+}
+
+func (s *SQLiteQueries) MarkFeedPostsReadBefore(ctx context.Context, arg MarkFeedPostsReadBeforeParams) (int64, error) {
+	return s.q.MarkFeedPostsReadBefore(ctx, sqlitedb.MarkFeedPostsReadBeforeParams{
+		UserID: arg.UserID,
+		ReadAt: arg.ReadAt,
+		FeedID: uuid.NullUUID{UUID: arg.FeedID, Valid: true},
+		Before: sql.NullTime{Time: arg.Before, Valid: true},
+	})
+}
+
+func (s *SQLiteQueries) MarkPostRead(ctx context.Context, arg MarkPostReadParams) error {
+	return s.q.MarkPostRead(ctx, sqlitedb.MarkPostReadParams(arg))
+}
+
+func (s *SQLiteQueries) MarkPostUnread(ctx context.Context, arg MarkPostUnreadParams) error {
+	return s.q.MarkPostUnread(ctx, sqlitedb.MarkPostUnreadParams(arg))
+}
+
+func (s *SQLiteQueries) PruneOrphanedPosts(ctx context.Context) (int64, error) {
+	return s.q.PruneOrphanedPosts(ctx)
+}
+
+func (s *SQLiteQueries) RemoveFromReadLater(ctx context.Context, arg RemoveFromReadLaterParams) (int64, error) {
+	return s.q.RemoveFromReadLater(ctx, sqlitedb.RemoveFromReadLaterParams(arg))
+}
+
+func (s *SQLiteQueries) RenameFeed(ctx context.Context, arg RenameFeedParams) (int64, error) {
+	return s.q.RenameFeed(ctx, sqlitedb.RenameFeedParams(arg))
+}
+
+func (s *SQLiteQueries) RenameFolder(ctx context.Context, arg RenameFolderParams) (int64, error) {
+	return s.q.RenameFolder(ctx, sqlitedb.RenameFolderParams(arg))
+}
+
+func (s *SQLiteQueries) SearchPosts(ctx context.Context, arg SearchPostsParams) ([]SearchPostsRow, error) {
+	rows, err := s.q.SearchPosts(ctx, sqlitedb.SearchPostsParams{
+		UserID:   arg.UserID,
+		Query:    arg.Query,
+		AllFeeds: arg.AllFeeds,
+		FeedID:   arg.FeedID,
+		Since:    arg.Since,
+		Until:    arg.Until,
+		MaxPosts: int64(arg.MaxPosts),
+	})
+	return convertRows(rows, err, func(row sqlitedb.SearchPostsRow) SearchPostsRow {
+		return SearchPostsRow{Post: Post(row.Post), FeedName: row.FeedName, Rank: float32(row.Rank), Snippet: row.Snippet}
+	})
+}
+
+func (s *SQLiteQueries) SetFeedFollowFolder(ctx context.Context, arg SetFeedFollowFolderParams) (int64, error) {
+	return s.q.SetFeedFollowFolder(ctx, sqlitedb.SetFeedFollowFolderParams(arg))
+}
+
+func (s *SQLiteQueries) SetFeedOwner(ctx context.Context, arg SetFeedOwnerParams) (int64, error) {
+	return s.q.SetFeedOwner(ctx, sqlitedb.SetFeedOwnerParams(arg))
+}
+
+func (s *SQLiteQueries) SetFeedSiteURL(ctx context.Context, arg SetFeedSiteURLParams) error {
+	return s.q.SetFeedSiteURL(ctx, sqlitedb.SetFeedSiteURLParams(arg))
+}
+
+func (s *SQLiteQueries) SetFeedURL(ctx context.Context, arg SetFeedURLParams) (int64, error) {
+	return s.q.SetFeedURL(ctx, sqlitedb.SetFeedURLParams(arg))
+}
+
+func (s *SQLiteQueries) SetUserAdmin(ctx context.Context, arg SetUserAdminParams) (int64, error) {
+	return s.q.SetUserAdmin(ctx, sqlitedb.SetUserAdminParams(arg))
+}
+
+func (s *SQLiteQueries) StarPost(ctx context.Context, arg StarPostParams) error {
+	return s.q.StarPost(ctx, sqlitedb.StarPostParams(arg))
+}
+
+func (s *SQLiteQueries) UnstarPost(ctx context.Context, arg UnstarPostParams) (int64, error) {
+	return s.q.UnstarPost(ctx, sqlitedb.UnstarPostParams(arg))
+}
+
+func (s *SQLiteQueries) UpdateFeedFollowSettings(ctx context.Context, arg UpdateFeedFollowSettingsParams) (int64, error) {
+	return s.q.UpdateFeedFollowSettings(ctx, sqlitedb.UpdateFeedFollowSettingsParams(arg))
+}
+
+func (s *SQLiteQueries) UpdatePostContent(ctx context.Context, arg UpdatePostContentParams) error {
+	return s.q.UpdatePostContent(ctx, sqlitedb.UpdatePostContentParams(arg))
+}
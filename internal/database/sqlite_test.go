@@ -0,0 +1,51 @@
+package database
+
+import (
+	"database/sql/driver"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestWebsearchToFTS(t *testing.T) {
+	cases := map[string]struct {
+		query string
+		want  string
+	}{
+		"one word":            {query: "generics", want: `"generics"`},
+		"every word":          {query: "go  generics", want: `"go" AND "generics"`},
+		"either word":         {query: "go or rust zig", want: `("go" OR "rust") AND "zig"`},
+		"or chain":            {query: "a OR b or c", want: `("a" OR "b" OR "c")`},
+		"leading or":          {query: "or go", want: `"go"`},
+		"phrase":              {query: `"type parameters" go`, want: `"type parameters" AND "go"`},
+		"open phrase":         {query: `"type parameters`, want: `"type parameters"`},
+		"excluded":            {query: "go -rust -\"zig lang\"", want: `"go" NOT "rust" NOT "zig lang"`},
+		"only excluded":       {query: "-rust", want: `""`},
+		"empty":               {query: "  ", want: `""`},
+		"fts5 syntax":         {query: `title:go NEAR(a b) *`, want: `"title:go" AND "NEAR(a" AND "b)" AND "*"`},
+		"quote in word":       {query: `it"s`, want: `"it" AND "s"`},
+		"empty phrase":        {query: `a""b`, want: `"a" AND "b"`},
+		"dash in word":        {query: "state-of-the-art", want: `"state-of-the-art"`},
+		"quoted or is a word": {query: `go "or" rust`, want: `"go" AND "or" AND "rust"`},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if got := websearchToFTS(tc.query); got != tc.want {
+				t.Errorf("websearchToFTS(%q) Mismatch wanted: %v , got: %v", tc.query, tc.want, got)
+			}
+		})
+	}
+}
+
+func TestSQLiteArgs(t *testing.T) {
+	zone := time.FixedZone("UTC+2", 2*60*60)
+	args := sqliteArgs([]driver.NamedValue{
+		{Ordinal: 1, Value: time.Date(2024, 5, 3, 10, 30, 15, 250000000, zone)},
+		{Ordinal: 2, Value: "text"},
+	})
+	// the wall clock is kept, like postgres keeps it in a TIMESTAMP column
+	want := []driver.NamedValue{{Ordinal: 1, Value: "2024-05-03 10:30:15.25"}, {Ordinal: 2, Value: "text"}}
+	if !reflect.DeepEqual(args, want) {
+		t.Errorf("sqliteArgs Mismatch wanted: %v , got: %v", want, args)
+	}
+}
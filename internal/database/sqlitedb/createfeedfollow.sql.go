@@ -0,0 +1,79 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: createfeedfollow.sql
+
+package sqlitedb
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/google/uuid"
+)
+
+const createFeedFollow = `-- name: CreateFeedFollow :one
+INSERT INTO feed_follows (id, created_at, updated_at, user_id, feed_id)
+VALUES (
+    ?1,
+    ?2,
+    ?3,
+    ?4,
+    ?5
+)
+RETURNING id, created_at, updated_at, user_id, feed_id, folder_id, display_name, hidden, notify, full_text, default_sort,
+    (SELECT users.name FROM users WHERE users.id = feed_follows.user_id) AS user_name,
+    (SELECT feeds.name FROM feeds WHERE feeds.id = feed_follows.feed_id) AS feed_name
+`
+
+type CreateFeedFollowParams struct {
+	ID        uuid.UUID
+	CreatedAt sql.NullTime
+	UpdatedAt sql.NullTime
+	UserID    uuid.NullUUID
+	FeedID    uuid.NullUUID
+}
+
+type CreateFeedFollowRow struct {
+	ID          uuid.UUID
+	CreatedAt   sql.NullTime
+	UpdatedAt   sql.NullTime
+	UserID      uuid.NullUUID
+	FeedID      uuid.NullUUID
+	FolderID    uuid.NullUUID
+	DisplayName sql.NullString
+	Hidden      bool
+	Notify      bool
+	FullText    bool
+	DefaultSort string
+	Name        string
+	Name_2      sql.NullString
+}
+
+// sqlite has no INSERT in WITH, the names come from subqueries of RETURNING
+func (q *Queries) CreateFeedFollow(ctx context.Context, arg CreateFeedFollowParams) (CreateFeedFollowRow, error) {
+	row := q.db.QueryRowContext(ctx, createFeedFollow,
+		arg.ID,
+		arg.CreatedAt,
+		arg.UpdatedAt,
+		arg.UserID,
+		arg.FeedID,
+	)
+	var i CreateFeedFollowRow
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.UserID,
+		&i.FeedID,
+		&i.FolderID,
+		&i.DisplayName,
+		&i.Hidden,
+		&i.Notify,
+		&i.FullText,
+		&i.DefaultSort,
+		&i.Name,
+		&i.Name_2,
+	)
+	return i, err
+}
@@ -0,0 +1,81 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: getfeedfollowbyuser.sql
+
+package sqlitedb
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/google/uuid"
+)
+
+const getFeedFollowsForUser = `-- name: GetFeedFollowsForUser :many
+SELECT feed_follows.id, feed_follows.created_at, feed_follows.updated_at, feed_follows.user_id, feed_follows.feed_id, feed_follows.folder_id, feed_follows.display_name, feed_follows.hidden, feed_follows.notify, feed_follows.full_text, feed_follows.default_sort,users.name AS user_name ,feeds.name AS feed_name, feeds.url AS feed_url, feeds.site_url AS feed_site_url, folders.name AS folder_name FROM feed_follows
+INNER JOIN users ON feed_follows.user_id = users.id 
+INNER JOIN feeds ON feed_follows.feed_id = feeds.id
+LEFT JOIN folders ON feed_follows.folder_id = folders.id
+WHERE feed_follows.user_id = ?1
+ORDER BY folders.name ASC NULLS FIRST, feeds.name ASC
+`
+
+type GetFeedFollowsForUserRow struct {
+	ID          uuid.UUID
+	CreatedAt   sql.NullTime
+	UpdatedAt   sql.NullTime
+	UserID      uuid.NullUUID
+	FeedID      uuid.NullUUID
+	FolderID    uuid.NullUUID
+	DisplayName sql.NullString
+	Hidden      bool
+	Notify      bool
+	FullText    bool
+	DefaultSort string
+	UserName    string
+	FeedName    sql.NullString
+	FeedUrl     sql.NullString
+	FeedSiteUrl sql.NullString
+	FolderName  sql.NullString
+}
+
+func (q *Queries) GetFeedFollowsForUser(ctx context.Context, userID uuid.NullUUID) ([]GetFeedFollowsForUserRow, error) {
+	rows, err := q.db.QueryContext(ctx, getFeedFollowsForUser, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetFeedFollowsForUserRow
+	for rows.Next() {
+		var i GetFeedFollowsForUserRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.UserID,
+			&i.FeedID,
+			&i.FolderID,
+			&i.DisplayName,
+			&i.Hidden,
+			&i.Notify,
+			&i.FullText,
+			&i.DefaultSort,
+			&i.UserName,
+			&i.FeedName,
+			&i.FeedUrl,
+			&i.FeedSiteUrl,
+			&i.FolderName,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
@@ -0,0 +1,74 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: getpostbyidprefix.sql
+
+package sqlitedb
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/google/uuid"
+)
+
+const getPostsByIDPrefix = `-- name: GetPostsByIDPrefix :many
+SELECT posts.id, posts.created_at, posts.updated_at, posts.title, posts.url, posts.description, posts.published_at, posts.feed_id, posts.content, posts.search_vector, posts.author, feeds.name AS feed_name
+FROM posts
+LEFT JOIN feeds ON posts.feed_id = feeds.id
+LEFT JOIN post_stars ON post_stars.post_id = posts.id AND post_stars.user_id = ?1
+LEFT JOIN read_later ON read_later.post_id = posts.id AND read_later.user_id = ?1
+LEFT JOIN feed_follows ON feed_follows.feed_id = posts.feed_id AND feed_follows.user_id = ?1
+WHERE posts.id LIKE replace(replace(replace(CAST(?2 AS TEXT), '\', '\\'), '%', '\%'), '_', '\_') || '%' ESCAPE '\'
+    AND (feed_follows.id IS NOT NULL OR post_stars.post_id IS NOT NULL OR read_later.post_id IS NOT NULL)
+LIMIT 2
+`
+
+type GetPostsByIDPrefixParams struct {
+	UserID uuid.UUID
+	Prefix string
+}
+
+type GetPostsByIDPrefixRow struct {
+	Post     Post
+	FeedName sql.NullString
+}
+
+// the wildcards of the prefix are taken literally, posts are found in the
+// followed feeds of the user and among the posts the user starred or queued.
+// sqlc leaves sqlc.arg in EXISTS subqueries as it is, joins find them here.
+func (q *Queries) GetPostsByIDPrefix(ctx context.Context, arg GetPostsByIDPrefixParams) ([]GetPostsByIDPrefixRow, error) {
+	rows, err := q.db.QueryContext(ctx, getPostsByIDPrefix, arg.UserID, arg.Prefix)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetPostsByIDPrefixRow
+	for rows.Next() {
+		var i GetPostsByIDPrefixRow
+		if err := rows.Scan(
+			&i.Post.ID,
+			&i.Post.CreatedAt,
+			&i.Post.UpdatedAt,
+			&i.Post.Title,
+			&i.Post.Url,
+			&i.Post.Description,
+			&i.Post.PublishedAt,
+			&i.Post.FeedID,
+			&i.Post.Content,
+			&i.Post.SearchVector,
+			&i.Post.Author,
+			&i.FeedName,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
@@ -0,0 +1,67 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: pagesnapshots.sql
+
+package sqlitedb
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const createPageSnapshot = `-- name: CreatePageSnapshot :one
+INSERT INTO page_snapshots (id, created_at, feed_id, content)
+VALUES (
+    ?1,
+    ?2,
+    ?3,
+    ?4
+)
+RETURNING id, created_at, feed_id, content
+`
+
+type CreatePageSnapshotParams struct {
+	ID        uuid.UUID
+	CreatedAt time.Time
+	FeedID    uuid.UUID
+	Content   string
+}
+
+func (q *Queries) CreatePageSnapshot(ctx context.Context, arg CreatePageSnapshotParams) (PageSnapshot, error) {
+	row := q.db.QueryRowContext(ctx, createPageSnapshot,
+		arg.ID,
+		arg.CreatedAt,
+		arg.FeedID,
+		arg.Content,
+	)
+	var i PageSnapshot
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.FeedID,
+		&i.Content,
+	)
+	return i, err
+}
+
+const getLatestPageSnapshot = `-- name: GetLatestPageSnapshot :one
+SELECT id, created_at, feed_id, content FROM page_snapshots
+WHERE feed_id = ?1
+ORDER BY created_at DESC
+LIMIT 1
+`
+
+func (q *Queries) GetLatestPageSnapshot(ctx context.Context, feedID uuid.UUID) (PageSnapshot, error) {
+	row := q.db.QueryRowContext(ctx, getLatestPageSnapshot, feedID)
+	var i PageSnapshot
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.FeedID,
+		&i.Content,
+	)
+	return i, err
+}
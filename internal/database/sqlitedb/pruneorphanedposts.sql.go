@@ -0,0 +1,25 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: pruneorphanedposts.sql
+
+package sqlitedb
+
+import (
+	"context"
+)
+
+const pruneOrphanedPosts = `-- name: PruneOrphanedPosts :execrows
+DELETE FROM posts
+WHERE posts.feed_id IS NULL
+    AND NOT EXISTS (SELECT 1 FROM post_stars WHERE post_stars.post_id = posts.id)
+    AND NOT EXISTS (SELECT 1 FROM read_later WHERE read_later.post_id = posts.id)
+`
+
+func (q *Queries) PruneOrphanedPosts(ctx context.Context) (int64, error) {
+	result, err := q.db.ExecContext(ctx, pruneOrphanedPosts)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
@@ -0,0 +1,157 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: savedsearches.sql
+
+package sqlitedb
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/google/uuid"
+)
+
+const createSavedSearch = `-- name: CreateSavedSearch :one
+INSERT INTO saved_searches (id, created_at, updated_at, user_id, name, query)
+VALUES (
+    ?1,
+    ?2,
+    ?3,
+    ?4,
+    ?5,
+    ?6
+)
+RETURNING id, created_at, updated_at, user_id, name, "query"
+`
+
+type CreateSavedSearchParams struct {
+	ID        uuid.UUID
+	CreatedAt sql.NullTime
+	UpdatedAt sql.NullTime
+	UserID    uuid.UUID
+	Name      string
+	Query     string
+}
+
+func (q *Queries) CreateSavedSearch(ctx context.Context, arg CreateSavedSearchParams) (SavedSearch, error) {
+	row := q.db.QueryRowContext(ctx, createSavedSearch,
+		arg.ID,
+		arg.CreatedAt,
+		arg.UpdatedAt,
+		arg.UserID,
+		arg.Name,
+		arg.Query,
+	)
+	var i SavedSearch
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.UserID,
+		&i.Name,
+		&i.Query,
+	)
+	return i, err
+}
+
+const deleteSavedSearch = `-- name: DeleteSavedSearch :execrows
+DELETE FROM saved_searches
+WHERE user_id = ?1 AND name = ?2
+`
+
+type DeleteSavedSearchParams struct {
+	UserID uuid.UUID
+	Name   string
+}
+
+func (q *Queries) DeleteSavedSearch(ctx context.Context, arg DeleteSavedSearchParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteSavedSearch, arg.UserID, arg.Name)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const getSavedSearchByName = `-- name: GetSavedSearchByName :one
+SELECT id, created_at, updated_at, user_id, name, "query" FROM saved_searches
+WHERE user_id = ?1 AND name = ?2
+`
+
+type GetSavedSearchByNameParams struct {
+	UserID uuid.UUID
+	Name   string
+}
+
+func (q *Queries) GetSavedSearchByName(ctx context.Context, arg GetSavedSearchByNameParams) (SavedSearch, error) {
+	row := q.db.QueryRowContext(ctx, getSavedSearchByName, arg.UserID, arg.Name)
+	var i SavedSearch
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.UserID,
+		&i.Name,
+		&i.Query,
+	)
+	return i, err
+}
+
+const getSavedSearchesForUser = `-- name: GetSavedSearchesForUser :many
+SELECT saved_searches.id, saved_searches.created_at, saved_searches.updated_at, saved_searches.user_id, saved_searches.name, saved_searches."query", (
+    SELECT COUNT(*)
+    FROM posts
+    INNER JOIN feed_follows ON feed_follows.feed_id = posts.feed_id AND feed_follows.user_id = saved_searches.user_id
+    LEFT JOIN post_reads ON post_reads.post_id = posts.id AND post_reads.user_id = saved_searches.user_id
+    WHERE post_reads.post_id IS NULL
+        AND posts.id IN (
+            SELECT posts_search_ids.post_id FROM posts_search
+            INNER JOIN posts_search_ids ON posts_search_ids.search_id = posts_search.rowid
+            WHERE posts_search MATCH websearch_to_fts(saved_searches.query)
+        )
+) AS unread
+FROM saved_searches
+WHERE saved_searches.user_id = ?1
+ORDER BY saved_searches.name ASC
+`
+
+type GetSavedSearchesForUserRow struct {
+	ID        uuid.UUID
+	CreatedAt sql.NullTime
+	UpdatedAt sql.NullTime
+	UserID    uuid.UUID
+	Name      string
+	Query     string
+	Unread    int64
+}
+
+func (q *Queries) GetSavedSearchesForUser(ctx context.Context, userID uuid.UUID) ([]GetSavedSearchesForUserRow, error) {
+	rows, err := q.db.QueryContext(ctx, getSavedSearchesForUser, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetSavedSearchesForUserRow
+	for rows.Next() {
+		var i GetSavedSearchesForUserRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.UserID,
+			&i.Name,
+			&i.Query,
+			&i.Unread,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
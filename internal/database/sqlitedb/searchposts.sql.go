@@ -0,0 +1,97 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: searchposts.sql
+
+package sqlitedb
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/google/uuid"
+)
+
+const searchPosts = `-- name: SearchPosts :many
+SELECT posts.id, posts.created_at, posts.updated_at, posts.title, posts.url, posts.description, posts.published_at, posts.feed_id, posts.content, posts.search_vector, posts.author,
+    COALESCE(feed_follows.display_name, feeds.name, '') AS feed_name,
+    CAST(-bm25(posts_search, 2.5, 1.0) AS REAL) AS "rank",
+    snippet(posts_search, -1, '[[', ']]', ' ... ', 30) AS snippet
+FROM posts_search
+INNER JOIN posts_search_ids ON posts_search_ids.search_id = posts_search.rowid
+INNER JOIN posts ON posts.id = posts_search_ids.post_id
+LEFT JOIN feeds ON posts.feed_id = feeds.id
+LEFT JOIN feed_follows ON feed_follows.feed_id = posts.feed_id AND feed_follows.user_id = ?1
+WHERE posts_search MATCH websearch_to_fts(CAST(?2 AS TEXT))
+    AND (CAST(?3 AS BOOLEAN) OR feed_follows.user_id IS NOT NULL)
+    AND (posts.feed_id = ?4 OR ?4 IS NULL)
+    AND (posts.published_at >= ?5 OR ?5 IS NULL)
+    AND (posts.published_at < ?6 OR ?6 IS NULL)
+ORDER BY "rank" DESC, posts.published_at DESC
+LIMIT ?7
+`
+
+type SearchPostsParams struct {
+	UserID   uuid.NullUUID
+	Query    string
+	AllFeeds bool
+	FeedID   uuid.NullUUID
+	Since    sql.NullTime
+	Until    sql.NullTime
+	MaxPosts int64
+}
+
+type SearchPostsRow struct {
+	Post     Post
+	FeedName string
+	Rank     float64
+	Snippet  string
+}
+
+// bm25 ranks lower for better matches, titles weigh as much more as the
+// 'A' weight of postgres over 'B'
+func (q *Queries) SearchPosts(ctx context.Context, arg SearchPostsParams) ([]SearchPostsRow, error) {
+	rows, err := q.db.QueryContext(ctx, searchPosts,
+		arg.UserID,
+		arg.Query,
+		arg.AllFeeds,
+		arg.FeedID,
+		arg.Since,
+		arg.Until,
+		arg.MaxPosts,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []SearchPostsRow
+	for rows.Next() {
+		var i SearchPostsRow
+		if err := rows.Scan(
+			&i.Post.ID,
+			&i.Post.CreatedAt,
+			&i.Post.UpdatedAt,
+			&i.Post.Title,
+			&i.Post.Url,
+			&i.Post.Description,
+			&i.Post.PublishedAt,
+			&i.Post.FeedID,
+			&i.Post.Content,
+			&i.Post.SearchVector,
+			&i.Post.Author,
+			&i.FeedName,
+			&i.Rank,
+			&i.Snippet,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
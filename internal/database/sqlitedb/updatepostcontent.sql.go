@@ -0,0 +1,30 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: updatepostcontent.sql
+
+package sqlitedb
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/google/uuid"
+)
+
+const updatePostContent = `-- name: UpdatePostContent :exec
+UPDATE posts
+SET updated_at = ?2 , content = ?3
+WHERE id = ?1
+`
+
+type UpdatePostContentParams struct {
+	ID        uuid.UUID
+	UpdatedAt sql.NullTime
+	Content   sql.NullString
+}
+
+func (q *Queries) UpdatePostContent(ctx context.Context, arg UpdatePostContentParams) error {
+	_, err := q.db.ExecContext(ctx, updatePostContent, arg.ID, arg.UpdatedAt, arg.Content)
+	return err
+}
@@ -0,0 +1,133 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/google/uuid"
+)
+
+// Store is what the commands need from the database: Queries on postgres,
+// SQLiteQueries on sqlite, or Memory in tests.
+type Store interface {
+	Users
+	Feeds
+	Follows
+	Posts
+
+	// InTx runs fn on a store whose changes are kept only when fn returns
+	// nil. fn must use the store it is given, an InTx inside fn joins the
+	// transaction already running.
+	InTx(ctx context.Context, fn func(tx Store) error) error
+}
+
+var (
+	_ Store = (*Queries)(nil)
+	_ Store = (*SQLiteQueries)(nil)
+	_ Store = (*Memory)(nil)
+)
+
+// Users are the gator accounts, their names are checked by the commands, not by the store.
+type Users interface {
+	CreateUser(ctx context.Context, arg CreateUserParams) (User, error)
+	GetUser(ctx context.Context, name string) (User, error)
+	GetUserByID(ctx context.Context, id uuid.UUID) (User, error)
+	GetUsers(ctx context.Context) ([]User, error)
+	SetUserAdmin(ctx context.Context, arg SetUserAdminParams) (int64, error)
+	// DeleteUser deletes a user with their follows, folders and the rest of
+	// their data, the feeds they added stay without an owner.
+	DeleteUser(ctx context.Context, id uuid.UUID) (int64, error)
+	// DeleteUsers deletes every user like DeleteUser does.
+	DeleteUsers(ctx context.Context) error
+}
+
+// Feeds are unique by url and by url key, the user that added a feed owns it.
+type Feeds interface {
+	CreateFeed(ctx context.Context, arg CreateFeedParams) (Feed, error)
+	GetFeedByURL(ctx context.Context, url sql.NullString) (Feed, error)
+	GetFeedByURLKey(ctx context.Context, urlKey sql.NullString) (Feed, error)
+	// LockFeedByURLKey is GetFeedByURLKey keeping the feed from changing
+	// until the transaction it runs in ends.
+	LockFeedByURLKey(ctx context.Context, urlKey sql.NullString) (Feed, error)
+	GetFeeds(ctx context.Context) ([]Feed, error)
+	// GetNextFeedToFetch is the feed fetched the longest time ago, feeds never fetched first.
+	GetNextFeedToFetch(ctx context.Context) (Feed, error)
+	MarkFeedFetched(ctx context.Context, arg MarkFeedFetchedParams) error
+	SetFeedSiteURL(ctx context.Context, arg SetFeedSiteURLParams) error
+	RenameFeed(ctx context.Context, arg RenameFeedParams) (int64, error)
+	SetFeedURL(ctx context.Context, arg SetFeedURLParams) (int64, error)
+	SetFeedOwner(ctx context.Context, arg SetFeedOwnerParams) (int64, error)
+	// DeleteFeed deletes a feed with its follows and settings, its posts
+	// stay without a feed.
+	DeleteFeed(ctx context.Context, id uuid.UUID) (int64, error)
+	DeleteFeeds(ctx context.Context) error
+	// DeleteUnsharedFeeds deletes the feeds of a user that no other user follows.
+	DeleteUnsharedFeeds(ctx context.Context, userID uuid.UUID) (int64, error)
+
+	CreateFeedScraper(ctx context.Context, arg CreateFeedScraperParams) (FeedScraper, error)
+	GetFeedScraper(ctx context.Context, feedID uuid.UUID) (FeedScraper, error)
+	CreateFeedWatch(ctx context.Context, arg CreateFeedWatchParams) (FeedWatch, error)
+	GetFeedWatch(ctx context.Context, feedID uuid.UUID) (FeedWatch, error)
+	CreatePageSnapshot(ctx context.Context, arg CreatePageSnapshotParams) (PageSnapshot, error)
+	GetLatestPageSnapshot(ctx context.Context, feedID uuid.UUID) (PageSnapshot, error)
+}
+
+// Follows are the subscriptions of users to feeds, one per user and feed,
+// with their settings and folders.
+type Follows interface {
+	CreateFeedFollow(ctx context.Context, arg CreateFeedFollowParams) (CreateFeedFollowRow, error)
+	DeleteFeedFollow(ctx context.Context, arg DeleteFeedFollowParams) error
+	GetFeedFollow(ctx context.Context, arg GetFeedFollowParams) (FeedFollow, error)
+	// GetFeedFollowsForUser orders the follows by folder, the ones without
+	// a folder first, then by feed name.
+	GetFeedFollowsForUser(ctx context.Context, userID uuid.NullUUID) ([]GetFeedFollowsForUserRow, error)
+	UpdateFeedFollowSettings(ctx context.Context, arg UpdateFeedFollowSettingsParams) (int64, error)
+	FeedWantsFullText(ctx context.Context, feedID uuid.NullUUID) (bool, error)
+	GetNotifyFollowers(ctx context.Context, feedID uuid.NullUUID) ([]GetNotifyFollowersRow, error)
+
+	CreateFolder(ctx context.Context, arg CreateFolderParams) (Folder, error)
+	GetFolderByName(ctx context.Context, arg GetFolderByNameParams) (Folder, error)
+	GetFoldersForUser(ctx context.Context, userID uuid.UUID) ([]Folder, error)
+	RenameFolder(ctx context.Context, arg RenameFolderParams) (int64, error)
+	// DeleteFolder leaves the follows of the folder without a folder.
+	DeleteFolder(ctx context.Context, arg DeleteFolderParams) (int64, error)
+	SetFeedFollowFolder(ctx context.Context, arg SetFeedFollowFolderParams) (int64, error)
+}
+
+// Posts are unique by url, with what every user read, starred and saved for later.
+type Posts interface {
+	// CreatePost returns sql.ErrNoRows when a post with the url exists, it
+	// does not fail the transaction it runs in.
+	CreatePost(ctx context.Context, arg CreatePostParams) (Post, error)
+	UpdatePostContent(ctx context.Context, arg UpdatePostContentParams) error
+	GetPostsForUser(ctx context.Context, arg GetPostsForUserParams) ([]Post, error)
+	// GetPostsByIDPrefix returns at most two posts, enough to tell an
+	// ambiguous prefix, of the feeds the user follows or starred or queued by
+	// the user.
+	GetPostsByIDPrefix(ctx context.Context, arg GetPostsByIDPrefixParams) ([]GetPostsByIDPrefixRow, error)
+	// PruneOrphanedPosts deletes the posts left without a feed that are not
+	// starred or saved for later.
+	PruneOrphanedPosts(ctx context.Context) (int64, error)
+	Browse(ctx context.Context, arg BrowseParams) ([]BrowseRow, error)
+
+	MarkPostRead(ctx context.Context, arg MarkPostReadParams) error
+	MarkPostUnread(ctx context.Context, arg MarkPostUnreadParams) error
+	MarkFeedPostsReadBefore(ctx context.Context, arg MarkFeedPostsReadBeforeParams) (int64, error)
+	MarkAllPostsRead(ctx context.Context, arg MarkAllPostsReadParams) (int64, error)
+	GetUnreadCountsForUser(ctx context.Context, userID uuid.NullUUID) ([]GetUnreadCountsForUserRow, error)
+
+	StarPost(ctx context.Context, arg StarPostParams) error
+	UnstarPost(ctx context.Context, arg UnstarPostParams) (int64, error)
+	GetStarredPosts(ctx context.Context, userID uuid.UUID) ([]GetStarredPostsRow, error)
+	AddToReadLater(ctx context.Context, arg AddToReadLaterParams) (int64, error)
+	RemoveFromReadLater(ctx context.Context, arg RemoveFromReadLaterParams) (int64, error)
+	GetReadLater(ctx context.Context, userID uuid.UUID) ([]GetReadLaterRow, error)
+
+	// SearchPosts and the unread counts of saved searches take queries in
+	// the syntax of websearch_to_tsquery.
+	SearchPosts(ctx context.Context, arg SearchPostsParams) ([]SearchPostsRow, error)
+	CreateSavedSearch(ctx context.Context, arg CreateSavedSearchParams) (SavedSearch, error)
+	GetSavedSearchByName(ctx context.Context, arg GetSavedSearchByNameParams) (SavedSearch, error)
+	GetSavedSearchesForUser(ctx context.Context, userID uuid.UUID) ([]GetSavedSearchesForUserRow, error)
+	DeleteSavedSearch(ctx context.Context, arg DeleteSavedSearchParams) (int64, error)
+}
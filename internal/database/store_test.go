@@ -0,0 +1,801 @@
+package database_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/o0n1x/gator/internal/database"
+	"github.com/o0n1x/gator/internal/dbtest"
+)
+
+// the contract of database.Store, every store of forEachStore passes it
+
+var ctx = context.Background()
+
+// at is a time of the tests, on a whole microsecond like postgres stores them.
+func at(day, hour int) time.Time {
+	return time.Date(2024, 5, day, hour, 30, 15, 250000000, time.UTC)
+}
+
+func valid(t time.Time) sql.NullTime {
+	return sql.NullTime{Time: t, Valid: true}
+}
+
+func text(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: s != ""}
+}
+
+func id(u uuid.UUID) uuid.NullUUID {
+	return uuid.NullUUID{UUID: u, Valid: true}
+}
+
+// forEachStore runs test against the memory store and every database of dbtest.Open.
+func forEachStore(t *testing.T, test func(t *testing.T, q database.Store)) {
+	t.Run("memory", func(t *testing.T) {
+		test(t, database.NewMemory())
+	})
+	forEachDatabase(t, func(t *testing.T, q database.Store) {
+		test(t, q)
+	})
+}
+
+func forEachDatabase(t *testing.T, test func(t *testing.T, q database.Store)) {
+	for _, d := range dbtest.Open(t) {
+		t.Run(d.Name, func(t *testing.T) {
+			test(t, database.NewStore(d.DB))
+		})
+	}
+}
+
+func createUser(t *testing.T, q database.Store, name string) database.User {
+	t.Helper()
+	user, err := q.CreateUser(ctx, database.CreateUserParams{ID: uuid.New(), CreatedAt: valid(at(1, 8)), UpdatedAt: valid(at(1, 8)), Name: name})
+	if err != nil {
+		t.Fatalf("CreateUser Failed %v", err)
+	}
+	return user
+}
+
+func createFeed(t *testing.T, q database.Store, user database.User, name string) database.Feed {
+	t.Helper()
+	url := "https://" + name + ".example.com/feed"
+	feed, err := q.CreateFeed(ctx, database.CreateFeedParams{
+		ID: uuid.New(), CreatedAt: valid(at(1, 9)), UpdatedAt: valid(at(1, 9)),
+		Name: text(name), Url: text(url), UserID: id(user.ID), UrlKey: text(strings.TrimPrefix(url, "https://")), Kind: "rss",
+	})
+	if err != nil {
+		t.Fatalf("CreateFeed Failed %v", err)
+	}
+	return feed
+}
+
+func follow(t *testing.T, q database.Store, user database.User, feed database.Feed) database.CreateFeedFollowRow {
+	t.Helper()
+	row, err := q.CreateFeedFollow(ctx, database.CreateFeedFollowParams{ID: uuid.New(), CreatedAt: valid(at(1, 10)), UpdatedAt: valid(at(1, 10)), UserID: id(user.ID), FeedID: id(feed.ID)})
+	if err != nil {
+		t.Fatalf("CreateFeedFollow Failed %v", err)
+	}
+	return row
+}
+
+func createPost(t *testing.T, q database.Store, feed database.Feed, title, description string, published time.Time) database.Post {
+	t.Helper()
+	post, err := q.CreatePost(ctx, database.CreatePostParams{
+		ID: uuid.New(), CreatedAt: valid(published.Add(time.Hour)), UpdatedAt: valid(published.Add(time.Hour)),
+		Title: text(title), Url: text(fmt.Sprintf("https://example.com/%v", uuid.New())), Description: text(description),
+		PublishedAt: valid(published), FeedID: id(feed.ID), Author: text("Ann Writer"),
+	})
+	if err != nil {
+		t.Fatalf("CreatePost Failed %v", err)
+	}
+	return post
+}
+
+func titles[T any](rows []T, post func(T) database.Post) []string {
+	var got []string
+	for _, row := range rows {
+		got = append(got, post(row).Title.String)
+	}
+	return got
+}
+
+func TestUsersAndFeeds(t *testing.T) {
+	forEachStore(t, func(t *testing.T, q database.Store) {
+		alice := createUser(t, q, "alice")
+		createUser(t, q, "bob")
+
+		got, err := q.GetUser(ctx, "alice")
+		if err != nil {
+			t.Fatalf("GetUser Failed %v", err)
+		}
+		// times come back as they were written, zone and all
+		if !reflect.DeepEqual(got, alice) || got.CreatedAt.Time != at(1, 8) {
+			t.Errorf("GetUser Mismatch wanted: %+v , got: %+v", alice, got)
+		}
+		if _, err := q.GetUser(ctx, "carol"); err != sql.ErrNoRows {
+			t.Errorf("GetUser Mismatch wanted: %v , got: %v", sql.ErrNoRows, err)
+		}
+		if users, err := q.GetUsers(ctx); err != nil || len(users) != 2 {
+			t.Errorf("GetUsers Mismatch wanted 2 users, got: %v %v", len(users), err)
+		}
+
+		feed := createFeed(t, q, alice, "go")
+		byKey, err := q.GetFeedByURLKey(ctx, feed.UrlKey)
+		if err != nil || byKey.ID != feed.ID || byKey.Kind != "rss" || byKey.LastFetchedAt.Valid {
+			t.Errorf("GetFeedByURLKey Mismatch wanted: %+v , got: %+v %v", feed, byKey, err)
+		}
+		err = q.InTx(ctx, func(tx database.Store) error {
+			locked, err := tx.LockFeedByURLKey(ctx, feed.UrlKey)
+			if err == nil && locked.ID != feed.ID {
+				t.Errorf("LockFeedByURLKey Mismatch wanted: %v , got: %v", feed.ID, locked.ID)
+			}
+			return err
+		})
+		if err != nil {
+			t.Errorf("LockFeedByURLKey Failed %v", err)
+		}
+		_, err = q.CreateFeed(ctx, database.CreateFeedParams{ID: uuid.New(), Name: text("again"), Url: feed.Url, UrlKey: text("other"), Kind: "rss"})
+		if err == nil {
+			t.Errorf("CreateFeed Failed, wanted an error for a second feed with the same url")
+		}
+		_, err = q.CreateFeed(ctx, database.CreateFeedParams{ID: uuid.New(), Name: text("again"), Url: text("https://other"), UrlKey: feed.UrlKey, Kind: "rss"})
+		if err == nil {
+			t.Errorf("CreateFeed Failed, wanted an error for a second feed with the same url key")
+		}
+
+		err = q.SetFeedSiteURL(ctx, database.SetFeedSiteURLParams{ID: feed.ID, SiteUrl: text("https://go.dev"), UpdatedAt: valid(at(2, 8))})
+		if err != nil {
+			t.Fatalf("SetFeedSiteURL Failed %v", err)
+		}
+		byURL, err := q.GetFeedByURL(ctx, feed.Url)
+		if err != nil || byURL.SiteUrl.String != "https://go.dev" || byURL.UpdatedAt.Time != at(2, 8) {
+			t.Errorf("GetFeedByURL Mismatch wanted the site url, got: %+v %v", byURL, err)
+		}
+
+		scraper, err := q.CreateFeedScraper(ctx, database.CreateFeedScraperParams{FeedID: feed.ID, ItemSelector: "li", TitleSelector: "a", LinkSelector: "a", DateFormat: text("2006-01-02")})
+		if err != nil {
+			t.Fatalf("CreateFeedScraper Failed %v", err)
+		}
+		if got, err := q.GetFeedScraper(ctx, feed.ID); err != nil || !reflect.DeepEqual(got, scraper) {
+			t.Errorf("GetFeedScraper Mismatch wanted: %+v , got: %+v %v", scraper, got, err)
+		}
+		watch, err := q.CreateFeedWatch(ctx, database.CreateFeedWatchParams{FeedID: feed.ID, Selector: text("main"), Threshold: 0.25})
+		if err != nil {
+			t.Fatalf("CreateFeedWatch Failed %v", err)
+		}
+		if got, err := q.GetFeedWatch(ctx, feed.ID); err != nil || !reflect.DeepEqual(got, watch) {
+			t.Errorf("GetFeedWatch Mismatch wanted: %+v , got: %+v %v", watch, got, err)
+		}
+		for i, content := range []string{"first", "second"} {
+			_, err := q.CreatePageSnapshot(ctx, database.CreatePageSnapshotParams{ID: uuid.New(), CreatedAt: at(2, 8+i), FeedID: feed.ID, Content: content})
+			if err != nil {
+				t.Fatalf("CreatePageSnapshot Failed %v", err)
+			}
+		}
+		if got, err := q.GetLatestPageSnapshot(ctx, feed.ID); err != nil || got.Content != "second" {
+			t.Errorf("GetLatestPageSnapshot Mismatch wanted: second , got: %+v %v", got, err)
+		}
+	})
+}
+
+func TestFollowsAndFolders(t *testing.T) {
+	forEachStore(t, func(t *testing.T, q database.Store) {
+		alice, bob := createUser(t, q, "alice"), createUser(t, q, "bob")
+		golang, rust, zig := createFeed(t, q, alice, "go"), createFeed(t, q, alice, "rust"), createFeed(t, q, bob, "zig")
+
+		row := follow(t, q, alice, golang)
+		if row.UserName != "alice" || row.FeedName.String != "go" || row.Hidden || row.FolderID.Valid {
+			t.Errorf("CreateFeedFollow Mismatch wanted alice and go, got: %+v", row)
+		}
+		_, err := q.CreateFeedFollow(ctx, database.CreateFeedFollowParams{ID: uuid.New(), UserID: id(alice.ID), FeedID: id(golang.ID)})
+		if err == nil {
+			t.Errorf("CreateFeedFollow Failed, wanted an error for a second follow of the same feed")
+		}
+		follow(t, q, alice, rust)
+		follow(t, q, alice, zig)
+		follow(t, q, bob, zig)
+
+		folder, err := q.CreateFolder(ctx, database.CreateFolderParams{ID: uuid.New(), UserID: alice.ID, Name: "systems"})
+		if err != nil {
+			t.Fatalf("CreateFolder Failed %v", err)
+		}
+		for _, feed := range []database.Feed{rust, zig} {
+			n, err := q.SetFeedFollowFolder(ctx, database.SetFeedFollowFolderParams{UserID: id(alice.ID), FeedID: id(feed.ID), FolderID: id(folder.ID)})
+			if err != nil || n != 1 {
+				t.Fatalf("SetFeedFollowFolder Failed %v %v", n, err)
+			}
+		}
+		// feeds without a folder first, then by folder and by feed name
+		follows, err := q.GetFeedFollowsForUser(ctx, id(alice.ID))
+		if err != nil {
+			t.Fatalf("GetFeedFollowsForUser Failed %v", err)
+		}
+		var got []string
+		for _, f := range follows {
+			got = append(got, f.FolderName.String+"/"+f.FeedName.String+"/"+f.DefaultSort)
+		}
+		if want := []string{"/go/newest", "systems/rust/newest", "systems/zig/newest"}; !reflect.DeepEqual(got, want) {
+			t.Errorf("GetFeedFollowsForUser Mismatch wanted: %v , got: %v", want, got)
+		}
+
+		n, err := q.UpdateFeedFollowSettings(ctx, database.UpdateFeedFollowSettingsParams{UserID: id(bob.ID), FeedID: id(zig.ID), DisplayName: text("Ziglang"), Notify: true, FullText: true, DefaultSort: "oldest"})
+		if err != nil || n != 1 {
+			t.Fatalf("UpdateFeedFollowSettings Failed %v %v", n, err)
+		}
+		if wants, err := q.FeedWantsFullText(ctx, id(zig.ID)); err != nil || !wants {
+			t.Errorf("FeedWantsFullText Mismatch wanted: true , got: %v %v", wants, err)
+		}
+		if wants, err := q.FeedWantsFullText(ctx, id(rust.ID)); err != nil || wants {
+			t.Errorf("FeedWantsFullText Mismatch wanted: false , got: %v %v", wants, err)
+		}
+		notify, err := q.GetNotifyFollowers(ctx, id(zig.ID))
+		if err != nil || len(notify) != 1 || notify[0].UserName != "bob" || notify[0].FeedName != "Ziglang" {
+			t.Errorf("GetNotifyFollowers Mismatch wanted bob and Ziglang, got: %+v %v", notify, err)
+		}
+		settings, err := q.GetFeedFollow(ctx, database.GetFeedFollowParams{UserID: id(bob.ID), FeedID: id(zig.ID)})
+		if err != nil || !settings.Notify || !settings.FullText || settings.Hidden || settings.DefaultSort != "oldest" {
+			t.Errorf("GetFeedFollow Mismatch wanted notify, full text and oldest first, got: %+v %v", settings, err)
+		}
+
+		if n, err := q.RenameFolder(ctx, database.RenameFolderParams{NewName: "low level", UserID: alice.ID, Name: "systems"}); err != nil || n != 1 {
+			t.Errorf("RenameFolder Failed %v %v", n, err)
+		}
+		if n, err := q.DeleteFolder(ctx, database.DeleteFolderParams{UserID: alice.ID, Name: "low level"}); err != nil || n != 1 {
+			t.Errorf("DeleteFolder Failed %v %v", n, err)
+		}
+		// the follows of a deleted folder stay, without a folder
+		if got, err := q.GetFeedFollow(ctx, database.GetFeedFollowParams{UserID: id(alice.ID), FeedID: id(zig.ID)}); err != nil || got.FolderID.Valid {
+			t.Errorf("GetFeedFollow Mismatch wanted no folder, got: %+v %v", got, err)
+		}
+
+		if err := q.DeleteFeedFollow(ctx, database.DeleteFeedFollowParams{UserID: id(alice.ID), FeedID: id(rust.ID)}); err != nil {
+			t.Fatalf("DeleteFeedFollow Failed %v", err)
+		}
+		if follows, err := q.GetFeedFollowsForUser(ctx, id(alice.ID)); err != nil || len(follows) != 2 {
+			t.Errorf("GetFeedFollowsForUser Mismatch wanted 2 follows, got: %v %v", len(follows), err)
+		}
+	})
+}
+
+func TestPostsAndReads(t *testing.T) {
+	forEachStore(t, func(t *testing.T, q database.Store) {
+		alice := createUser(t, q, "alice")
+		golang, rust := createFeed(t, q, alice, "go"), createFeed(t, q, alice, "rust")
+		follow(t, q, alice, golang)
+		first := createPost(t, q, golang, "first", "", at(3, 8))
+		second := createPost(t, q, golang, "second", "", at(4, 8))
+		third := createPost(t, q, rust, "third", "", at(5, 8))
+
+		if _, err := q.CreatePost(ctx, database.CreatePostParams{ID: uuid.New(), Url: first.Url}); err != sql.ErrNoRows {
+			t.Errorf("CreatePost Mismatch wanted: %v for a second post with the same url , got: %v", sql.ErrNoRows, err)
+		}
+		posts, err := q.GetPostsForUser(ctx, database.GetPostsForUserParams{ID: alice.ID, Limit: 5})
+		if err != nil {
+			t.Fatalf("GetPostsForUser Failed %v", err)
+		}
+		if got, want := titles(posts, func(p database.Post) database.Post { return p }), []string{"second", "first"}; !reflect.DeepEqual(got, want) {
+			t.Errorf("GetPostsForUser Mismatch wanted: %v , got: %v", want, got)
+		}
+		if !reflect.DeepEqual(posts[1], first) {
+			t.Errorf("GetPostsForUser Mismatch wanted: %+v , got: %+v", first, posts[1])
+		}
+
+		// rust is not followed yet, and wildcards match nothing
+		for _, prefix := range []string{third.ID.String()[:8], "%", "________"} {
+			if got, err := q.GetPostsByIDPrefix(ctx, database.GetPostsByIDPrefixParams{Prefix: prefix, UserID: alice.ID}); err != nil || len(got) != 0 {
+				t.Errorf("GetPostsByIDPrefix Mismatch wanted none for %v , got: %+v %v", prefix, got, err)
+			}
+		}
+
+		follow(t, q, alice, rust)
+		prefix, err := q.GetPostsByIDPrefix(ctx, database.GetPostsByIDPrefixParams{Prefix: third.ID.String()[:8], UserID: alice.ID})
+		if err != nil || len(prefix) != 1 || prefix[0].Post.ID != third.ID || prefix[0].FeedName.String != "rust" {
+			t.Errorf("GetPostsByIDPrefix Mismatch wanted: %v , got: %+v %v", third.ID, prefix, err)
+		}
+		unread := func() map[uuid.UUID]int64 {
+			rows, err := q.GetUnreadCountsForUser(ctx, id(alice.ID))
+			if err != nil {
+				t.Fatalf("GetUnreadCountsForUser Failed %v", err)
+			}
+			counts := map[uuid.UUID]int64{}
+			for _, row := range rows {
+				counts[row.FeedID.UUID] = row.Unread
+			}
+			return counts
+		}
+		if got, want := unread(), map[uuid.UUID]int64{golang.ID: 2, rust.ID: 1}; !reflect.DeepEqual(got, want) {
+			t.Errorf("unread Mismatch wanted: %v , got: %v", want, got)
+		}
+		for i := 0; i < 2; i++ {
+			if err := q.MarkPostRead(ctx, database.MarkPostReadParams{UserID: alice.ID, PostID: first.ID, ReadAt: at(6, 8)}); err != nil {
+				t.Fatalf("MarkPostRead Failed %v", err)
+			}
+		}
+		n, err := q.MarkFeedPostsReadBefore(ctx, database.MarkFeedPostsReadBeforeParams{UserID: alice.ID, ReadAt: at(6, 8), FeedID: golang.ID, Before: second.PublishedAt.Time.Add(time.Second)})
+		if err != nil || n != 1 {
+			t.Errorf("MarkFeedPostsReadBefore Mismatch wanted: 1 , got: %v %v", n, err)
+		}
+		if got, want := unread(), map[uuid.UUID]int64{rust.ID: 1}; !reflect.DeepEqual(got, want) {
+			t.Errorf("unread Mismatch wanted: %v , got: %v", want, got)
+		}
+		if err := q.MarkPostUnread(ctx, database.MarkPostUnreadParams{UserID: alice.ID, PostID: second.ID}); err != nil {
+			t.Fatalf("MarkPostUnread Failed %v", err)
+		}
+		if n, err := q.MarkAllPostsRead(ctx, database.MarkAllPostsReadParams{ReadAt: at(6, 9), UserID: alice.ID}); err != nil || n != 2 {
+			t.Errorf("MarkAllPostsRead Mismatch wanted: 2 , got: %v %v", n, err)
+		}
+		if got := unread(); len(got) != 0 {
+			t.Errorf("unread Mismatch wanted none, got: %v", got)
+		}
+
+		err = q.UpdatePostContent(ctx, database.UpdatePostContentParams{ID: third.ID, UpdatedAt: valid(at(7, 8)), Content: text("<p>full text</p>")})
+		if err != nil {
+			t.Fatalf("UpdatePostContent Failed %v", err)
+		}
+		prefix, err = q.GetPostsByIDPrefix(ctx, database.GetPostsByIDPrefixParams{Prefix: third.ID.String(), UserID: alice.ID})
+		if err != nil || len(prefix) != 1 || prefix[0].Post.Content.String != "<p>full text</p>" {
+			t.Errorf("UpdatePostContent Mismatch wanted the content, got: %+v %v", prefix, err)
+		}
+	})
+}
+
+func TestStarsAndReadLater(t *testing.T) {
+	forEachStore(t, func(t *testing.T, q database.Store) {
+		alice := createUser(t, q, "alice")
+		feed := createFeed(t, q, alice, "go")
+		first := createPost(t, q, feed, "first", "", at(3, 8))
+		second := createPost(t, q, feed, "second", "", at(4, 8))
+
+		for _, star := range []struct {
+			post database.Post
+			at   time.Time
+		}{{first, at(6, 8)}, {second, at(6, 9)}, {first, at(6, 10)}} {
+			if err := q.StarPost(ctx, database.StarPostParams{UserID: alice.ID, PostID: star.post.ID, StarredAt: star.at}); err != nil {
+				t.Fatalf("StarPost Failed %v", err)
+			}
+		}
+		starred, err := q.GetStarredPosts(ctx, alice.ID)
+		if err != nil {
+			t.Fatalf("GetStarredPosts Failed %v", err)
+		}
+		// starring again keeps the first star
+		if got, want := titles(starred, func(r database.GetStarredPostsRow) database.Post { return r.Post }), []string{"second", "first"}; !reflect.DeepEqual(got, want) || starred[1].StarredAt != at(6, 8) {
+			t.Errorf("GetStarredPosts Mismatch wanted: %v , got: %v %v", want, got, starred[1].StarredAt)
+		}
+		if n, err := q.UnstarPost(ctx, database.UnstarPostParams{UserID: alice.ID, PostID: second.ID}); err != nil || n != 1 {
+			t.Errorf("UnstarPost Failed %v %v", n, err)
+		}
+
+		for _, post := range []database.Post{second, first, second} {
+			if _, err := q.AddToReadLater(ctx, database.AddToReadLaterParams{UserID: alice.ID, PostID: post.ID, AddedAt: at(7, 8)}); err != nil {
+				t.Fatalf("AddToReadLater Failed %v", err)
+			}
+		}
+		queue, err := q.GetReadLater(ctx, alice.ID)
+		if err != nil {
+			t.Fatalf("GetReadLater Failed %v", err)
+		}
+		if got, want := titles(queue, func(r database.GetReadLaterRow) database.Post { return r.Post }), []string{"second", "first"}; !reflect.DeepEqual(got, want) || queue[1].Position != 2 || queue[1].FeedName.String != "go" {
+			t.Errorf("GetReadLater Mismatch wanted: %v , got: %v %+v", want, got, queue)
+		}
+		if n, err := q.RemoveFromReadLater(ctx, database.RemoveFromReadLaterParams{UserID: alice.ID, PostID: second.ID}); err != nil || n != 1 {
+			t.Errorf("RemoveFromReadLater Failed %v %v", n, err)
+		}
+	})
+}
+
+func TestSearch(t *testing.T) {
+	forEachStore(t, func(t *testing.T, q database.Store) {
+		alice := createUser(t, q, "alice")
+		golang, rust := createFeed(t, q, alice, "go"), createFeed(t, q, alice, "rust")
+		follow(t, q, alice, golang)
+		createPost(t, q, golang, "Generics in Go", "type parameters arrived", at(3, 8))
+		createPost(t, q, golang, "Go release notes", "the release adds generics to more packages", at(4, 8))
+		createPost(t, q, golang, "Fuzzing", "a new way of testing", at(5, 8))
+		createPost(t, q, rust, "Rust generics", "traits and generics", at(6, 8))
+
+		search := func(query string, all bool) []database.SearchPostsRow {
+			t.Helper()
+			rows, err := q.SearchPosts(ctx, database.SearchPostsParams{Query: query, UserID: id(alice.ID), AllFeeds: all, MaxPosts: 10})
+			if err != nil {
+				t.Fatalf("SearchPosts %q Failed %v", query, err)
+			}
+			return rows
+		}
+		cases := map[string]struct {
+			query string
+			all   bool
+			want  []string
+		}{
+			// a title match ranks above a description match
+			"ranked":           {query: "generics", want: []string{"Generics in Go", "Go release notes"}},
+			"all feeds":        {query: "rust", all: true, want: []string{"Rust generics"}},
+			"followed only":    {query: "rust"},
+			"every word":       {query: "generics release", want: []string{"Go release notes"}},
+			"either word":      {query: "fuzzing or parameters", want: []string{"Fuzzing", "Generics in Go"}},
+			"excluded word":    {query: "generics -release", want: []string{"Generics in Go"}},
+			"phrase":           {query: `"type parameters"`, want: []string{"Generics in Go"}},
+			"phrase not found": {query: `"parameters type"`},
+		}
+		for name, tc := range cases {
+			t.Run(name, func(t *testing.T) {
+				rows := search(tc.query, tc.all)
+				got := titles(rows, func(r database.SearchPostsRow) database.Post { return r.Post })
+				if tc.want == nil {
+					tc.want = []string{}
+				}
+				if got == nil {
+					got = []string{}
+				}
+				// either word has no order, the posts match as well
+				if name == "either word" && len(got) == 2 && got[0] != tc.want[0] {
+					got[0], got[1] = got[1], got[0]
+				}
+				if !reflect.DeepEqual(got, tc.want) {
+					t.Errorf("SearchPosts %q Mismatch wanted: %v , got: %v", tc.query, tc.want, got)
+				}
+			})
+		}
+
+		rows := search("release", false)
+		if len(rows) != 1 || !strings.Contains(rows[0].Snippet, "[[release]]") || rows[0].FeedName != "go" || rows[0].Rank <= 0 {
+			t.Errorf("SearchPosts Mismatch wanted a snippet with [[release]], got: %+v", rows)
+		}
+
+		_, err := q.CreateSavedSearch(ctx, database.CreateSavedSearchParams{ID: uuid.New(), UserID: alice.ID, Name: "generics", Query: "generics"})
+		if err != nil {
+			t.Fatalf("CreateSavedSearch Failed %v", err)
+		}
+		if _, err := q.CreateSavedSearch(ctx, database.CreateSavedSearchParams{ID: uuid.New(), UserID: alice.ID, Name: "generics", Query: "go"}); err == nil {
+			t.Errorf("CreateSavedSearch Failed, wanted an error for a second search with the same name")
+		}
+		saved, err := q.GetSavedSearchesForUser(ctx, alice.ID)
+		if err != nil || len(saved) != 1 || saved[0].Unread != 2 {
+			t.Errorf("GetSavedSearchesForUser Mismatch wanted 2 unread, got: %+v %v", saved, err)
+		}
+	})
+}
+
+// the databases stem words, the memory store does not
+func TestSearchStemming(t *testing.T) {
+	forEachDatabase(t, func(t *testing.T, q database.Store) {
+		alice := createUser(t, q, "alice")
+		feed := createFeed(t, q, alice, "go")
+		follow(t, q, alice, feed)
+		createPost(t, q, feed, "Fuzzing", "a new way of testing", at(5, 8))
+		rows, err := q.SearchPosts(ctx, database.SearchPostsParams{Query: "tests", UserID: id(alice.ID), MaxPosts: 10})
+		if err != nil || len(rows) != 1 {
+			t.Errorf("SearchPosts Mismatch wanted: [Fuzzing] , got: %+v %v", rows, err)
+		}
+	})
+}
+
+func TestBrowse(t *testing.T) {
+	forEachStore(t, func(t *testing.T, q database.Store) {
+		alice := createUser(t, q, "alice")
+		golang, rust := createFeed(t, q, alice, "go"), createFeed(t, q, alice, "rust")
+		follow(t, q, alice, golang)
+		follow(t, q, alice, rust)
+		// the same published time, the id decides between them
+		a := createPost(t, q, golang, "a", "", at(3, 8))
+		b := createPost(t, q, rust, "b", "", at(3, 8))
+		tied := []string{"b", "a"}
+		if a.ID.String() > b.ID.String() {
+			tied = []string{"a", "b"}
+		}
+		read := createPost(t, q, golang, "c", "100% sure", at(4, 8))
+		createPost(t, q, rust, "d", "", at(5, 8))
+		if err := q.MarkPostRead(ctx, database.MarkPostReadParams{UserID: alice.ID, PostID: read.ID, ReadAt: at(6, 8)}); err != nil {
+			t.Fatalf("MarkPostRead Failed %v", err)
+		}
+
+		all, err := q.Browse(ctx, database.BrowseParams{UserID: alice.ID, Limit: 10})
+		if err != nil {
+			t.Fatalf("Browse Failed %v", err)
+		}
+		if len(all) != 4 || all[0].Post.Title.String != "d" || !all[1].IsRead {
+			t.Fatalf("Browse Mismatch wanted d first and c read, got: %+v", all)
+		}
+		for _, sort := range []database.BrowseSort{database.BrowseByPublished, database.BrowseByFetched, database.BrowseByFeed} {
+			for _, reverse := range []bool{false, true} {
+				arg := database.BrowseParams{UserID: alice.ID, Sort: sort, Reverse: reverse, Limit: 10}
+				whole, err := q.Browse(ctx, arg)
+				if err != nil {
+					t.Fatalf("Browse Failed %v", err)
+				}
+				// page by page gives the same posts in the same order
+				arg.Limit = 1
+				var paged []database.BrowseRow
+				for range whole {
+					page, err := q.Browse(ctx, arg)
+					if err != nil || len(page) != 1 {
+						t.Fatalf("Browse %v Failed %v %v", sort, page, err)
+					}
+					paged = append(paged, page...)
+					arg.After = database.BrowseCursor(arg, page[0])
+				}
+				if rest, err := q.Browse(ctx, arg); err != nil || len(rest) != 0 {
+					t.Errorf("Browse %v Mismatch wanted no posts after the last, got: %v %v", sort, rest, err)
+				}
+				if !reflect.DeepEqual(paged, whole) {
+					t.Errorf("Browse %v reverse %v Mismatch wanted: %v , got: %v", sort, reverse, whole, paged)
+				}
+			}
+		}
+
+		cases := map[string]struct {
+			arg  database.BrowseParams
+			want []string
+		}{
+			"unread":          {arg: database.BrowseParams{UnreadOnly: true}, want: append([]string{"d"}, tied...)},
+			"feed":            {arg: database.BrowseParams{FeedID: id(golang.ID)}, want: []string{"c", "a"}},
+			"since":           {arg: database.BrowseParams{Since: valid(at(4, 8))}, want: []string{"d", "c"}},
+			"until":           {arg: database.BrowseParams{Until: valid(at(4, 8)), Sort: database.BrowseByFeed}, want: []string{"a", "b"}},
+			"keyword literal": {arg: database.BrowseParams{Keyword: "0% S"}, want: []string{"c"}},
+			"keyword percent": {arg: database.BrowseParams{Keyword: "1%s"}},
+			"author":          {arg: database.BrowseParams{Author: "ann w", FeedID: id(rust.ID)}, want: []string{"d", "b"}},
+			"search":          {arg: database.BrowseParams{Search: text("sure")}, want: []string{"c"}},
+		}
+		for name, tc := range cases {
+			t.Run(name, func(t *testing.T) {
+				tc.arg.UserID, tc.arg.Limit = alice.ID, 10
+				rows, err := q.Browse(ctx, tc.arg)
+				if err != nil {
+					t.Fatalf("Browse Failed %v", err)
+				}
+				got := titles(rows, func(r database.BrowseRow) database.Post { return r.Post })
+				if !reflect.DeepEqual(got, tc.want) {
+					t.Errorf("Browse Mismatch wanted: %v , got: %v", tc.want, got)
+				}
+			})
+		}
+	})
+}
+
+func TestFetchOrderAndDeletes(t *testing.T) {
+	forEachStore(t, func(t *testing.T, q database.Store) {
+		alice, bob := createUser(t, q, "alice"), createUser(t, q, "bob")
+		golang, rust := createFeed(t, q, alice, "go"), createFeed(t, q, bob, "rust")
+
+		// never fetched feeds come first, then the least recently fetched
+		if err := q.MarkFeedFetched(ctx, database.MarkFeedFetchedParams{ID: golang.ID, UpdatedAt: valid(at(2, 8))}); err != nil {
+			t.Fatalf("MarkFeedFetched Failed %v", err)
+		}
+		if next, err := q.GetNextFeedToFetch(ctx); err != nil || next.ID != rust.ID {
+			t.Errorf("GetNextFeedToFetch Mismatch wanted: rust , got: %v %v", next.Name.String, err)
+		}
+		if err := q.MarkFeedFetched(ctx, database.MarkFeedFetchedParams{ID: rust.ID, UpdatedAt: valid(at(2, 9))}); err != nil {
+			t.Fatalf("MarkFeedFetched Failed %v", err)
+		}
+		next, err := q.GetNextFeedToFetch(ctx)
+		if err != nil || next.ID != golang.ID || next.LastFetchedAt.Time != at(2, 8) {
+			t.Errorf("GetNextFeedToFetch Mismatch wanted: go , got: %+v %v", next, err)
+		}
+
+		starred := createPost(t, q, rust, "starred", "", at(3, 8))
+		createPost(t, q, rust, "orphan", "", at(3, 9))
+		follow(t, q, alice, rust)
+		if err := q.StarPost(ctx, database.StarPostParams{UserID: alice.ID, PostID: starred.ID, StarredAt: at(4, 8)}); err != nil {
+			t.Fatalf("StarPost Failed %v", err)
+		}
+
+		// the feeds stay without an owner, their posts stay without a feed
+		// once the feeds are deleted too, until pruned
+		if _, err := q.DeleteFolder(ctx, database.DeleteFolderParams{UserID: bob.ID, Name: "none"}); err != nil {
+			t.Fatalf("DeleteFolder Failed %v", err)
+		}
+		if err := q.DeleteUsers(ctx); err != nil {
+			t.Fatalf("DeleteUsers Failed %v", err)
+		}
+		feeds, err := q.GetFeeds(ctx)
+		if err != nil || len(feeds) != 2 || feeds[0].UserID.Valid || feeds[1].UserID.Valid {
+			t.Errorf("GetFeeds Mismatch wanted 2 feeds without an owner, got: %+v %v", feeds, err)
+		}
+		if err := q.DeleteFeeds(ctx); err != nil {
+			t.Fatalf("DeleteFeeds Failed %v", err)
+		}
+		if n, err := q.PruneOrphanedPosts(ctx); err != nil || n != 2 {
+			t.Errorf("PruneOrphanedPosts Mismatch wanted: 2 , got: %v %v", n, err)
+		}
+	})
+}
+
+func TestConstraints(t *testing.T) {
+	forEachStore(t, func(t *testing.T, q database.Store) {
+		alice, bob := createUser(t, q, "alice"), createUser(t, q, "bob")
+		feed := createFeed(t, q, alice, "go")
+		post := createPost(t, q, feed, "first", "", at(3, 8))
+
+		// the same name for two users, not twice for one
+		for _, user := range []database.User{alice, bob} {
+			if _, err := q.CreateFolder(ctx, database.CreateFolderParams{ID: uuid.New(), UserID: user.ID, Name: "news"}); err != nil {
+				t.Fatalf("CreateFolder Failed %v", err)
+			}
+		}
+		if _, err := q.CreateFolder(ctx, database.CreateFolderParams{ID: uuid.New(), UserID: alice.ID, Name: "news"}); err == nil {
+			t.Errorf("CreateFolder Failed, wanted an error for a second folder with the same name")
+		}
+		if _, err := q.CreateFolder(ctx, database.CreateFolderParams{ID: uuid.New(), UserID: alice.ID, Name: "blogs"}); err != nil {
+			t.Fatalf("CreateFolder Failed %v", err)
+		}
+		if _, err := q.RenameFolder(ctx, database.RenameFolderParams{NewName: "news", UserID: alice.ID, Name: "blogs"}); err == nil {
+			t.Errorf("RenameFolder Failed, wanted an error for a name already taken")
+		}
+
+		missing := uuid.New()
+		cases := map[string]func() error{
+			"follow of a missing feed": func() error {
+				_, err := q.CreateFeedFollow(ctx, database.CreateFeedFollowParams{ID: uuid.New(), UserID: id(alice.ID), FeedID: id(missing)})
+				return err
+			},
+			"feed of a missing user": func() error {
+				_, err := q.CreateFeed(ctx, database.CreateFeedParams{ID: uuid.New(), Name: text("x"), Url: text("https://x"), UserID: id(missing), Kind: "rss"})
+				return err
+			},
+			"post of a missing feed": func() error {
+				_, err := q.CreatePost(ctx, database.CreatePostParams{ID: uuid.New(), Url: text("https://x/1"), FeedID: id(missing)})
+				return err
+			},
+			"star of a missing post": func() error {
+				return q.StarPost(ctx, database.StarPostParams{UserID: alice.ID, PostID: missing, StarredAt: at(4, 8)})
+			},
+			"read of a missing user": func() error {
+				return q.MarkPostRead(ctx, database.MarkPostReadParams{UserID: missing, PostID: post.ID, ReadAt: at(4, 8)})
+			},
+			"search of a missing user": func() error {
+				_, err := q.CreateSavedSearch(ctx, database.CreateSavedSearchParams{ID: uuid.New(), UserID: missing, Name: "x", Query: "x"})
+				return err
+			},
+		}
+		for name, create := range cases {
+			t.Run(name, func(t *testing.T) {
+				if err := create(); err == nil {
+					t.Errorf("%v Failed, wanted a foreign key error", name)
+				}
+			})
+		}
+
+		if _, err := q.GetUser(ctx, "carol"); !errors.Is(err, sql.ErrNoRows) {
+			t.Errorf("GetUser Mismatch wanted: %v , got: %v", sql.ErrNoRows, err)
+		}
+		if _, err := q.GetNextFeedToFetch(ctx); err != nil {
+			t.Errorf("GetNextFeedToFetch Failed %v", err)
+		}
+		if err := q.DeleteFeeds(ctx); err != nil {
+			t.Fatalf("DeleteFeeds Failed %v", err)
+		}
+		if _, err := q.GetNextFeedToFetch(ctx); !errors.Is(err, sql.ErrNoRows) {
+			t.Errorf("GetNextFeedToFetch Mismatch wanted: %v , got: %v", sql.ErrNoRows, err)
+		}
+	})
+}
+
+func TestFeedOwners(t *testing.T) {
+	forEachStore(t, func(t *testing.T, q database.Store) {
+		alice, bob := createUser(t, q, "alice"), createUser(t, q, "bob")
+		golang, rust, zig := createFeed(t, q, alice, "go"), createFeed(t, q, alice, "rust"), createFeed(t, q, alice, "zig")
+		follow(t, q, alice, golang)
+		follow(t, q, alice, rust)
+		follow(t, q, bob, golang)
+		post := createPost(t, q, golang, "first", "", at(3, 8))
+
+		if n, err := q.SetUserAdmin(ctx, database.SetUserAdminParams{ID: bob.ID, IsAdmin: true, UpdatedAt: valid(at(2, 8))}); err != nil || n != 1 {
+			t.Errorf("SetUserAdmin Mismatch wanted: 1 , got: %v %v", n, err)
+		}
+		if user, err := q.GetUser(ctx, "bob"); err != nil || !user.IsAdmin {
+			t.Errorf("GetUser Mismatch wanted an admin, got: %+v %v", user, err)
+		}
+
+		if n, err := q.RenameFeed(ctx, database.RenameFeedParams{ID: golang.ID, Name: text("golang"), UpdatedAt: valid(at(2, 8))}); err != nil || n != 1 {
+			t.Errorf("RenameFeed Mismatch wanted: 1 , got: %v %v", n, err)
+		}
+		if _, err := q.SetFeedURL(ctx, database.SetFeedURLParams{ID: golang.ID, Url: rust.Url, UrlKey: text("other"), UpdatedAt: valid(at(2, 8))}); err == nil {
+			t.Errorf("SetFeedURL Failed, wanted an error for the url of another feed")
+		}
+		if _, err := q.SetFeedURL(ctx, database.SetFeedURLParams{ID: golang.ID, Url: text("https://other"), UrlKey: rust.UrlKey, UpdatedAt: valid(at(2, 8))}); err == nil {
+			t.Errorf("SetFeedURL Failed, wanted an error for the url key of another feed")
+		}
+		if n, err := q.SetFeedURL(ctx, database.SetFeedURLParams{ID: golang.ID, Url: text("https://go.dev/feed"), UrlKey: text("go.dev/feed"), UpdatedAt: valid(at(2, 8))}); err != nil || n != 1 {
+			t.Errorf("SetFeedURL Mismatch wanted: 1 , got: %v %v", n, err)
+		}
+		if feed, err := q.GetFeedByURLKey(ctx, text("go.dev/feed")); err != nil || feed.ID != golang.ID || feed.Name.String != "golang" {
+			t.Errorf("GetFeedByURLKey Mismatch wanted: golang , got: %+v %v", feed, err)
+		}
+		if _, err := q.SetFeedOwner(ctx, database.SetFeedOwnerParams{ID: zig.ID, UserID: id(uuid.New()), UpdatedAt: valid(at(2, 8))}); err == nil {
+			t.Errorf("SetFeedOwner Failed, wanted a foreign key error")
+		}
+
+		// the feeds only alice reads go, the one bob follows stays without an owner
+		if n, err := q.DeleteUnsharedFeeds(ctx, alice.ID); err != nil || n != 2 {
+			t.Errorf("DeleteUnsharedFeeds Mismatch wanted: 2 , got: %v %v", n, err)
+		}
+		if n, err := q.DeleteUser(ctx, alice.ID); err != nil || n != 1 {
+			t.Errorf("DeleteUser Mismatch wanted: 1 , got: %v %v", n, err)
+		}
+		feeds, err := q.GetFeeds(ctx)
+		if err != nil || len(feeds) != 1 || feeds[0].ID != golang.ID || feeds[0].UserID.Valid {
+			t.Errorf("GetFeeds Mismatch wanted golang without an owner, got: %+v %v", feeds, err)
+		}
+		if follows, err := q.GetFeedFollowsForUser(ctx, id(bob.ID)); err != nil || len(follows) != 1 {
+			t.Errorf("GetFeedFollowsForUser Mismatch wanted: 1 , got: %v %v", follows, err)
+		}
+		if n, err := q.SetFeedOwner(ctx, database.SetFeedOwnerParams{ID: golang.ID, UserID: id(bob.ID), UpdatedAt: valid(at(2, 9))}); err != nil || n != 1 {
+			t.Errorf("SetFeedOwner Mismatch wanted: 1 , got: %v %v", n, err)
+		}
+
+		if err := q.StarPost(ctx, database.StarPostParams{UserID: bob.ID, PostID: post.ID, StarredAt: at(3, 9)}); err != nil {
+			t.Fatalf("StarPost Failed %v", err)
+		}
+		if n, err := q.DeleteFeed(ctx, golang.ID); err != nil || n != 1 {
+			t.Errorf("DeleteFeed Mismatch wanted: 1 , got: %v %v", n, err)
+		}
+		if follows, err := q.GetFeedFollowsForUser(ctx, id(bob.ID)); err != nil || len(follows) != 0 {
+			t.Errorf("GetFeedFollowsForUser Mismatch wanted none, got: %v %v", follows, err)
+		}
+		// bob still finds the starred post, alice lost it with the feed
+		if got, err := q.GetPostsByIDPrefix(ctx, database.GetPostsByIDPrefixParams{Prefix: post.ID.String(), UserID: bob.ID}); err != nil || len(got) != 1 || got[0].Post.FeedID.Valid {
+			t.Errorf("GetPostsByIDPrefix Mismatch wanted the post without a feed, got: %+v %v", got, err)
+		}
+		if got, err := q.GetPostsByIDPrefix(ctx, database.GetPostsByIDPrefixParams{Prefix: post.ID.String(), UserID: alice.ID}); err != nil || len(got) != 0 {
+			t.Errorf("GetPostsByIDPrefix Mismatch wanted none, got: %+v %v", got, err)
+		}
+	})
+}
+
+func TestInTx(t *testing.T) {
+	forEachStore(t, func(t *testing.T, q database.Store) {
+		alice := createUser(t, q, "alice")
+		feed := createFeed(t, q, alice, "go")
+		first := createPost(t, q, feed, "first", "", at(3, 8))
+		failed := errors.New("failed")
+
+		err := q.InTx(ctx, func(tx database.Store) error {
+			// a post already there does not break the transaction
+			if _, err := tx.CreatePost(ctx, database.CreatePostParams{ID: uuid.New(), Url: first.Url}); err != sql.ErrNoRows {
+				t.Errorf("CreatePost Mismatch wanted: %v , got: %v", sql.ErrNoRows, err)
+			}
+			createPost(t, tx, feed, "second", "", at(4, 8))
+			return tx.InTx(ctx, func(nested database.Store) error {
+				follow(t, nested, alice, feed)
+				return nil
+			})
+		})
+		if err != nil {
+			t.Fatalf("InTx Failed %v", err)
+		}
+
+		err = q.InTx(ctx, func(tx database.Store) error {
+			createPost(t, tx, feed, "third", "", at(5, 8))
+			if err := tx.MarkFeedFetched(ctx, database.MarkFeedFetchedParams{ID: feed.ID, UpdatedAt: valid(at(5, 9))}); err != nil {
+				t.Errorf("MarkFeedFetched Failed %v", err)
+			}
+			if err := tx.DeleteFeedFollow(ctx, database.DeleteFeedFollowParams{UserID: id(alice.ID), FeedID: id(feed.ID)}); err != nil {
+				t.Errorf("DeleteFeedFollow Failed %v", err)
+			}
+			return failed
+		})
+		if err != failed {
+			t.Errorf("InTx Mismatch wanted: %v , got: %v", failed, err)
+		}
+
+		// the first transaction is kept, nothing of the second is
+		posts, err := q.GetPostsForUser(ctx, database.GetPostsForUserParams{ID: alice.ID, Limit: 5})
+		if err != nil {
+			t.Fatalf("GetPostsForUser Failed %v", err)
+		}
+		if got, want := titles(posts, func(p database.Post) database.Post { return p }), []string{"second", "first"}; !reflect.DeepEqual(got, want) {
+			t.Errorf("GetPostsForUser Mismatch wanted: %v , got: %v", want, got)
+		}
+		feeds, err := q.GetFeeds(ctx)
+		if err != nil || len(feeds) != 1 || feeds[0].LastFetchedAt.Valid {
+			t.Errorf("GetFeeds Mismatch wanted: %v not fetched , got: %v %v", feed.Name.String, feeds, err)
+		}
+	})
+}
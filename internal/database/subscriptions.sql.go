@@ -0,0 +1,126 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: subscriptions.sql
+
+package database
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/google/uuid"
+)
+
+const feedWantsFullText = `-- name: FeedWantsFullText :one
+SELECT EXISTS (
+    SELECT 1 FROM feed_follows
+    WHERE feed_id = $1 AND full_text
+)::boolean AS wants_full_text
+`
+
+func (q *Queries) FeedWantsFullText(ctx context.Context, feedID uuid.NullUUID) (bool, error) {
+	row := q.db.QueryRowContext(ctx, feedWantsFullText, feedID)
+	var wants_full_text bool
+	err := row.Scan(&wants_full_text)
+	return wants_full_text, err
+}
+
+const getFeedFollow = `-- name: GetFeedFollow :one
+SELECT id, created_at, updated_at, user_id, feed_id, folder_id, display_name, hidden, notify, full_text, default_sort FROM feed_follows
+WHERE user_id = $1 AND feed_id = $2
+`
+
+type GetFeedFollowParams struct {
+	UserID uuid.NullUUID
+	FeedID uuid.NullUUID
+}
+
+func (q *Queries) GetFeedFollow(ctx context.Context, arg GetFeedFollowParams) (FeedFollow, error) {
+	row := q.db.QueryRowContext(ctx, getFeedFollow, arg.UserID, arg.FeedID)
+	var i FeedFollow
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.UserID,
+		&i.FeedID,
+		&i.FolderID,
+		&i.DisplayName,
+		&i.Hidden,
+		&i.Notify,
+		&i.FullText,
+		&i.DefaultSort,
+	)
+	return i, err
+}
+
+const getNotifyFollowers = `-- name: GetNotifyFollowers :many
+SELECT users.name AS user_name, COALESCE(feed_follows.display_name, feeds.name)::text AS feed_name
+FROM feed_follows
+INNER JOIN users ON feed_follows.user_id = users.id
+INNER JOIN feeds ON feed_follows.feed_id = feeds.id
+WHERE feed_follows.feed_id = $1 AND feed_follows.notify
+`
+
+type GetNotifyFollowersRow struct {
+	UserName string
+	FeedName string
+}
+
+func (q *Queries) GetNotifyFollowers(ctx context.Context, feedID uuid.NullUUID) ([]GetNotifyFollowersRow, error) {
+	rows, err := q.db.QueryContext(ctx, getNotifyFollowers, feedID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetNotifyFollowersRow
+	for rows.Next() {
+		var i GetNotifyFollowersRow
+		if err := rows.Scan(&i.UserName, &i.FeedName); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const updateFeedFollowSettings = `-- name: UpdateFeedFollowSettings :execrows
+UPDATE feed_follows
+SET display_name = $3, hidden = $4, notify = $5, full_text = $6, default_sort = $7, updated_at = $8
+WHERE user_id = $1 AND feed_id = $2
+`
+
+type UpdateFeedFollowSettingsParams struct {
+	UserID      uuid.NullUUID
+	FeedID      uuid.NullUUID
+	DisplayName sql.NullString
+	Hidden      bool
+	Notify      bool
+	FullText    bool
+	DefaultSort string
+	UpdatedAt   sql.NullTime
+}
+
+func (q *Queries) UpdateFeedFollowSettings(ctx context.Context, arg UpdateFeedFollowSettingsParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, updateFeedFollowSettings,
+		arg.UserID,
+		arg.FeedID,
+		arg.DisplayName,
+		arg.Hidden,
+		arg.Notify,
+		arg.FullText,
+		arg.DefaultSort,
+		arg.UpdatedAt,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
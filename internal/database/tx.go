@@ -0,0 +1,30 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+)
+
+// beginner is a DBTX that starts transactions, the *sql.DB of New.
+type beginner interface {
+	BeginTx(ctx context.Context, opts *sql.TxOptions) (*sql.Tx, error)
+}
+
+// InTx runs fn on q in a transaction, committed when fn returns nil and
+// rolled back otherwise. Queries already on a transaction run fn on it.
+func (q *Queries) InTx(ctx context.Context, fn func(tx Store) error) error {
+	db, ok := q.db.(beginner)
+	if !ok {
+		return fn(q)
+	}
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := fn(q.WithTx(tx)); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
@@ -0,0 +1,70 @@
+// Package dbtest opens the databases the tests of the data layer run
+// against: a new sqlite file for every test, and the postgres database of
+// GATOR_TEST_POSTGRES_URL when it is set. That database must be a scratch
+// one, its tables are emptied before every test.
+package dbtest
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/o0n1x/gator/internal/database"
+	"github.com/o0n1x/gator/internal/migrate"
+)
+
+// PostgresEnv names the variable holding the url of the postgres test database.
+const PostgresEnv = "GATOR_TEST_POSTGRES_URL"
+
+// Database is a database to run a test against, migrated to the latest
+// schema and empty.
+type Database struct {
+	Name string
+	DB   *sql.DB
+}
+
+// Open returns the databases to run a test against, they are closed when
+// the test ends.
+func Open(t *testing.T) []Database {
+	t.Helper()
+	databases := []Database{{Name: "sqlite", DB: open(t, "sqlite://"+filepath.Join(t.TempDir(), "gator.db"))}}
+	if url := os.Getenv(PostgresEnv); url != "" {
+		db := open(t, url)
+		if _, err := db.Exec(`TRUNCATE users, feeds, posts CASCADE`); err != nil {
+			t.Fatalf("emptying %v Failed %v", PostgresEnv, err)
+		}
+		databases = append(databases, Database{Name: "postgres", DB: db})
+	}
+	return databases
+}
+
+// OpenEmpty opens a new sqlite database without any table.
+func OpenEmpty(t *testing.T) *sql.DB {
+	t.Helper()
+	db, err := database.Open("sqlite://" + filepath.Join(t.TempDir(), "gator.db"))
+	if err != nil {
+		t.Fatalf("database.Open Failed %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func open(t *testing.T, url string) *sql.DB {
+	t.Helper()
+	db, err := database.Open(url)
+	if err != nil {
+		t.Fatalf("database.Open Failed %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	migrations, err := migrate.Embedded(database.DialectOf(db))
+	if err != nil {
+		t.Fatalf("migrate.Embedded Failed %v", err)
+	}
+	m := &migrate.Migrator{DB: db, Migrations: migrations}
+	if _, err := m.Up(context.Background(), 0); err != nil {
+		t.Fatalf("migrate Failed %v", err)
+	}
+	return db
+}
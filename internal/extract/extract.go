@@ -0,0 +1,230 @@
+package extract
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// maxArticleSize caps how much of an article page is read.
+const maxArticleSize = 5 << 20
+
+var (
+	unlikelyTags = map[atom.Atom]bool{
+		atom.Script: true, atom.Style: true, atom.Noscript: true, atom.Nav: true,
+		atom.Aside: true, atom.Footer: true, atom.Header: true, atom.Form: true,
+		atom.Iframe: true, atom.Svg: true, atom.Button: true, atom.Select: true,
+	}
+	blockTags = map[atom.Atom]bool{
+		atom.P: true, atom.Pre: true, atom.Blockquote: true, atom.Li: true,
+		atom.H1: true, atom.H2: true, atom.H3: true, atom.H4: true, atom.H5: true, atom.H6: true,
+	}
+	negativeNames = regexp.MustCompile(`(?i)comment|sidebar|footer|footnote|nav|menu|share|social|related|promo|advert|sponsor|cookie|banner|subscribe|newsletter|popup|masthead`)
+	positiveNames = regexp.MustCompile(`(?i)article|body|content|entry|main|page|post|text|blog|story`)
+	spaces        = regexp.MustCompile(`\s+`)
+)
+
+// FetchArticle downloads the page at articleURL and returns its main text content.
+func FetchArticle(ctx context.Context, articleURL string) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", articleURL, nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("User-Agent", "gator")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 400 {
+		return "", fmt.Errorf("unexpected status %v", resp.Status)
+	}
+	return Article(io.LimitReader(resp.Body, maxArticleSize))
+}
+
+// Article returns the main text of an html page, paragraphs separated by a blank line.
+// It scores paragraphs readability style and keeps the best scoring container.
+func Article(r io.Reader) (string, error) {
+	doc, err := html.Parse(r)
+	if err != nil {
+		return "", err
+	}
+	removeUnlikely(doc)
+
+	scores := map[*html.Node]float64{}
+	var candidates []*html.Node
+	addScore := func(n *html.Node, score float64) {
+		if n == nil || n.Type != html.ElementNode {
+			return
+		}
+		if _, ok := scores[n]; !ok {
+			scores[n] = baseScore(n)
+			candidates = append(candidates, n)
+		}
+		scores[n] += score
+	}
+
+	walk(doc, func(n *html.Node) {
+		if n.DataAtom != atom.P && n.DataAtom != atom.Pre && n.DataAtom != atom.Td {
+			return
+		}
+		text := textOf(n)
+		if len(text) < 25 {
+			return
+		}
+		score := 1 + float64(strings.Count(text, ",")) + min(float64(len(text))/100, 3)
+		addScore(n.Parent, score)
+		if n.Parent != nil {
+			addScore(n.Parent.Parent, score/2)
+		}
+	})
+
+	var top *html.Node
+	topScore := 0.0
+	for _, n := range candidates {
+		score := scores[n] * (1 - linkDensity(n))
+		if top == nil || score > topScore {
+			top, topScore = n, score
+		}
+	}
+	if top == nil {
+		top = findBody(doc)
+		if top == nil {
+			return "", errors.New("no content found")
+		}
+	}
+
+	var paragraphs []string
+	walk(top, func(n *html.Node) {
+		if !blockTags[n.DataAtom] || hasBlockAncestor(n, top) {
+			return
+		}
+		if text := textOf(n); text != "" {
+			paragraphs = append(paragraphs, text)
+		}
+	})
+	if len(paragraphs) == 0 {
+		if text := textOf(top); text != "" {
+			paragraphs = append(paragraphs, text)
+		}
+	}
+	if len(paragraphs) == 0 {
+		return "", errors.New("no content found")
+	}
+	return strings.Join(paragraphs, "\n\n"), nil
+}
+
+func removeUnlikely(n *html.Node) {
+	for c := n.FirstChild; c != nil; {
+		next := c.NextSibling
+		if c.Type == html.CommentNode || (c.Type == html.ElementNode && isUnlikely(c)) {
+			n.RemoveChild(c)
+		} else {
+			removeUnlikely(c)
+		}
+		c = next
+	}
+}
+
+func isUnlikely(n *html.Node) bool {
+	if unlikelyTags[n.DataAtom] {
+		return true
+	}
+	if n.DataAtom == atom.Body || n.DataAtom == atom.Html || n.DataAtom == atom.Article || n.DataAtom == atom.Main {
+		return false
+	}
+	names := attr(n, "class") + " " + attr(n, "id")
+	return negativeNames.MatchString(names) && !positiveNames.MatchString(names)
+}
+
+func baseScore(n *html.Node) float64 {
+	score := 0.0
+	switch n.DataAtom {
+	case atom.Article, atom.Main:
+		score += 10
+	case atom.Div:
+		score += 5
+	case atom.Pre, atom.Td, atom.Blockquote:
+		score += 3
+	case atom.Ol, atom.Ul, atom.Form:
+		score -= 3
+	case atom.H1, atom.H2, atom.H3, atom.H4, atom.H5, atom.H6, atom.Th:
+		score -= 5
+	}
+	names := attr(n, "class") + " " + attr(n, "id")
+	if positiveNames.MatchString(names) {
+		score += 25
+	}
+	if negativeNames.MatchString(names) {
+		score -= 25
+	}
+	return score
+}
+
+func linkDensity(n *html.Node) float64 {
+	total := len(textOf(n))
+	if total == 0 {
+		return 0
+	}
+	links := 0
+	walk(n, func(c *html.Node) {
+		if c.DataAtom == atom.A {
+			links += len(textOf(c))
+		}
+	})
+	return float64(links) / float64(total)
+}
+
+func hasBlockAncestor(n, root *html.Node) bool {
+	for p := n.Parent; p != nil && p != root; p = p.Parent {
+		if blockTags[p.DataAtom] {
+			return true
+		}
+	}
+	return false
+}
+
+func findBody(n *html.Node) *html.Node {
+	var body *html.Node
+	walk(n, func(c *html.Node) {
+		if body == nil && c.DataAtom == atom.Body {
+			body = c
+		}
+	})
+	return body
+}
+
+func textOf(n *html.Node) string {
+	var b strings.Builder
+	walk(n, func(c *html.Node) {
+		if c.Type == html.TextNode {
+			b.WriteString(c.Data)
+			b.WriteString(" ")
+		}
+	})
+	return strings.TrimSpace(spaces.ReplaceAllString(b.String(), " "))
+}
+
+func attr(n *html.Node, key string) string {
+	for _, a := range n.Attr {
+		if a.Key == key {
+			return a.Val
+		}
+	}
+	return ""
+}
+
+func walk(n *html.Node, f func(*html.Node)) {
+	f(n)
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		walk(c, f)
+	}
+}
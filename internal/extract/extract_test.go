@@ -0,0 +1,83 @@
+package extract
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestArticle(t *testing.T) {
+	cases := map[string]struct {
+		fixture string
+		want    []string
+		notWant []string
+	}{
+		"blog post": {
+			fixture: "blog_post.html",
+			want: []string{
+				"For years our build tooling was a pile of shell scripts",
+				"The best migration is the one your users never notice",
+				"Build times dropped from eleven minutes to under four",
+			},
+			notWant: []string{"Archive", "Related posts", "Great post", "Copyright", "window.analytics"},
+		},
+		"news article": {
+			fixture: "news_article.html",
+			want: []string{
+				"The city council voted seven to two",
+				"Phase one covers four miles of road.",
+				"Opponents argued the plan would reduce parking",
+			},
+			notWant: []string{"Subscribe today", "Share on social media", "Sports"},
+		},
+		"no paragraphs": {
+			fixture: "no_paragraphs.html",
+			want:    []string{"Just a short status line."},
+			notWant: []string{"Home"},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			f, err := os.Open(filepath.Join("testdata", tc.fixture))
+			if err != nil {
+				t.Fatalf("failed to open fixture: %v", err)
+			}
+			defer f.Close()
+
+			content, err := Article(f)
+			if err != nil {
+				t.Errorf("Article Failed %v", err)
+				return
+			}
+			for _, want := range tc.want {
+				if !strings.Contains(content, want) {
+					t.Errorf("content is missing %q, got:\n%v", want, content)
+				}
+			}
+			for _, notWant := range tc.notWant {
+				if strings.Contains(content, notWant) {
+					t.Errorf("content should not contain %q, got:\n%v", notWant, content)
+				}
+			}
+		})
+	}
+}
+
+func TestArticleParagraphs(t *testing.T) {
+	f, err := os.Open(filepath.Join("testdata", "blog_post.html"))
+	if err != nil {
+		t.Fatalf("failed to open fixture: %v", err)
+	}
+	defer f.Close()
+
+	content, err := Article(f)
+	if err != nil {
+		t.Fatalf("Article Failed %v", err)
+	}
+	paragraphs := strings.Split(content, "\n\n")
+	if len(paragraphs) != 5 {
+		t.Errorf("wanted 5 paragraphs (title, 3 paragraphs, quote), got %v:\n%v", len(paragraphs), content)
+	}
+}
@@ -0,0 +1,388 @@
+// Package migrate applies the goose migrations embedded in gator, the ones
+// of sql/schema to postgres and of sql/sqlite/schema to sqlite. The applied
+// versions are kept in the goose_db_version table the way goose keeps them,
+// so databases set up with goose keep working.
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/o0n1x/gator/internal/database"
+	"github.com/o0n1x/gator/internal/urlnorm"
+	"github.com/o0n1x/gator/sql/schema"
+	sqliteschema "github.com/o0n1x/gator/sql/sqlite/schema"
+)
+
+const versionTable = "goose_db_version"
+
+// goStep marks a step written in go, -- +gator go <name>. It runs between the
+// sql before and after it, in the same transaction. Goose reads the line as a
+// comment.
+const goStep = "-- +gator go "
+
+// Migration is one NNN_name.sql file.
+type Migration struct {
+	Version int64
+	Name    string
+	Up      string
+	Down    string
+	// NoTransaction migrations are marked -- +goose NO TRANSACTION and run
+	// outside a transaction.
+	NoTransaction bool
+}
+
+// Status is a migration and when it was applied.
+type Status struct {
+	Migration
+	Applied   bool
+	AppliedAt time.Time
+}
+
+// Load reads the migrations of fsys, ordered by version.
+func Load(fsys fs.FS) ([]Migration, error) {
+	names, err := fs.Glob(fsys, "*.sql")
+	if err != nil {
+		return nil, err
+	}
+	var migrations []Migration
+	seen := map[int64]string{}
+	for _, name := range names {
+		data, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			return nil, err
+		}
+		migration, err := parse(name, string(data))
+		if err != nil {
+			return nil, err
+		}
+		if other, ok := seen[migration.Version]; ok {
+			return nil, fmt.Errorf("migrations %v and %v have the same version", other, name)
+		}
+		seen[migration.Version] = name
+		migrations = append(migrations, migration)
+	}
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+	return migrations, nil
+}
+
+type loaded struct {
+	once       sync.Once
+	migrations []Migration
+	err        error
+}
+
+var embedded = map[database.Dialect]*loaded{
+	database.Postgres: {},
+	database.SQLite:   {},
+}
+
+// Embedded returns the migrations built into gator for a kind of database.
+func Embedded(dialect database.Dialect) ([]Migration, error) {
+	l, ok := embedded[dialect]
+	if !ok {
+		return nil, fmt.Errorf("no migrations for %v databases", dialect)
+	}
+	l.once.Do(func() {
+		fsys := schema.FS
+		if dialect == database.SQLite {
+			fsys = sqliteschema.FS
+		}
+		l.migrations, l.err = Load(fsys)
+	})
+	return l.migrations, l.err
+}
+
+// parse splits a goose migration into its up and down sql.
+func parse(name, content string) (Migration, error) {
+	number, _, ok := strings.Cut(path.Base(name), "_")
+	version, err := strconv.ParseInt(number, 10, 64)
+	if !ok || err != nil || version < 1 {
+		return Migration{}, fmt.Errorf("migration %v does not start with a version number like 001_", name)
+	}
+	migration := Migration{Version: version, Name: strings.TrimSuffix(path.Base(name), ".sql")}
+
+	var up, down strings.Builder
+	var section *strings.Builder
+	for _, line := range strings.SplitAfter(content, "\n") {
+		if step, ok := strings.CutPrefix(strings.TrimSpace(line), goStep); ok {
+			if _, ok := steps[strings.TrimSpace(step)]; !ok {
+				return Migration{}, fmt.Errorf("migration %v has an unknown go step %v", name, step)
+			}
+		}
+		annotation, isAnnotation := strings.CutPrefix(strings.TrimSpace(line), "-- +goose ")
+		if !isAnnotation {
+			if section != nil {
+				section.WriteString(line)
+			}
+			continue
+		}
+		switch strings.ToUpper(strings.TrimSpace(annotation)) {
+		case "UP":
+			section = &up
+		case "DOWN":
+			section = &down
+		case "NO TRANSACTION":
+			migration.NoTransaction = true
+		case "STATEMENTBEGIN", "STATEMENTEND":
+			// the sections are run whole, statements need no marking
+		default:
+			return Migration{}, fmt.Errorf("migration %v has an unknown annotation %v", name, annotation)
+		}
+	}
+	if section == nil {
+		return Migration{}, fmt.Errorf("migration %v has no -- +goose Up section", name)
+	}
+	migration.Up, migration.Down = strings.TrimSpace(up.String()), strings.TrimSpace(down.String())
+	return migration, nil
+}
+
+// Latest is the version of the last migration, 0 when there is none.
+func Latest(migrations []Migration) int64 {
+	if len(migrations) == 0 {
+		return 0
+	}
+	return migrations[len(migrations)-1].Version
+}
+
+// Pending lists the migrations that are not applied, up to and including version to.
+func Pending(migrations []Migration, applied map[int64]time.Time, to int64) []Migration {
+	var pending []Migration
+	for _, migration := range migrations {
+		if _, ok := applied[migration.Version]; !ok && migration.Version <= to {
+			pending = append(pending, migration)
+		}
+	}
+	return pending
+}
+
+// queryer is a connection or a transaction.
+type queryer interface {
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+}
+
+// Applied returns the applied versions and when they were applied, none when
+// the version table does not exist yet.
+func Applied(ctx context.Context, db queryer, dialect database.Dialect) (map[int64]time.Time, error) {
+	exists := `SELECT to_regclass($1) IS NOT NULL`
+	if dialect == database.SQLite {
+		exists = `SELECT EXISTS (SELECT 1 FROM sqlite_master WHERE type = 'table' AND name = $1)`
+	}
+	var ok bool
+	if err := db.QueryRowContext(ctx, exists, versionTable).Scan(&ok); err != nil {
+		return nil, fmt.Errorf("error reading the schema version: %v", err)
+	}
+	applied := map[int64]time.Time{}
+	if !ok {
+		return applied, nil
+	}
+	rows, err := db.QueryContext(ctx, `SELECT version_id, is_applied, tstamp FROM `+versionTable+` ORDER BY id DESC`)
+	if err != nil {
+		return nil, fmt.Errorf("error reading the schema version: %v", err)
+	}
+	defer rows.Close()
+	// like goose, the newest row of a version decides whether it is applied
+	seen := map[int64]bool{}
+	for rows.Next() {
+		var version int64
+		var isApplied bool
+		var at sql.NullTime
+		if err := rows.Scan(&version, &isApplied, &at); err != nil {
+			return nil, err
+		}
+		if seen[version] {
+			continue
+		}
+		seen[version] = true
+		if isApplied && version > 0 {
+			applied[version] = at.Time
+		}
+	}
+	return applied, rows.Err()
+}
+
+// Current is the highest applied version, 0 for an empty database.
+func Current(ctx context.Context, db queryer, dialect database.Dialect) (int64, error) {
+	applied, err := Applied(ctx, db, dialect)
+	if err != nil {
+		return 0, err
+	}
+	var current int64
+	for version := range applied {
+		current = max(current, version)
+	}
+	return current, nil
+}
+
+// Migrator applies and rolls back migrations on a database.
+type Migrator struct {
+	DB         *sql.DB
+	Migrations []Migration
+	// URLs keys the feed urls in the go steps, the default normalizer when nil.
+	URLs *urlnorm.Normalizer
+}
+
+// Status lists every migration and whether it is applied.
+func (m *Migrator) Status(ctx context.Context) ([]Status, error) {
+	applied, err := Applied(ctx, m.DB, database.DialectOf(m.DB))
+	if err != nil {
+		return nil, err
+	}
+	var statuses []Status
+	for _, migration := range m.Migrations {
+		at, ok := applied[migration.Version]
+		statuses = append(statuses, Status{Migration: migration, Applied: ok, AppliedAt: at})
+	}
+	return statuses, nil
+}
+
+// Up applies the pending migrations up to version to, every one when to is 0.
+// It stops at the first migration that fails, the ones before it stay applied.
+func (m *Migrator) Up(ctx context.Context, to int64) ([]Migration, error) {
+	if err := m.ensureTable(ctx); err != nil {
+		return nil, err
+	}
+	applied, err := Applied(ctx, m.DB, database.DialectOf(m.DB))
+	if err != nil {
+		return nil, err
+	}
+	if to == 0 {
+		to = Latest(m.Migrations)
+	}
+	var done []Migration
+	for _, migration := range Pending(m.Migrations, applied, to) {
+		err := m.run(ctx, migration.Version, migration.Up, migration.NoTransaction,
+			`INSERT INTO `+versionTable+` (version_id, is_applied) VALUES ($1, true)`)
+		if err != nil {
+			return done, fmt.Errorf("error applying %v: %v", migration.Name, err)
+		}
+		done = append(done, migration)
+	}
+	return done, nil
+}
+
+// Down rolls back the last applied migration.
+func (m *Migrator) Down(ctx context.Context) (Migration, error) {
+	current, err := Current(ctx, m.DB, database.DialectOf(m.DB))
+	if err != nil {
+		return Migration{}, err
+	}
+	if current == 0 {
+		return Migration{}, fmt.Errorf("no migration is applied")
+	}
+	for _, migration := range m.Migrations {
+		if migration.Version != current {
+			continue
+		}
+		err := m.run(ctx, migration.Version, migration.Down, migration.NoTransaction,
+			`DELETE FROM `+versionTable+` WHERE version_id = $1`)
+		if err != nil {
+			return Migration{}, fmt.Errorf("error rolling back %v: %v", migration.Name, err)
+		}
+		return migration, nil
+	}
+	return Migration{}, fmt.Errorf("the database is at version %v which this gator does not know, update gator", current)
+}
+
+// Redo rolls back the last applied migration and applies it again.
+func (m *Migrator) Redo(ctx context.Context) (Migration, error) {
+	migration, err := m.Down(ctx)
+	if err != nil {
+		return Migration{}, err
+	}
+	if _, err := m.Up(ctx, migration.Version); err != nil {
+		return Migration{}, err
+	}
+	return migration, nil
+}
+
+func (m *Migrator) ensureTable(ctx context.Context) error {
+	id := "SERIAL PRIMARY KEY"
+	if database.DialectOf(m.DB) == database.SQLite {
+		id = "INTEGER PRIMARY KEY AUTOINCREMENT"
+	}
+	_, err := m.DB.ExecContext(ctx, `
+CREATE TABLE IF NOT EXISTS `+versionTable+` (
+    id `+id+`,
+    version_id BIGINT NOT NULL,
+    is_applied BOOLEAN NOT NULL,
+    tstamp TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+)`)
+	if err != nil {
+		return fmt.Errorf("error creating %v: %v", versionTable, err)
+	}
+	// goose starts the table with version 0
+	_, err = m.DB.ExecContext(ctx, `
+INSERT INTO `+versionTable+` (version_id, is_applied)
+SELECT 0, true WHERE NOT EXISTS (SELECT 1 FROM `+versionTable+`)`)
+	return err
+}
+
+// run runs the sql of a migration and records it with record, in one
+// transaction unless noTransaction is set.
+func (m *Migrator) run(ctx context.Context, version int64, statements string, noTransaction bool, record string) error {
+	if noTransaction {
+		if err := m.exec(ctx, m.DB, statements); err != nil {
+			return err
+		}
+		_, err := m.DB.ExecContext(ctx, record, version)
+		return err
+	}
+
+	tx, err := m.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	if err := m.exec(ctx, tx, statements); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, record, version); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// exec runs statements on db, and the go steps they mark where they mark them.
+func (m *Migrator) exec(ctx context.Context, db queryer, statements string) error {
+	var chunk strings.Builder
+	flush := func() error {
+		statements := chunk.String()
+		chunk.Reset()
+		if strings.TrimSpace(statements) == "" {
+			return nil
+		}
+		_, err := db.ExecContext(ctx, statements)
+		return err
+	}
+	for _, line := range strings.SplitAfter(statements, "\n") {
+		name, ok := strings.CutPrefix(strings.TrimSpace(line), goStep)
+		if !ok {
+			chunk.WriteString(line)
+			continue
+		}
+		if err := flush(); err != nil {
+			return err
+		}
+		step, ok := steps[strings.TrimSpace(name)]
+		if !ok {
+			return fmt.Errorf("unknown go step %v", name)
+		}
+		if err := step(ctx, m, db); err != nil {
+			return err
+		}
+	}
+	return flush()
+}
@@ -0,0 +1,253 @@
+package migrate
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/o0n1x/gator/internal/database"
+	"github.com/o0n1x/gator/internal/urlnorm"
+)
+
+func TestParse(t *testing.T) {
+	cases := map[string]struct {
+		name    string
+		content string
+		want    Migration
+		err     bool
+	}{
+		"up and down": {
+			name:    "003_feeds.sql",
+			content: "-- +goose Up\nCREATE TABLE feeds(id UUID);\n\n-- +goose Down\nDROP TABLE feeds;",
+			want:    Migration{Version: 3, Name: "003_feeds", Up: "CREATE TABLE feeds(id UUID);", Down: "DROP TABLE feeds;"},
+		},
+		"statement markers and no transaction": {
+			name:    "010_index.sql",
+			content: "-- +goose NO TRANSACTION\n-- +goose Up\n-- +goose StatementBegin\nCREATE INDEX CONCURRENTLY i ON posts (url);\n-- +goose StatementEnd\n",
+			want:    Migration{Version: 10, Name: "010_index", Up: "CREATE INDEX CONCURRENTLY i ON posts (url);", NoTransaction: true},
+		},
+		"comments kept": {
+			name:    "2_a.sql",
+			content: "-- header\n-- +goose Up\n-- posts outlive feeds\nSELECT 1;\n",
+			want:    Migration{Version: 2, Name: "2_a", Up: "-- posts outlive feeds\nSELECT 1;"},
+		},
+		"go step": {
+			name:    "006_keys.sql",
+			content: "-- +goose Up\nALTER TABLE feeds ADD COLUMN url_key TEXT;\n-- +gator go backfill_url_keys\n",
+			want:    Migration{Version: 6, Name: "006_keys", Up: "ALTER TABLE feeds ADD COLUMN url_key TEXT;\n-- +gator go backfill_url_keys"},
+		},
+		"unknown go step":    {name: "006_keys.sql", content: "-- +goose Up\n-- +gator go fill_everything\n", err: true},
+		"no version":         {name: "feeds.sql", content: "-- +goose Up\nSELECT 1;", err: true},
+		"version zero":       {name: "000_init.sql", content: "-- +goose Up\nSELECT 1;", err: true},
+		"no up section":      {name: "004_a.sql", content: "SELECT 1;", err: true},
+		"unknown annotation": {name: "004_a.sql", content: "-- +goose Sideways\n-- +goose Up\nSELECT 1;", err: true},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := parse(tc.name, tc.content)
+			if tc.err {
+				if err == nil {
+					t.Errorf("parse Failed, wanted an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parse Failed %v", err)
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("parse Mismatch wanted: %+v , got: %+v", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestLoad(t *testing.T) {
+	fsys := fstest.MapFS{
+		"010_b.sql": {Data: []byte("-- +goose Up\nSELECT 10;")},
+		"002_a.sql": {Data: []byte("-- +goose Up\nSELECT 2;")},
+		"schema.go": {Data: []byte("package schema")},
+	}
+	migrations, err := Load(fsys)
+	if err != nil {
+		t.Fatalf("Load Failed %v", err)
+	}
+	if len(migrations) != 2 || migrations[0].Version != 2 || migrations[1].Version != 10 || Latest(migrations) != 10 {
+		t.Errorf("Load Mismatch wanted versions 2 and 10, got: %+v", migrations)
+	}
+
+	fsys["002_again.sql"] = &fstest.MapFile{Data: []byte("-- +goose Up\nSELECT 2;")}
+	if _, err := Load(fsys); err == nil {
+		t.Errorf("Load Failed, wanted an error for two migrations with version 2")
+	}
+}
+
+func TestEmbedded(t *testing.T) {
+	postgres, err := Embedded(database.Postgres)
+	if err != nil {
+		t.Fatalf("Embedded Failed %v", err)
+	}
+	sqlite, err := Embedded(database.SQLite)
+	if err != nil {
+		t.Fatalf("Embedded Failed %v", err)
+	}
+	if len(postgres) == 0 {
+		t.Fatalf("Embedded Failed, no migrations")
+	}
+	if len(sqlite) != len(postgres) {
+		t.Fatalf("sqlite migrations Mismatch wanted: %v , got: %v", len(postgres), len(sqlite))
+	}
+	for i, migration := range postgres {
+		if migration.Version != int64(i+1) {
+			t.Errorf("version Mismatch wanted: %v , got: %v (%v)", i+1, migration.Version, migration.Name)
+		}
+		if migration.Up == "" || migration.Down == "" {
+			t.Errorf("migration %v Failed, it needs an up and a down section", migration.Name)
+		}
+		// the versions mean the same schema on both databases
+		if sqlite[i].Name != migration.Name || sqlite[i].Up == "" || sqlite[i].Down == "" {
+			t.Errorf("sqlite migration Mismatch wanted: %v with an up and a down section, got: %+v", migration.Name, sqlite[i])
+		}
+	}
+}
+
+func TestPending(t *testing.T) {
+	migrations := []Migration{{Version: 1}, {Version: 2}, {Version: 3}, {Version: 4}}
+	applied := map[int64]time.Time{1: {}, 3: {}}
+	cases := map[string]struct {
+		to   int64
+		want []int64
+	}{
+		"all":      {to: 4, want: []int64{2, 4}},
+		"up to 3":  {to: 3, want: []int64{2}},
+		"up to 1":  {to: 1},
+		"past end": {to: 9, want: []int64{2, 4}},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var got []int64
+			for _, migration := range Pending(migrations, applied, tc.to) {
+				got = append(got, migration.Version)
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("Pending Mismatch wanted: %v , got: %v", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestSQLiteUpAndDown(t *testing.T) {
+	ctx := context.Background()
+	db, err := database.Open("sqlite://" + filepath.Join(t.TempDir(), "gator.db"))
+	if err != nil {
+		t.Fatalf("database.Open Failed %v", err)
+	}
+	defer db.Close()
+	migrations, err := Embedded(database.SQLite)
+	if err != nil {
+		t.Fatalf("Embedded Failed %v", err)
+	}
+	m := &Migrator{DB: db, Migrations: migrations}
+
+	// every migration applies, rolls back and applies again
+	for round := 0; round < 2; round++ {
+		done, err := m.Up(ctx, 0)
+		if err != nil {
+			t.Fatalf("Up Failed %v", err)
+		}
+		if round == 0 && len(done) != len(migrations) {
+			t.Errorf("Up Mismatch wanted: %v migrations , got: %v", len(migrations), len(done))
+		}
+		if current, err := Current(ctx, db, database.SQLite); err != nil || current != Latest(migrations) {
+			t.Fatalf("Current Mismatch wanted: %v , got: %v %v", Latest(migrations), current, err)
+		}
+		if round == 1 {
+			break
+		}
+		for i := len(migrations) - 1; i >= 0; i-- {
+			migration, err := m.Down(ctx)
+			if err != nil {
+				t.Fatalf("Down Failed %v", err)
+			}
+			if migration.Version != migrations[i].Version {
+				t.Errorf("Down Mismatch wanted: %v , got: %v", migrations[i].Name, migration.Name)
+			}
+		}
+		var tables int
+		if err := db.QueryRow(`SELECT count(*) FROM sqlite_master WHERE type = 'table' AND name NOT IN ('goose_db_version', 'sqlite_sequence')`).Scan(&tables); err != nil || tables != 0 {
+			t.Errorf("tables after Down Mismatch wanted: 0 , got: %v %v", tables, err)
+		}
+	}
+}
+
+func TestBackfillURLKeys(t *testing.T) {
+	ctx := context.Background()
+	cases := map[string]struct {
+		// url and the key the sql of 006 gave it
+		feeds [][2]string
+		err   bool
+	}{
+		"keyed again": {feeds: [][2]string{
+			{"HTTPS://Example.com:443/feed/?utm_source=x&b=2&a=1", "example.com/feed?utm_source=x&b=2&a=1"},
+			{"https://example.com/b", "example.com/a"},
+			{"https://example.com/a", "example.com/b"},
+			{"file:///tmp/feed.xml", "file:///tmp/feed.xml"},
+		}},
+		"same url twice": {feeds: [][2]string{
+			{"https://example.com/feed", "example.com/feed"},
+			{"https://example.com/feed/?utm_source=x", "example.com/feed?utm_source=x"},
+		}, err: true},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			db, err := database.Open("sqlite://" + filepath.Join(t.TempDir(), "gator.db"))
+			if err != nil {
+				t.Fatalf("database.Open Failed %v", err)
+			}
+			defer db.Close()
+			migrations, err := Embedded(database.SQLite)
+			if err != nil {
+				t.Fatalf("Embedded Failed %v", err)
+			}
+			m := &Migrator{DB: db, Migrations: migrations}
+			if _, err := m.Up(ctx, 19); err != nil {
+				t.Fatalf("Up Failed %v", err)
+			}
+			for i, feed := range tc.feeds {
+				_, err := db.Exec(`INSERT INTO feeds (id, name, url, url_key) VALUES ($1, $2, $3, $4)`,
+					fmt.Sprintf("00000000-0000-0000-0000-00000000000%v", i), fmt.Sprintf("feed %v", i), feed[0], feed[1])
+				if err != nil {
+					t.Fatalf("insert Failed %v", err)
+				}
+			}
+
+			_, err = m.Up(ctx, 0)
+			if tc.err {
+				if err == nil || !strings.Contains(err.Error(), "DELETE FROM feeds WHERE id = '00000000-0000-0000-0000-000000000001'") {
+					t.Errorf("Up Mismatch wanted an error naming the feed to delete, got: %v", err)
+				}
+				if current, err := Current(ctx, db, database.SQLite); err != nil || current != 19 {
+					t.Errorf("Current Mismatch wanted: 19 , got: %v %v", current, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Up Failed %v", err)
+			}
+			for _, feed := range tc.feeds {
+				want, err := urlnorm.New(nil, false).Key(feed[0])
+				if err != nil {
+					t.Fatalf("Key Failed %v", err)
+				}
+				var got string
+				if err := db.QueryRow(`SELECT url_key FROM feeds WHERE url = $1`, feed[0]).Scan(&got); err != nil || got != want {
+					t.Errorf("url_key of %v Mismatch wanted: %v , got: %v %v", feed[0], want, got, err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,69 @@
+package migrate
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/o0n1x/gator/internal/urlnorm"
+)
+
+// steps are the go steps a migration can mark, by name.
+var steps = map[string]func(ctx context.Context, m *Migrator, db queryer) error{
+	"backfill_url_keys": backfillURLKeys,
+}
+
+// backfillURLKeys sets the url_key of every feed to the key urlnorm gives its
+// url, the key gator looks feeds up by. Feeds whose url does not parse keep no
+// key. Two feeds with the same key can not both stay, it fails naming them so
+// one of them can be deleted before migrating again.
+func backfillURLKeys(ctx context.Context, m *Migrator, db queryer) error {
+	urls := m.URLs
+	if urls == nil {
+		urls = urlnorm.New(nil, false)
+	}
+	rows, err := db.QueryContext(ctx, `SELECT id, name, url FROM feeds WHERE url IS NOT NULL ORDER BY created_at, id`)
+	if err != nil {
+		return fmt.Errorf("error reading the feeds: %v", err)
+	}
+	defer rows.Close()
+	type feed struct{ id, name, url, key string }
+	var feeds []feed
+	byKey := map[string]feed{}
+	for rows.Next() {
+		var f feed
+		var name *string
+		if err := rows.Scan(&f.id, &name, &f.url); err != nil {
+			return fmt.Errorf("error reading the feeds: %v", err)
+		}
+		if name != nil {
+			f.name = *name
+		}
+		key, err := urls.Key(f.url)
+		if err != nil {
+			continue
+		}
+		f.key = key
+		if other, ok := byKey[key]; ok {
+			return fmt.Errorf("feeds %q (%v, id %v) and %q (%v, id %v) are the same url %v, "+
+				"delete the one to drop with DELETE FROM feeds WHERE id = '%v' and migrate again",
+				other.name, other.url, other.id, f.name, f.url, f.id, key, f.id)
+		}
+		byKey[key] = f
+		feeds = append(feeds, f)
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("error reading the feeds: %v", err)
+	}
+	rows.Close()
+
+	// the old keys go first, a new key may be the old key of another feed
+	if _, err := db.ExecContext(ctx, `UPDATE feeds SET url_key = NULL`); err != nil {
+		return fmt.Errorf("error clearing the url keys: %v", err)
+	}
+	for _, f := range feeds {
+		if _, err := db.ExecContext(ctx, `UPDATE feeds SET url_key = $1 WHERE id = $2`, f.key, f.id); err != nil {
+			return fmt.Errorf("error setting the url key of %v: %v", f.url, err)
+		}
+	}
+	return nil
+}
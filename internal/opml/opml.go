@@ -0,0 +1,221 @@
+// Package opml reads and writes OPML subscription lists, the format feed
+// readers use to move subscriptions between each other.
+package opml
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"golang.org/x/net/html/charset"
+)
+
+// Document is an OPML 1.0 or 2.0 document.
+type Document struct {
+	Version string
+	Title   string
+	// Created is the dateCreated of the head, zero when it is missing.
+	Created  time.Time
+	Outlines []Outline
+}
+
+// Outline is a feed when XMLURL is set, otherwise a folder of outlines.
+type Outline struct {
+	Text     string
+	Title    string
+	Type     string
+	XMLURL   string
+	HTMLURL  string
+	Outlines []Outline
+}
+
+// Name is the title of the outline, its text when it has no title.
+func (o Outline) Name() string {
+	if o.Title != "" {
+		return o.Title
+	}
+	return o.Text
+}
+
+// Feed is a feed outline with the folder it was found in.
+type Feed struct {
+	Title   string
+	XMLURL  string
+	HTMLURL string
+	// Folder is the path of the folder outlines above the feed joined with
+	// FolderSeparator, "" at the top level.
+	Folder string
+}
+
+// FolderSeparator joins the names of nested folders.
+const FolderSeparator = "/"
+
+// Feeds lists the feed outlines of the document in order.
+func (d *Document) Feeds() []Feed {
+	var feeds []Feed
+	var walk func(outlines []Outline, folder string)
+	walk = func(outlines []Outline, folder string) {
+		for _, o := range outlines {
+			if o.XMLURL != "" {
+				feeds = append(feeds, Feed{Title: o.Name(), XMLURL: o.XMLURL, HTMLURL: o.HTMLURL, Folder: folder})
+			}
+			if len(o.Outlines) > 0 {
+				path := o.Name()
+				if folder != "" {
+					path = folder + FolderSeparator + path
+				}
+				walk(o.Outlines, path)
+			}
+		}
+	}
+	walk(d.Outlines, "")
+	return feeds
+}
+
+// FromFeeds builds an OPML 2.0 document of feeds, nesting them in folder
+// outlines by their Folder path.
+func FromFeeds(title string, created time.Time, feeds []Feed) *Document {
+	doc := &Document{Version: "2.0", Title: title, Created: created}
+	for _, feed := range feeds {
+		outlines := &doc.Outlines
+		if feed.Folder != "" {
+			for _, name := range strings.Split(feed.Folder, FolderSeparator) {
+				outlines = folderOutlines(outlines, name)
+			}
+		}
+		*outlines = append(*outlines, Outline{
+			Text:    feed.Title,
+			Title:   feed.Title,
+			Type:    "rss",
+			XMLURL:  feed.XMLURL,
+			HTMLURL: feed.HTMLURL,
+		})
+	}
+	return doc
+}
+
+// folderOutlines returns the outlines of the folder called name, adding the
+// folder when it is not there yet.
+func folderOutlines(outlines *[]Outline, name string) *[]Outline {
+	for i := range *outlines {
+		if o := &(*outlines)[i]; o.XMLURL == "" && o.Name() == name {
+			return &o.Outlines
+		}
+	}
+	*outlines = append(*outlines, Outline{Text: name, Title: name})
+	return &(*outlines)[len(*outlines)-1].Outlines
+}
+
+type xmlDocument struct {
+	XMLName xml.Name `xml:"opml"`
+	Version string   `xml:"version,attr"`
+	Head    struct {
+		Title       string `xml:"title"`
+		DateCreated string `xml:"dateCreated,omitempty"`
+	} `xml:"head"`
+	Body struct {
+		Outlines []xmlOutline `xml:"outline"`
+	} `xml:"body"`
+}
+
+type xmlOutline struct {
+	Attrs    []xml.Attr   `xml:",any,attr"`
+	Outlines []xmlOutline `xml:"outline"`
+}
+
+// attr finds an attribute ignoring case, older exporters write xmlurl or
+// url instead of xmlUrl.
+func (o xmlOutline) attr(names ...string) string {
+	for _, name := range names {
+		for _, a := range o.Attrs {
+			if strings.EqualFold(a.Name.Local, name) {
+				return strings.TrimSpace(a.Value)
+			}
+		}
+	}
+	return ""
+}
+
+func newXMLOutline(o Outline) xmlOutline {
+	var out xmlOutline
+	for _, a := range []struct{ name, value string }{
+		{"text", o.Text}, {"title", o.Title}, {"type", o.Type}, {"xmlUrl", o.XMLURL}, {"htmlUrl", o.HTMLURL},
+	} {
+		// text is required, the other attributes are left out when empty
+		if a.value != "" || a.name == "text" {
+			out.Attrs = append(out.Attrs, xml.Attr{Name: xml.Name{Local: a.name}, Value: a.value})
+		}
+	}
+	for _, child := range o.Outlines {
+		out.Outlines = append(out.Outlines, newXMLOutline(child))
+	}
+	return out
+}
+
+func (o xmlOutline) outline() Outline {
+	out := Outline{
+		Text:    o.attr("text"),
+		Title:   o.attr("title"),
+		Type:    o.attr("type"),
+		XMLURL:  o.attr("xmlUrl"),
+		HTMLURL: o.attr("htmlUrl"),
+	}
+	if out.XMLURL == "" && strings.EqualFold(out.Type, "rss") {
+		out.XMLURL = o.attr("url")
+	}
+	for _, child := range o.Outlines {
+		out.Outlines = append(out.Outlines, child.outline())
+	}
+	return out
+}
+
+// Parse reads an OPML document.
+func Parse(r io.Reader) (*Document, error) {
+	var doc xmlDocument
+	decoder := xml.NewDecoder(r)
+	decoder.CharsetReader = charset.NewReaderLabel
+	if err := decoder.Decode(&doc); err != nil {
+		var syntax *xml.SyntaxError
+		if errors.As(err, &syntax) {
+			return nil, fmt.Errorf("invalid opml on line %v: %v", syntax.Line, syntax.Msg)
+		}
+		return nil, fmt.Errorf("invalid opml: %v", err)
+	}
+	out := &Document{Version: doc.Version, Title: strings.TrimSpace(doc.Head.Title)}
+	for _, layout := range []string{time.RFC1123Z, time.RFC1123, time.RFC3339} {
+		if created, err := time.Parse(layout, strings.TrimSpace(doc.Head.DateCreated)); err == nil {
+			out.Created = created
+			break
+		}
+	}
+	for _, o := range doc.Body.Outlines {
+		out.Outlines = append(out.Outlines, o.outline())
+	}
+	return out, nil
+}
+
+// Write writes the document as OPML 2.0.
+func (d *Document) Write(w io.Writer) error {
+	var doc xmlDocument
+	doc.Version = "2.0"
+	doc.Head.Title = d.Title
+	if !d.Created.IsZero() {
+		doc.Head.DateCreated = d.Created.Format(time.RFC1123Z)
+	}
+	for _, o := range d.Outlines {
+		doc.Body.Outlines = append(doc.Body.Outlines, newXMLOutline(o))
+	}
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(doc); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
@@ -0,0 +1,122 @@
+package opml
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	cases := map[string]struct {
+		input string
+		title string
+		feeds []Feed
+		err   bool
+	}{
+		"opml 2.0 nested folders": {
+			input: `<?xml version="1.0" encoding="UTF-8"?>
+<opml version="2.0">
+  <head><title>Subscriptions</title></head>
+  <body>
+    <outline text="Go Blog" type="rss" xmlUrl="https://go.dev/blog/feed.atom" htmlUrl="https://go.dev/blog"/>
+    <outline text="Tech">
+      <outline text="LWN" title="LWN.net" type="rss" xmlUrl="https://lwn.net/headlines/rss"/>
+      <outline text="Languages">
+        <outline text="Rust" type="rss" xmlUrl="https://blog.rust-lang.org/feed.xml"/>
+      </outline>
+    </outline>
+  </body>
+</opml>`,
+			title: "Subscriptions",
+			feeds: []Feed{
+				{Title: "Go Blog", XMLURL: "https://go.dev/blog/feed.atom", HTMLURL: "https://go.dev/blog"},
+				{Title: "LWN.net", XMLURL: "https://lwn.net/headlines/rss", Folder: "Tech"},
+				{Title: "Rust", XMLURL: "https://blog.rust-lang.org/feed.xml", Folder: "Tech/Languages"},
+			},
+		},
+		"opml 1.0 attribute case": {
+			input: `<opml version="1.0"><head><title>old reader</title></head><body>
+<outline title="News"><outline title="Example" type="rss" xmlurl="https://example.com/rss" htmlurl="https://example.com"/></outline>
+<outline text="Url Attribute" type="rss" url="https://example.org/feed"/>
+</body></opml>`,
+			title: "old reader",
+			feeds: []Feed{
+				{Title: "Example", XMLURL: "https://example.com/rss", HTMLURL: "https://example.com", Folder: "News"},
+				{Title: "Url Attribute", XMLURL: "https://example.org/feed"},
+			},
+		},
+		"iso-8859-1 declaration": {
+			input: `<?xml version="1.0" encoding="ISO-8859-1"?><opml version="2.0"><body><outline text="Caf` + "\xe9" + `" xmlUrl="https://a.example/rss"/></body></opml>`,
+			feeds: []Feed{{Title: "Café", XMLURL: "https://a.example/rss"}},
+		},
+		"empty body":   {input: `<opml version="2.0"><head/><body/></opml>`},
+		"not opml":     {input: `<rss version="2.0"><channel/></rss>`, err: true},
+		"invalid xml":  {input: `<opml version="2.0"><body><outline></body></opml>`, err: true},
+		"empty source": {input: ``, err: true},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			doc, err := Parse(strings.NewReader(tc.input))
+			if tc.err {
+				if err == nil {
+					t.Errorf("Parse Failed, wanted an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Parse Failed %v", err)
+			}
+			if doc.Title != tc.title {
+				t.Errorf("Title Mismatch wanted: %v , got: %v", tc.title, doc.Title)
+			}
+			if feeds := doc.Feeds(); !reflect.DeepEqual(feeds, tc.feeds) {
+				t.Errorf("Feeds Mismatch wanted: %+v , got: %+v", tc.feeds, feeds)
+			}
+		})
+	}
+}
+
+func TestWriteRoundTrip(t *testing.T) {
+	created := time.Date(2024, 3, 1, 9, 30, 0, 0, time.UTC)
+	feeds := []Feed{
+		{Title: "Go Blog", XMLURL: "https://go.dev/blog/feed.atom", HTMLURL: "https://go.dev/blog"},
+		{Title: "LWN.net", XMLURL: "https://lwn.net/headlines/rss", Folder: "Tech"},
+		{Title: "Rust", XMLURL: "https://blog.rust-lang.org/feed.xml", Folder: "Tech/Languages"},
+		{Title: "Hacker News", XMLURL: "https://news.ycombinator.com/rss?a=1&b=2", Folder: "Tech"},
+		{Title: `Quotes "&" <Brackets>`, XMLURL: "https://example.com/rss"},
+	}
+
+	var b bytes.Buffer
+	if err := FromFeeds("gator subscriptions of alice", created, feeds).Write(&b); err != nil {
+		t.Fatalf("Write Failed %v", err)
+	}
+	if !strings.HasPrefix(b.String(), `<?xml version="1.0" encoding="UTF-8"?>`) || !strings.Contains(b.String(), `<opml version="2.0">`) {
+		t.Errorf("Write Failed, not an opml 2.0 document:\n%v", b.String())
+	}
+
+	doc, err := Parse(&b)
+	if err != nil {
+		t.Fatalf("Parse Failed %v", err)
+	}
+	if doc.Title != "gator subscriptions of alice" || !doc.Created.Equal(created) {
+		t.Errorf("head Mismatch wanted: %v %v , got: %v %v", "gator subscriptions of alice", created, doc.Title, doc.Created)
+	}
+	if got := doc.Feeds(); !reflect.DeepEqual(got, feeds) {
+		t.Errorf("Feeds Mismatch wanted: %+v , got: %+v", feeds, got)
+	}
+}
+
+func TestWriteEmpty(t *testing.T) {
+	var b bytes.Buffer
+	if err := FromFeeds("empty", time.Time{}, nil).Write(&b); err != nil {
+		t.Fatalf("Write Failed %v", err)
+	}
+	if !strings.Contains(b.String(), "<body></body>") {
+		t.Errorf("Write Failed, the body is required even without feeds:\n%v", b.String())
+	}
+	if _, err := Parse(&b); err != nil {
+		t.Errorf("Parse Failed %v", err)
+	}
+}
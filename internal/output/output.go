@@ -0,0 +1,202 @@
+package output
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// Format is how a listing is written.
+type Format string
+
+const (
+	// Text is the human readable listing of each command, written by the command itself.
+	Text  Format = "text"
+	Table Format = "table"
+	JSON  Format = "json"
+	JSONL Format = "jsonl"
+	CSV   Format = "csv"
+	TSV   Format = "tsv"
+)
+
+// Formats lists every format, Text first.
+var Formats = []string{string(Text), string(Table), string(JSON), string(JSONL), string(CSV), string(TSV)}
+
+type field struct {
+	name  string
+	index int
+}
+
+// Fields returns the field names of a record type, its json tags in order.
+func Fields(record interface{}) []string {
+	var names []string
+	for _, f := range structFields(reflect.TypeOf(record)) {
+		names = append(names, f.name)
+	}
+	return names
+}
+
+func structFields(t reflect.Type) []field {
+	for t.Kind() == reflect.Slice || t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	var fields []field
+	for i := 0; i < t.NumField(); i++ {
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+		if name == "" || name == "-" {
+			continue
+		}
+		fields = append(fields, field{name: name, index: i})
+	}
+	return fields
+}
+
+// selectFields picks the named fields, all of them when names is empty.
+func selectFields(all []field, names []string) ([]field, error) {
+	if len(names) == 0 {
+		return all, nil
+	}
+	byName := map[string]field{}
+	var known []string
+	for _, f := range all {
+		byName[f.name] = f
+		known = append(known, f.name)
+	}
+	var selected []field
+	for _, name := range names {
+		f, ok := byName[strings.TrimSpace(name)]
+		if !ok {
+			return nil, fmt.Errorf("unknown field %v, expected one of %v", name, strings.Join(known, ", "))
+		}
+		selected = append(selected, f)
+	}
+	return selected, nil
+}
+
+// Write writes records, a slice of structs with json tags, in format.
+// names limits and orders the fields, every field is written when it is empty.
+func Write(w io.Writer, format Format, records interface{}, names []string) error {
+	rows := reflect.ValueOf(records)
+	if rows.Kind() != reflect.Slice {
+		return fmt.Errorf("records must be a slice, got %v", rows.Kind())
+	}
+	fields, err := selectFields(structFields(rows.Type()), names)
+	if err != nil {
+		return err
+	}
+
+	switch format {
+	case JSON, JSONL:
+		var objects []json.RawMessage
+		for i := 0; i < rows.Len(); i++ {
+			object, err := jsonObject(rows.Index(i), fields)
+			if err != nil {
+				return err
+			}
+			objects = append(objects, object)
+		}
+		if format == JSONL {
+			for _, object := range objects {
+				fmt.Fprintf(w, "%s\n", object)
+			}
+			return nil
+		}
+		if objects == nil {
+			objects = []json.RawMessage{}
+		}
+		data, err := json.MarshalIndent(objects, "", "  ")
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintf(w, "%s\n", data)
+		return err
+
+	case CSV:
+		out := csv.NewWriter(w)
+		out.Write(header(fields, strings.ToLower))
+		for i := 0; i < rows.Len(); i++ {
+			line := make([]string, len(fields))
+			for j, f := range fields {
+				line[j] = cell(rows.Index(i).Field(f.index))
+			}
+			out.Write(line)
+		}
+		out.Flush()
+		return out.Error()
+
+	case TSV, Table:
+		var out io.Writer = w
+		var tab *tabwriter.Writer
+		names := header(fields, strings.ToLower)
+		if format == Table {
+			tab = tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+			out = tab
+			names = header(fields, strings.ToUpper)
+		}
+		fmt.Fprintln(out, strings.Join(names, "\t"))
+		for i := 0; i < rows.Len(); i++ {
+			line := make([]string, len(fields))
+			for j, f := range fields {
+				// there is no quoting, tabs and newlines in a value become spaces
+				line[j] = strings.Join(strings.Fields(cell(rows.Index(i).Field(f.index))), " ")
+			}
+			fmt.Fprintln(out, strings.Join(line, "\t"))
+		}
+		if tab != nil {
+			return tab.Flush()
+		}
+		return nil
+
+	default:
+		return fmt.Errorf("format %v is written by the command itself", format)
+	}
+}
+
+func header(fields []field, caseOf func(string) string) []string {
+	names := make([]string, len(fields))
+	for i, f := range fields {
+		names[i] = caseOf(f.name)
+	}
+	return names
+}
+
+// jsonObject encodes the fields of a record as an object, keeping their order.
+func jsonObject(record reflect.Value, fields []field) (json.RawMessage, error) {
+	var b bytes.Buffer
+	b.WriteByte('{')
+	for i, f := range fields {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		key, _ := json.Marshal(f.name)
+		value, err := json.Marshal(record.Field(f.index).Interface())
+		if err != nil {
+			return nil, err
+		}
+		b.Write(key)
+		b.WriteByte(':')
+		b.Write(value)
+	}
+	b.WriteByte('}')
+	return b.Bytes(), nil
+}
+
+// cell formats a value for csv, tsv and table output.
+func cell(v reflect.Value) string {
+	if v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return ""
+		}
+		v = v.Elem()
+	}
+	if t, ok := v.Interface().(time.Time); ok {
+		return t.Format(time.RFC3339)
+	}
+	return fmt.Sprint(v.Interface())
+}
@@ -0,0 +1,101 @@
+package output
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+type testRecord struct {
+	Name    string     `json:"name"`
+	Unread  int64      `json:"unread"`
+	Fetched *time.Time `json:"fetched_at"`
+	secret  string
+}
+
+func TestWrite(t *testing.T) {
+	fetched := time.Date(2024, 5, 1, 8, 0, 0, 0, time.UTC)
+	records := []testRecord{
+		{Name: "Go Blog", Unread: 3, Fetched: &fetched},
+		{Name: "tabs\tand, commas", Unread: 0},
+	}
+
+	cases := map[string]struct {
+		format Format
+		fields []string
+		out    string
+	}{
+		"json": {JSON, nil, `[
+  {
+    "name": "Go Blog",
+    "unread": 3,
+    "fetched_at": "2024-05-01T08:00:00Z"
+  },
+  {
+    "name": "tabs\tand, commas",
+    "unread": 0,
+    "fetched_at": null
+  }
+]
+`},
+		"jsonl fields": {JSONL, []string{"unread", "name"}, `{"unread":3,"name":"Go Blog"}
+{"unread":0,"name":"tabs\tand, commas"}
+`},
+		"csv": {CSV, nil, `name,unread,fetched_at
+Go Blog,3,2024-05-01T08:00:00Z
+"tabs	and, commas",0,
+`},
+		"tsv": {TSV, []string{"name"}, "name\nGo Blog\ntabs and, commas\n"},
+		"table": {Table, []string{"name", "unread"}, `NAME              UNREAD
+Go Blog           3
+tabs and, commas  0
+`},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var b bytes.Buffer
+			err := Write(&b, tc.format, records, tc.fields)
+			if err != nil {
+				t.Errorf("Write Failed %v", err)
+				return
+			}
+			if b.String() != tc.out {
+				t.Errorf("Write Mismatch wanted: %q , got: %q", tc.out, b.String())
+			}
+		})
+	}
+}
+
+func TestWriteEmptyJSON(t *testing.T) {
+	var b bytes.Buffer
+	err := Write(&b, JSON, []testRecord{}, nil)
+	if err != nil {
+		t.Errorf("Write Failed %v", err)
+		return
+	}
+	if b.String() != "[]\n" {
+		t.Errorf("Write Mismatch wanted: %q , got: %q", "[]\n", b.String())
+	}
+}
+
+func TestWriteUnknownField(t *testing.T) {
+	var b bytes.Buffer
+	err := Write(&b, CSV, []testRecord{}, []string{"secret"})
+	if err == nil {
+		t.Errorf("Write accepted the unknown field secret")
+	}
+}
+
+func TestFields(t *testing.T) {
+	got := Fields(testRecord{})
+	want := []string{"name", "unread", "fetched_at"}
+	if len(got) != len(want) {
+		t.Errorf("Fields Mismatch wanted: %v , got: %v", want, got)
+		return
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("Fields Mismatch wanted: %v , got: %v", want, got)
+		}
+	}
+}
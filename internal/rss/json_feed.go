@@ -0,0 +1,70 @@
+package rss
+
+import (
+	"encoding/json"
+	"errors"
+)
+
+// jsonFeed is the subset of the JSON Feed format (https://jsonfeed.org) that gator uses.
+type jsonAuthor struct {
+	Name string `json:"name"`
+}
+
+type jsonFeed struct {
+	Version     string `json:"version"`
+	Title       string `json:"title"`
+	HomePageURL string `json:"home_page_url"`
+	Description string `json:"description"`
+	Items       []struct {
+		ID            string `json:"id"`
+		URL           string `json:"url"`
+		Title         string `json:"title"`
+		ContentText   string `json:"content_text"`
+		ContentHTML   string `json:"content_html"`
+		Summary       string `json:"summary"`
+		DatePublished string `json:"date_published"`
+		// author is the JSON Feed 1.0 field, replaced by authors in 1.1
+		Author  *jsonAuthor  `json:"author"`
+		Authors []jsonAuthor `json:"authors"`
+	} `json:"items"`
+}
+
+func parseJSON(data []byte) (*RSSFeed, error) {
+	var feed jsonFeed
+	err := json.Unmarshal(data, &feed)
+	if err != nil {
+		return nil, err
+	}
+	if feed.Version == "" && feed.Items == nil {
+		return nil, errors.New("not a json feed, missing version and items")
+	}
+
+	var rss RSSFeed
+	rss.Channel.Title = feed.Title
+	rss.Channel.Link = feed.HomePageURL
+	rss.Channel.Description = feed.Description
+	for _, item := range feed.Items {
+		rssitem := RSSItem{
+			Title:       item.Title,
+			Link:        item.URL,
+			Description: item.Summary,
+			PubDate:     item.DatePublished,
+		}
+		if rssitem.Link == "" {
+			rssitem.Link = item.ID
+		}
+		if rssitem.Description == "" {
+			rssitem.Description = item.ContentText
+		}
+		if rssitem.Description == "" {
+			rssitem.Description = item.ContentHTML
+		}
+		if len(item.Authors) > 0 {
+			rssitem.Author = item.Authors[0].Name
+		} else if item.Author != nil {
+			rssitem.Author = item.Author.Name
+		}
+		rss.Channel.Item = append(rss.Channel.Item, rssitem)
+	}
+	return &rss, nil
+}
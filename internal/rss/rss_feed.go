@@ -1,11 +1,10 @@
 package rss
 
 import (
-	"context"
+	"bytes"
 	"encoding/xml"
 	"html"
-	"io"
-	"net/http"
+	"strings"
 )
 
 type RSSFeed struct {
@@ -22,27 +21,19 @@ type RSSItem struct {
 	Link        string `xml:"link"`
 	Description string `xml:"description"`
 	PubDate     string `xml:"pubDate"`
+	Author      string `xml:"author"`
+	// Creator is the dc:creator element most feeds use instead of author.
+	Creator string `xml:"http://purl.org/dc/elements/1.1/ creator"`
 }
 
-func FetchFeed(ctx context.Context, feedURL string) (*RSSFeed, error) {
-
-	req, err := http.NewRequestWithContext(ctx, "GET", feedURL, nil)
-	if err != nil {
-		return nil, err
+// Parse reads an rss (xml) or json feed document.
+func Parse(data []byte) (*RSSFeed, error) {
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '{' {
+		return parseJSON(trimmed)
 	}
-	req.Header.Set("User-Agent", "gator")
 
-	resp, err := http.DefaultClient.Do(req)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-	rss_feed, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, err
-	}
 	var rss RSSFeed
-	err = xml.Unmarshal(rss_feed, &rss)
+	err := xml.Unmarshal(data, &rss)
 	if err != nil {
 		return nil, err
 	}
@@ -53,6 +44,10 @@ func FetchFeed(ctx context.Context, feedURL string) (*RSSFeed, error) {
 	for i, rssitem := range rss.Channel.Item {
 		rssitem.Title = html.UnescapeString(rssitem.Title)
 		rssitem.Description = html.UnescapeString(rssitem.Description)
+		if rssitem.Author == "" {
+			rssitem.Author = rssitem.Creator
+		}
+		rssitem.Author = html.UnescapeString(strings.TrimSpace(rssitem.Author))
 		rss.Channel.Item[i] = rssitem
 	}
 
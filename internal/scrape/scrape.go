@@ -0,0 +1,119 @@
+package scrape
+
+import (
+	"errors"
+	"io"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/o0n1x/gator/internal/rss"
+)
+
+// Selectors describe where the items of a page are. Item is matched against the page,
+// the others are matched inside every item. Date and Summary are optional.
+type Selectors struct {
+	Item    string
+	Title   string
+	Link    string
+	Date    string
+	Summary string
+	// DateFormat is an optional go time layout for the text matched by Date.
+	DateFormat string
+}
+
+var dateLayouts = []string{
+	time.RFC3339,
+	time.RFC1123Z,
+	time.RFC1123,
+	"2006-01-02 15:04:05",
+	"2006-01-02",
+	"January 2, 2006",
+	"Jan 2, 2006",
+	"2 January 2006",
+	"02 Jan 2006",
+}
+
+func (sel Selectors) Validate() error {
+	if sel.Item == "" || sel.Title == "" || sel.Link == "" {
+		return errors.New("item, title and link selectors are required")
+	}
+	return nil
+}
+
+// Items turns every match of sel.Item into an rss item. Relative links are resolved
+// against pageURL and dates are rewritten as RFC3339, or left empty when unparsable.
+func Items(r io.Reader, pageURL string, sel Selectors) ([]rss.RSSItem, error) {
+	if err := sel.Validate(); err != nil {
+		return nil, err
+	}
+	base, err := url.Parse(pageURL)
+	if err != nil {
+		return nil, err
+	}
+	doc, err := goquery.NewDocumentFromReader(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var items []rss.RSSItem
+	doc.Find(sel.Item).Each(func(_ int, item *goquery.Selection) {
+		title := text(item.Find(sel.Title).First())
+		href := link(item.Find(sel.Link).First())
+		if href == "" {
+			return
+		}
+		ref, err := base.Parse(href)
+		if err != nil {
+			return
+		}
+		if title == "" {
+			title = ref.String()
+		}
+
+		rssitem := rss.RSSItem{
+			Title: title,
+			Link:  ref.String(),
+		}
+		if sel.Summary != "" {
+			rssitem.Description = text(item.Find(sel.Summary).First())
+		}
+		if sel.Date != "" {
+			rssitem.PubDate = date(item.Find(sel.Date).First(), sel.DateFormat)
+		}
+		items = append(items, rssitem)
+	})
+	return items, nil
+}
+
+func text(s *goquery.Selection) string {
+	return strings.Join(strings.Fields(s.Text()), " ")
+}
+
+// link returns the href of s, or of the first link inside it.
+func link(s *goquery.Selection) string {
+	if href, ok := s.Attr("href"); ok {
+		return strings.TrimSpace(href)
+	}
+	href, _ := s.Find("a[href]").First().Attr("href")
+	return strings.TrimSpace(href)
+}
+
+func date(s *goquery.Selection, format string) string {
+	raw, ok := s.Attr("datetime")
+	if !ok {
+		raw = text(s)
+	}
+	layouts := dateLayouts
+	if format != "" {
+		layouts = []string{format}
+	}
+	for _, layout := range layouts {
+		t, err := time.Parse(layout, raw)
+		if err == nil {
+			return t.UTC().Format(time.RFC3339)
+		}
+	}
+	return ""
+}
@@ -0,0 +1,58 @@
+package scrape
+
+import (
+	"strings"
+	"testing"
+)
+
+const changelogPage = `<html><body>
+<ul class="releases">
+  <li class="release">
+    <h2><a href="/releases/v2">Version 2.0</a></h2>
+    <time datetime="2025-03-01T10:00:00Z">March 1</time>
+    <p class="notes">New dashboard.</p>
+  </li>
+  <li class="release">
+    <h2><a href="https://cdn.example.com/v1">Version 1.0</a></h2>
+    <span class="date">January 5, 2025</span>
+    <p class="notes">First release.</p>
+  </li>
+  <li class="release"><h2>No link here</h2></li>
+</ul>
+</body></html>`
+
+func TestItems(t *testing.T) {
+	sel := Selectors{
+		Item:    "li.release",
+		Title:   "h2",
+		Link:    "h2 a",
+		Date:    "time, .date",
+		Summary: ".notes",
+	}
+	items, err := Items(strings.NewReader(changelogPage), "https://example.com/changelog", sel)
+	if err != nil {
+		t.Fatalf("Items Failed %v", err)
+	}
+	if len(items) != 2 {
+		t.Fatalf("wanted 2 items, got %v", len(items))
+	}
+
+	cases := []struct {
+		title, link, date, summary string
+	}{
+		{"Version 2.0", "https://example.com/releases/v2", "2025-03-01T10:00:00Z", "New dashboard."},
+		{"Version 1.0", "https://cdn.example.com/v1", "2025-01-05T00:00:00Z", "First release."},
+	}
+	for i, tc := range cases {
+		item := items[i]
+		if item.Title != tc.title || item.Link != tc.link || item.PubDate != tc.date || item.Description != tc.summary {
+			t.Errorf("item %v mismatch wanted: %+v , got: %+v", i, tc, item)
+		}
+	}
+}
+
+func TestSelectorsValidate(t *testing.T) {
+	if err := (Selectors{Item: "li", Title: "h2"}).Validate(); err == nil {
+		t.Errorf("expected error for missing link selector")
+	}
+}
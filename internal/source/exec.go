@@ -0,0 +1,47 @@
+package source
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// ExecFetcher runs the command of an exec://command arg1 arg2 url and returns its stdout.
+type ExecFetcher struct {
+	Timeout time.Duration
+}
+
+func NewExecFetcher() ExecFetcher {
+	return ExecFetcher{Timeout: time.Minute}
+}
+
+func (f ExecFetcher) Fetch(ctx context.Context, rawURL string) ([]byte, error) {
+	command, ok := strings.CutPrefix(rawURL, "exec://")
+	if !ok {
+		return nil, fmt.Errorf("invalid exec url %v", rawURL)
+	}
+	args := strings.Fields(command)
+	if len(args) == 0 {
+		return nil, errors.New("exec url has no command")
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, f.Timeout)
+	defer cancel()
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, args[0], args[1:]...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	err := cmd.Run()
+	if err != nil {
+		return nil, fmt.Errorf("running %v: %v: %v", args[0], err, strings.TrimSpace(stderr.String()))
+	}
+	if stdout.Len() > maxDocumentSize {
+		return nil, fmt.Errorf("output of %v is larger than %v bytes", args[0], maxDocumentSize)
+	}
+	return stdout.Bytes(), nil
+}
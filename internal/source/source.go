@@ -0,0 +1,64 @@
+package source
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// maxDocumentSize caps how much of a fetched document is read.
+const maxDocumentSize = 10 << 20
+
+// Fetcher retrieves the raw document (feed, html page, ...) behind a url.
+type Fetcher interface {
+	Fetch(ctx context.Context, rawURL string) ([]byte, error)
+}
+
+// Registry selects a Fetcher by url scheme.
+type Registry struct {
+	fetchers map[string]Fetcher
+}
+
+// optIn are the schemes that reach the host agg runs on, by the config
+// setting that enables them.
+var optIn = map[string]string{
+	"file": "allow_file_feeds",
+	"exec": "allow_exec_feeds",
+}
+
+// NewRegistry returns a registry with the built-in http(s) fetchers. file://
+// reads local files and exec:// runs local commands, and any user can add a
+// feed, so they are only registered when allowFile and allowExec are set.
+func NewRegistry(allowFile, allowExec bool) *Registry {
+	r := &Registry{fetchers: map[string]Fetcher{}}
+	web := NewHTTPFetcher()
+	r.Register("http", web)
+	r.Register("https", web)
+	if allowFile {
+		r.Register("file", FileFetcher{})
+	}
+	if allowExec {
+		r.Register("exec", NewExecFetcher())
+	}
+	return r
+}
+
+func (r *Registry) Register(scheme string, f Fetcher) {
+	r.fetchers[strings.ToLower(scheme)] = f
+}
+
+// Fetch retrieves rawURL with the fetcher registered for its scheme.
+func (r *Registry) Fetch(ctx context.Context, rawURL string) ([]byte, error) {
+	scheme, _, ok := strings.Cut(rawURL, "://")
+	if !ok {
+		return nil, fmt.Errorf("url %v has no scheme", rawURL)
+	}
+	f, ok := r.fetchers[strings.ToLower(scheme)]
+	if !ok {
+		if setting, ok := optIn[strings.ToLower(scheme)]; ok {
+			return nil, fmt.Errorf("%v feeds are disabled, set %v in the config to fetch %v", scheme, setting, rawURL)
+		}
+		return nil, fmt.Errorf("unsupported url scheme %v", scheme)
+	}
+	return f.Fetch(ctx, rawURL)
+}
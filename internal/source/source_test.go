@@ -0,0 +1,81 @@
+package source
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/o0n1x/gator/internal/rss"
+)
+
+func TestRegistryFetch(t *testing.T) {
+	xmlPath, err := filepath.Abs(filepath.Join("testdata", "feed.xml"))
+	if err != nil {
+		t.Fatalf("failed to resolve fixture: %v", err)
+	}
+	jsonPath, err := filepath.Abs(filepath.Join("testdata", "feed.json"))
+	if err != nil {
+		t.Fatalf("failed to resolve fixture: %v", err)
+	}
+
+	cases := map[string]struct {
+		url       string
+		title     string
+		items     int
+		firstLink string
+	}{
+		"file xml":  {"file://" + xmlPath, "Offline & Local", 2, "https://example.com/first"},
+		"file json": {"file://" + jsonPath, "Script Output", 1, "https://example.com/build/42"},
+		"exec":      {"exec://cat " + xmlPath, "Offline & Local", 2, "https://example.com/first"},
+	}
+
+	r := NewRegistry(true, true)
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			data, err := r.Fetch(context.Background(), tc.url)
+			if err != nil {
+				t.Errorf("Fetch Failed %v", err)
+				return
+			}
+			feed, err := rss.Parse(data)
+			if err != nil {
+				t.Errorf("Parse Failed %v", err)
+				return
+			}
+			if feed.Channel.Title != tc.title {
+				t.Errorf("Title Mismatch wanted: %v , got: %v", tc.title, feed.Channel.Title)
+			}
+			if len(feed.Channel.Item) != tc.items {
+				t.Errorf("Items Mismatch wanted: %v , got: %v", tc.items, len(feed.Channel.Item))
+				return
+			}
+			if feed.Channel.Item[0].Link != tc.firstLink {
+				t.Errorf("Link Mismatch wanted: %v , got: %v", tc.firstLink, feed.Channel.Item[0].Link)
+			}
+		})
+	}
+}
+
+func TestRegistryErrors(t *testing.T) {
+	cases := map[string]struct {
+		allow bool
+		url   string
+		err   string
+	}{
+		"exec disabled":   {false, "exec://echo hi", "exec feeds are disabled, set allow_exec_feeds"},
+		"file disabled":   {false, "file:///etc/passwd", "file feeds are disabled, set allow_file_feeds"},
+		"unknown scheme":  {true, "gopher://example.com/feed", "unsupported url scheme"},
+		"failing command": {true, "exec://false", "running false"},
+		"no scheme":       {true, "example.com/feed", "has no scheme"},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, err := NewRegistry(tc.allow, tc.allow).Fetch(context.Background(), tc.url)
+			if err == nil || !strings.Contains(err.Error(), tc.err) {
+				t.Errorf("Error Mismatch wanted: %v , got: %v", tc.err, err)
+			}
+		})
+	}
+}
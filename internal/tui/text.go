@@ -0,0 +1,57 @@
+package tui
+
+import (
+	"regexp"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+var blankLines = regexp.MustCompile(`\n{3,}`)
+
+// blockTags start a new line when the description is turned into text.
+var blockTags = map[string]bool{
+	"p": true, "br": true, "div": true, "li": true, "tr": true, "blockquote": true, "pre": true,
+	"h1": true, "h2": true, "h3": true, "h4": true, "h5": true, "h6": true,
+}
+
+// plainText turns an html description into paragraphs of text, text
+// without markup comes back with only its spaces cleaned up.
+func plainText(s string) string {
+	var b strings.Builder
+	z := html.NewTokenizer(strings.NewReader(s))
+	skip := 0
+	for {
+		switch z.Next() {
+		case html.ErrorToken:
+			text := strings.TrimSpace(blankLines.ReplaceAllString(b.String(), "\n\n"))
+			var lines []string
+			for _, line := range strings.Split(text, "\n") {
+				lines = append(lines, strings.Join(strings.Fields(line), " "))
+			}
+			return strings.Join(lines, "\n")
+		case html.TextToken:
+			if skip == 0 {
+				b.Write(z.Text())
+			}
+		case html.StartTagToken, html.SelfClosingTagToken:
+			name, _ := z.TagName()
+			switch tag := string(name); {
+			case tag == "script" || tag == "style":
+				skip++
+			case blockTags[tag]:
+				b.WriteString("\n\n")
+			}
+		case html.EndTagToken:
+			name, _ := z.TagName()
+			switch tag := string(name); {
+			case tag == "script" || tag == "style":
+				if skip > 0 {
+					skip--
+				}
+			case blockTags[tag]:
+				b.WriteString("\n\n")
+			}
+		}
+	}
+}
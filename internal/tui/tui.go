@@ -0,0 +1,557 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"runtime"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/google/uuid"
+	"github.com/o0n1x/gator/internal/database"
+)
+
+const (
+	postLimit   = 200
+	feedWidth   = 26
+	helpFeeds   = "j/k move  enter posts  u unread only  / search  R refresh  q quit"
+	helpPosts   = "j/k move  enter read  r read/unread  s star  o open  R refresh  / search  h feeds  q quit"
+	helpReading = "j/k scroll  r read/unread  s star  o open  h posts  q quit"
+)
+
+// Options are what the reader needs from the rest of gator.
+type Options struct {
+	DB   database.Store
+	User database.User
+	// OpenCommand opens a link, the url is added as its last argument.
+	// The platform opener is used when it is empty.
+	OpenCommand string
+	// Refresh fetches the feed at url now and saves its new posts.
+	Refresh func(url string) error
+}
+
+type pane int
+
+const (
+	feedPane pane = iota
+	postPane
+	readPane
+)
+
+type feedItem struct {
+	name   string
+	url    string
+	id     uuid.NullUUID
+	unread int64
+	// oldestFirst is the default sort of the subscription
+	oldestFirst bool
+}
+
+type feedsLoadedMsg struct{ feeds []feedItem }
+
+type postsLoadedMsg struct{ posts []database.BrowseRow }
+
+type statusMsg struct{ text string }
+
+// refreshedMsg reloads the feeds and posts after new posts were saved.
+type refreshedMsg struct{ text string }
+
+type errMsg struct{ err error }
+
+type model struct {
+	opts   Options
+	width  int
+	height int
+	focus  pane
+
+	feeds      []feedItem
+	feedCursor int
+	posts      []database.BrowseRow
+	postCursor int
+	// reading is the index in posts of the post in the reading pane, -1 for none
+	reading int
+	scroll  int
+
+	unreadOnly bool
+	searching  bool
+	input      string
+	query      string
+	status     string
+}
+
+func newModel(opts Options) model {
+	return model{opts: opts, reading: -1, unreadOnly: true}
+}
+
+// Run starts the full screen reader and returns when it is closed.
+func Run(opts Options) error {
+	_, err := tea.NewProgram(newModel(opts), tea.WithAltScreen()).Run()
+	return err
+}
+
+func (m model) Init() tea.Cmd {
+	return m.loadFeeds()
+}
+
+func (m model) loadFeeds() tea.Cmd {
+	return func() tea.Msg {
+		ctx := context.Background()
+		follows, err := m.opts.DB.GetFeedFollowsForUser(ctx, uuid.NullUUID{UUID: m.opts.User.ID, Valid: true})
+		if err != nil {
+			return errMsg{fmt.Errorf("error listing feeds: %v", err)}
+		}
+		counts, err := m.opts.DB.GetUnreadCountsForUser(ctx, uuid.NullUUID{UUID: m.opts.User.ID, Valid: true})
+		if err != nil {
+			return errMsg{fmt.Errorf("error counting unread posts: %v", err)}
+		}
+		unread := map[uuid.UUID]int64{}
+		var total int64
+		for _, count := range counts {
+			unread[count.FeedID.UUID] = count.Unread
+			total += count.Unread
+		}
+		feeds := []feedItem{{name: "All feeds", unread: total}}
+		for _, follow := range follows {
+			name := follow.FeedName.String
+			if follow.DisplayName.Valid {
+				name = follow.DisplayName.String
+			}
+			feeds = append(feeds, feedItem{
+				name:        name,
+				url:         follow.FeedUrl.String,
+				id:          follow.FeedID,
+				unread:      unread[follow.FeedID.UUID],
+				oldestFirst: follow.DefaultSort == "oldest",
+			})
+		}
+		return feedsLoadedMsg{feeds}
+	}
+}
+
+func (m model) loadPosts() tea.Cmd {
+	params := database.BrowseParams{
+		UserID:     m.opts.User.ID,
+		UnreadOnly: m.unreadOnly,
+		Limit:      postLimit,
+	}
+	if m.feedCursor < len(m.feeds) {
+		params.FeedID = m.feeds[m.feedCursor].id
+		params.IncludeHidden = params.FeedID.Valid
+		params.Reverse = m.feeds[m.feedCursor].oldestFirst
+	}
+	if m.query != "" {
+		params.Search.String, params.Search.Valid = m.query, true
+	}
+	return func() tea.Msg {
+		posts, err := m.opts.DB.Browse(context.Background(), params)
+		if err != nil {
+			return errMsg{fmt.Errorf("error listing posts: %v", err)}
+		}
+		return postsLoadedMsg{posts}
+	}
+}
+
+func (m model) setRead(post database.BrowseRow, read bool) tea.Cmd {
+	return func() tea.Msg {
+		var err error
+		if read {
+			err = m.opts.DB.MarkPostRead(context.Background(), database.MarkPostReadParams{
+				UserID: m.opts.User.ID,
+				PostID: post.Post.ID,
+				ReadAt: time.Now().UTC(),
+			})
+		} else {
+			err = m.opts.DB.MarkPostUnread(context.Background(), database.MarkPostUnreadParams{
+				UserID: m.opts.User.ID,
+				PostID: post.Post.ID,
+			})
+		}
+		if err != nil {
+			return errMsg{fmt.Errorf("error marking post %v: %v", post.Post.ID, err)}
+		}
+		return nil
+	}
+}
+
+func (m model) setStar(post database.BrowseRow, star bool) tea.Cmd {
+	return func() tea.Msg {
+		var err error
+		if star {
+			err = m.opts.DB.StarPost(context.Background(), database.StarPostParams{
+				UserID:    m.opts.User.ID,
+				PostID:    post.Post.ID,
+				StarredAt: time.Now().UTC(),
+			})
+		} else {
+			_, err = m.opts.DB.UnstarPost(context.Background(), database.UnstarPostParams{
+				UserID: m.opts.User.ID,
+				PostID: post.Post.ID,
+			})
+		}
+		if err != nil {
+			return errMsg{fmt.Errorf("error starring post %v: %v", post.Post.ID, err)}
+		}
+		return nil
+	}
+}
+
+func (m model) refresh() tea.Cmd {
+	var urls []string
+	if m.feedCursor == 0 || m.feedCursor >= len(m.feeds) {
+		for _, feed := range m.feeds[min(1, len(m.feeds)):] {
+			urls = append(urls, feed.url)
+		}
+	} else {
+		urls = []string{m.feeds[m.feedCursor].url}
+	}
+	return func() tea.Msg {
+		for _, url := range urls {
+			if err := m.opts.Refresh(url); err != nil {
+				return errMsg{fmt.Errorf("error refreshing %v: %v", url, err)}
+			}
+		}
+		return refreshedMsg{fmt.Sprintf("refreshed %v feeds", len(urls))}
+	}
+}
+
+func (m model) open(post database.BrowseRow) tea.Cmd {
+	args := strings.Fields(m.opts.OpenCommand)
+	if len(args) == 0 {
+		args = defaultOpenCommand()
+	}
+	args = append(args, post.Post.Url.String)
+	return func() tea.Msg {
+		cmd := exec.Command(args[0], args[1:]...)
+		if err := cmd.Start(); err != nil {
+			return errMsg{fmt.Errorf("error opening %v: %v", post.Post.Url.String, err)}
+		}
+		go cmd.Wait()
+		return statusMsg{"opened " + post.Post.Url.String}
+	}
+}
+
+func defaultOpenCommand() []string {
+	switch runtime.GOOS {
+	case "darwin":
+		return []string{"open"}
+	case "windows":
+		return []string{"rundll32", "url.dll,FileProtocolHandler"}
+	default:
+		return []string{"xdg-open"}
+	}
+}
+
+// current is the post the keys act on, the one being read or else the selected one.
+func (m model) current() (int, bool) {
+	if m.focus == readPane && m.reading >= 0 && m.reading < len(m.posts) {
+		return m.reading, true
+	}
+	if m.postCursor < len(m.posts) {
+		return m.postCursor, true
+	}
+	return 0, false
+}
+
+func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width, m.height = msg.Width, msg.Height
+		return m, nil
+
+	case feedsLoadedMsg:
+		m.feeds = msg.feeds
+		m.feedCursor = min(m.feedCursor, len(m.feeds)-1)
+		return m, m.loadPosts()
+
+	case postsLoadedMsg:
+		m.posts = msg.posts
+		m.postCursor = min(m.postCursor, max(len(m.posts)-1, 0))
+		m.reading = -1
+		if m.focus == readPane {
+			m.focus = postPane
+		}
+		return m, nil
+
+	case statusMsg:
+		m.status = msg.text
+		return m, nil
+
+	case refreshedMsg:
+		m.status = msg.text
+		return m, m.loadFeeds()
+
+	case errMsg:
+		m.status = msg.err.Error()
+		return m, nil
+
+	case tea.KeyMsg:
+		if m.searching {
+			return m.updateSearch(msg)
+		}
+		return m.updateKey(msg)
+	}
+	return m, nil
+}
+
+func (m model) updateSearch(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyEnter:
+		m.searching = false
+		m.query = strings.TrimSpace(m.input)
+		m.postCursor = 0
+		return m, m.loadPosts()
+	case tea.KeyEsc:
+		m.searching = false
+		return m, nil
+	case tea.KeyBackspace:
+		if len(m.input) > 0 {
+			runes := []rune(m.input)
+			m.input = string(runes[:len(runes)-1])
+		}
+	case tea.KeyRunes, tea.KeySpace:
+		m.input += string(msg.Runes)
+	}
+	return m, nil
+}
+
+func (m model) updateKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	m.status = ""
+	switch msg.String() {
+	case "q", "ctrl+c":
+		return m, tea.Quit
+	case "tab":
+		m.focus = (m.focus + 1) % 3
+		if m.focus == readPane && m.reading < 0 {
+			m.focus = feedPane
+		}
+		return m, nil
+	case "shift+tab", "h", "left", "esc":
+		if m.focus > feedPane {
+			m.focus--
+		}
+		return m, nil
+	case "/":
+		m.searching = true
+		m.input = m.query
+		return m, nil
+	case "u":
+		m.unreadOnly = !m.unreadOnly
+		m.postCursor = 0
+		return m, m.loadPosts()
+	case "R":
+		if m.opts.Refresh == nil {
+			return m, nil
+		}
+		m.status = "refreshing..."
+		return m, m.refresh()
+	}
+
+	switch m.focus {
+	case feedPane:
+		switch msg.String() {
+		case "j", "down":
+			if m.feedCursor < len(m.feeds)-1 {
+				m.feedCursor++
+				m.postCursor = 0
+				return m, m.loadPosts()
+			}
+		case "k", "up":
+			if m.feedCursor > 0 {
+				m.feedCursor--
+				m.postCursor = 0
+				return m, m.loadPosts()
+			}
+		case "enter", "l", "right":
+			m.focus = postPane
+		}
+		return m, nil
+
+	case readPane:
+		switch msg.String() {
+		case "j", "down":
+			m.scroll++
+			return m, nil
+		case "k", "up":
+			m.scroll = max(m.scroll-1, 0)
+			return m, nil
+		}
+
+	case postPane:
+		switch msg.String() {
+		case "j", "down":
+			m.postCursor = min(m.postCursor+1, max(len(m.posts)-1, 0))
+			return m, nil
+		case "k", "up":
+			m.postCursor = max(m.postCursor-1, 0)
+			return m, nil
+		case "enter", "l", "right":
+			if m.postCursor >= len(m.posts) {
+				return m, nil
+			}
+			m.reading, m.scroll, m.focus = m.postCursor, 0, readPane
+			post := m.posts[m.postCursor]
+			if post.IsRead {
+				return m, nil
+			}
+			m.posts[m.postCursor].IsRead = true
+			return m, m.setRead(post, true)
+		}
+	}
+
+	i, ok := m.current()
+	if !ok {
+		return m, nil
+	}
+	switch msg.String() {
+	case "r":
+		m.posts[i].IsRead = !m.posts[i].IsRead
+		return m, m.setRead(m.posts[i], m.posts[i].IsRead)
+	case "s":
+		m.posts[i].IsStarred = !m.posts[i].IsStarred
+		return m, m.setStar(m.posts[i], m.posts[i].IsStarred)
+	case "o":
+		return m, m.open(m.posts[i])
+	}
+	return m, nil
+}
+
+var (
+	borderStyle  = lipgloss.NewStyle().Border(lipgloss.RoundedBorder()).BorderForeground(lipgloss.Color("8"))
+	focusStyle   = borderStyle.BorderForeground(lipgloss.Color("6"))
+	selected     = lipgloss.NewStyle().Reverse(true)
+	unreadStyle  = lipgloss.NewStyle().Bold(true)
+	titleStyle   = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("2"))
+	dimStyle     = lipgloss.NewStyle().Foreground(lipgloss.Color("8"))
+	starredStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("3"))
+)
+
+func (m model) View() string {
+	if m.width == 0 {
+		return "loading..."
+	}
+	// two lines for the status bar, two for the borders of the panes
+	height := max(m.height-4, 1)
+	postWidth := max((m.width-feedWidth)*2/5, 20)
+	readWidth := max(m.width-feedWidth-postWidth-6, 10)
+
+	panes := lipgloss.JoinHorizontal(lipgloss.Top,
+		m.box(feedPane, feedWidth, height, m.feedLines(feedWidth, height)),
+		m.box(postPane, postWidth, height, m.postLines(postWidth, height)),
+		m.box(readPane, readWidth, height, m.readLines(readWidth, height)),
+	)
+
+	status := m.status
+	switch {
+	case m.searching:
+		status = "search: " + m.input + "_"
+	case status == "" && m.focus == feedPane:
+		status = helpFeeds
+	case status == "" && m.focus == postPane:
+		status = helpPosts
+	case status == "":
+		status = helpReading
+	}
+	filter := "all posts"
+	if m.unreadOnly {
+		filter = "unread"
+	}
+	if m.query != "" {
+		filter += ", search: " + m.query
+	}
+	return panes + "\n" + dimStyle.Render(truncate("["+filter+"] "+status, m.width))
+}
+
+func (m model) box(p pane, width, height int, lines []string) string {
+	style := borderStyle
+	if m.focus == p {
+		style = focusStyle
+	}
+	for len(lines) < height {
+		lines = append(lines, "")
+	}
+	return style.Width(width).Height(height).Render(strings.Join(lines[:height], "\n"))
+}
+
+func (m model) feedLines(width, height int) []string {
+	var lines []string
+	for i, feed := range m.feeds {
+		line := truncate(fmt.Sprintf("%v (%v)", feed.name, feed.unread), width)
+		if feed.unread > 0 {
+			line = unreadStyle.Render(line)
+		}
+		if i == m.feedCursor {
+			line = selected.Render(line)
+		}
+		lines = append(lines, line)
+	}
+	return window(lines, m.feedCursor, height)
+}
+
+func (m model) postLines(width, height int) []string {
+	if len(m.posts) == 0 {
+		return []string{dimStyle.Render("no posts")}
+	}
+	var lines []string
+	for i, post := range m.posts {
+		mark := " "
+		if !post.IsRead {
+			mark = "●"
+		}
+		if post.IsStarred {
+			mark = starredStyle.Render("★")
+		}
+		line := truncate(post.Post.Title.String, width-2)
+		if !post.IsRead {
+			line = unreadStyle.Render(line)
+		}
+		if i == m.postCursor {
+			line = selected.Render(line)
+		}
+		lines = append(lines, mark+" "+line)
+	}
+	return window(lines, m.postCursor, height)
+}
+
+func (m model) readLines(width, height int) []string {
+	if m.reading < 0 || m.reading >= len(m.posts) {
+		return []string{dimStyle.Render("select a post and press enter")}
+	}
+	post := m.posts[m.reading]
+	body := post.Post.Content.String
+	if body == "" {
+		body = post.Post.Description.String
+	}
+	text := strings.Join([]string{
+		titleStyle.Width(width).Render(post.Post.Title.String),
+		dimStyle.Render(truncate(post.FeedName+" · "+post.Post.PublishedAt.Time.Format("2006-01-02 15:04"), width)),
+		dimStyle.Render(truncate(post.Post.Url.String, width)),
+		"",
+		lipgloss.NewStyle().Width(width).Render(plainText(body)),
+	}, "\n")
+	lines := strings.Split(text, "\n")
+	scroll := min(m.scroll, max(len(lines)-height, 0))
+	return lines[scroll:]
+}
+
+// window keeps the cursor line visible in height lines.
+func window(lines []string, cursor, height int) []string {
+	if len(lines) <= height {
+		return lines
+	}
+	start := min(max(cursor-height/2, 0), len(lines)-height)
+	return lines[start : start+height]
+}
+
+func truncate(s string, width int) string {
+	runes := []rune(s)
+	if width <= 0 {
+		return ""
+	}
+	if len(runes) <= width {
+		return s
+	}
+	return string(runes[:width-1]) + "…"
+}
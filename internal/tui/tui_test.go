@@ -0,0 +1,98 @@
+package tui
+
+import (
+	"database/sql"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/google/uuid"
+	"github.com/o0n1x/gator/internal/database"
+)
+
+func TestPlainText(t *testing.T) {
+	cases := map[string]struct {
+		in  string
+		out string
+	}{
+		"plain":      {"Just   some\ntext", "Just some\ntext"},
+		"paragraphs": {"<p>First <b>bold</b> part.</p><p>Second</p>", "First bold part.\n\nSecond"},
+		"script":     {"<div>shown<script>hidden()</script></div>", "shown"},
+		"entities":   {"Tom &amp; Jerry<br>next", "Tom & Jerry\n\nnext"},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := plainText(tc.in)
+			if got != tc.out {
+				t.Errorf("plainText Mismatch wanted: %q , got: %q", tc.out, got)
+			}
+		})
+	}
+}
+
+func testPost(title string, read bool) database.BrowseRow {
+	return database.BrowseRow{
+		Post:     database.Post{ID: uuid.New(), Title: sql.NullString{String: title, Valid: true}},
+		FeedName: "Go Blog",
+		IsRead:   read,
+	}
+}
+
+func press(m model, keys ...string) (model, tea.Cmd) {
+	var cmd tea.Cmd
+	for _, key := range keys {
+		msg := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)}
+		switch key {
+		case "enter":
+			msg = tea.KeyMsg{Type: tea.KeyEnter}
+		case "tab":
+			msg = tea.KeyMsg{Type: tea.KeyTab}
+		}
+		var next tea.Model
+		next, cmd = m.Update(msg)
+		m = next.(model)
+	}
+	return m, cmd
+}
+
+func TestNavigation(t *testing.T) {
+	m := newModel(Options{})
+	next, _ := m.Update(postsLoadedMsg{[]database.BrowseRow{testPost("one", false), testPost("two", false), testPost("three", true)}})
+	m = next.(model)
+
+	m, _ = press(m, "l", "j", "j", "j")
+	if m.focus != postPane || m.postCursor != 2 {
+		t.Errorf("Navigation Mismatch wanted: pane %v cursor 2 , got: pane %v cursor %v", postPane, m.focus, m.postCursor)
+	}
+	m, _ = press(m, "k")
+	if m.postCursor != 1 {
+		t.Errorf("Navigation Mismatch wanted: cursor 1 , got: %v", m.postCursor)
+	}
+
+	m, cmd := press(m, "enter")
+	if m.focus != readPane || m.reading != 1 {
+		t.Errorf("Reading Mismatch wanted: pane %v post 1 , got: pane %v post %v", readPane, m.focus, m.reading)
+	}
+	if !m.posts[1].IsRead || cmd == nil {
+		t.Errorf("Reading a post did not mark it read")
+	}
+
+	m, _ = press(m, "r", "s")
+	if m.posts[1].IsRead || !m.posts[1].IsStarred {
+		t.Errorf("Toggle Mismatch wanted: unread and starred , got: read %v starred %v", m.posts[1].IsRead, m.posts[1].IsStarred)
+	}
+
+	m, _ = press(m, "h", "h")
+	if m.focus != feedPane {
+		t.Errorf("Navigation Mismatch wanted: pane %v , got: %v", feedPane, m.focus)
+	}
+}
+
+func TestSearchInput(t *testing.T) {
+	m := newModel(Options{})
+	m, _ = press(m, "/", "g", "o")
+	m, _ = press(m, " ")
+	m, cmd := press(m, "c", "v", "e", "enter")
+	if m.searching || m.query != "go cve" || cmd == nil {
+		t.Errorf("Search Mismatch wanted: %q , got: %q (searching %v)", "go cve", m.query, m.searching)
+	}
+}
@@ -0,0 +1,183 @@
+package urlnorm
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+)
+
+// DefaultTrackingParams are stripped from every URL unless the config provides its own list.
+// A trailing '*' matches any parameter with that prefix.
+var DefaultTrackingParams = []string{
+	"utm_*",
+	"fbclid",
+	"gclid",
+	"dclid",
+	"msclkid",
+	"mc_cid",
+	"mc_eid",
+	"igshid",
+	"yclid",
+	"_hsenc",
+	"_hsmi",
+	"ref",
+	"ref_src",
+}
+
+// DefaultRedirectHosts are hosts known to only redirect to the real article.
+var DefaultRedirectHosts = []string{
+	"feedproxy.google.com",
+	"feeds.feedburner.com",
+	"rss.feedsportal.com",
+	"t.co",
+	"bit.ly",
+	"ow.ly",
+}
+
+type Normalizer struct {
+	TrackingParams   []string
+	ResolveRedirects bool
+	RedirectHosts    []string
+	Client           *http.Client
+}
+
+func New(trackingParams []string, resolveRedirects bool) *Normalizer {
+	if trackingParams == nil {
+		trackingParams = DefaultTrackingParams
+	}
+	return &Normalizer{
+		TrackingParams:   trackingParams,
+		ResolveRedirects: resolveRedirects,
+		RedirectHosts:    DefaultRedirectHosts,
+		Client:           &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// Normalize returns the canonical form of raw: lowercase scheme and host, no default port,
+// no fragment, no tracking parameters and the remaining query sorted by key.
+// Urls that are not http(s), like file:// or exec://, are only trimmed.
+func (n *Normalizer) Normalize(raw string) (string, error) {
+	raw = strings.TrimSpace(raw)
+	if !isWeb(raw) {
+		if !strings.Contains(raw, "://") {
+			return "", errors.New("url must be absolute: " + raw)
+		}
+		return raw, nil
+	}
+	u, err := url.Parse(raw)
+	if err != nil {
+		return "", err
+	}
+	if u.Scheme == "" || u.Host == "" {
+		return "", errors.New("url must be absolute: " + raw)
+	}
+	u.Scheme = strings.ToLower(u.Scheme)
+	host := strings.ToLower(u.Hostname())
+	port := u.Port()
+	if (u.Scheme == "http" && port == "80") || (u.Scheme == "https" && port == "443") {
+		port = ""
+	}
+	if port != "" {
+		host = host + ":" + port
+	}
+	u.Host = host
+	u.Fragment = ""
+	u.RawFragment = ""
+	if u.Path == "" {
+		u.Path = "/"
+	}
+
+	query := u.Query()
+	for key := range query {
+		if n.isTracking(key) {
+			query.Del(key)
+		}
+	}
+	u.RawQuery = query.Encode()
+	u.ForceQuery = false
+
+	return u.String(), nil
+}
+
+// Key returns a comparison key for raw that also ignores the scheme and a trailing slash,
+// so http://x.com/feed/ and https://x.com/feed share the same key.
+func (n *Normalizer) Key(raw string) (string, error) {
+	normalized, err := n.Normalize(raw)
+	if err != nil {
+		return "", err
+	}
+	if !isWeb(normalized) {
+		return normalized, nil
+	}
+	u, err := url.Parse(normalized)
+	if err != nil {
+		return "", err
+	}
+	key := u.Host + strings.TrimRight(u.EscapedPath(), "/")
+	if u.RawQuery != "" {
+		key += "?" + u.RawQuery
+	}
+	return key, nil
+}
+
+// Resolve follows redirects for URLs on known redirector hosts and returns the final URL.
+// It returns raw unchanged when resolving is disabled or the host is not a redirector.
+func (n *Normalizer) Resolve(ctx context.Context, raw string) (string, error) {
+	if !n.ResolveRedirects {
+		return raw, nil
+	}
+	u, err := url.Parse(raw)
+	if err != nil {
+		return "", err
+	}
+	if !slices.Contains(n.RedirectHosts, strings.ToLower(u.Hostname())) {
+		return raw, nil
+	}
+	req, err := http.NewRequestWithContext(ctx, "HEAD", raw, nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("User-Agent", "gator")
+	resp, err := n.Client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	return resp.Request.URL.String(), nil
+}
+
+// Canonical resolves redirects when enabled and then normalizes the result.
+func (n *Normalizer) Canonical(ctx context.Context, raw string) (string, error) {
+	resolved, err := n.Resolve(ctx, raw)
+	if err != nil {
+		// an unreachable redirector should not lose the post, keep the original link
+		resolved = raw
+	}
+	return n.Normalize(resolved)
+}
+
+func isWeb(raw string) bool {
+	lower := strings.ToLower(raw)
+	return strings.HasPrefix(lower, "http://") || strings.HasPrefix(lower, "https://")
+}
+
+func (n *Normalizer) isTracking(key string) bool {
+	key = strings.ToLower(key)
+	for _, param := range n.TrackingParams {
+		param = strings.ToLower(param)
+		if prefix, ok := strings.CutSuffix(param, "*"); ok {
+			if strings.HasPrefix(key, prefix) {
+				return true
+			}
+			continue
+		}
+		if key == param {
+			return true
+		}
+	}
+	return false
+}
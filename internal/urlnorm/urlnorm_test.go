@@ -0,0 +1,74 @@
+package urlnorm
+
+import "testing"
+
+func TestNormalize(t *testing.T) {
+	cases := map[string]struct {
+		in  string
+		out string
+	}{
+		"host case and default port": {"HTTPS://Example.COM:443/Feed", "https://example.com/Feed"},
+		"fragment":                   {"https://example.com/a#comments", "https://example.com/a"},
+		"tracking params":            {"https://example.com/a?utm_source=x&id=3&fbclid=y&ref=rss", "https://example.com/a?id=3"},
+		"sorted query":               {"https://example.com/a?b=2&a=1", "https://example.com/a?a=1&b=2"},
+		"empty path":                 {"http://example.com", "http://example.com/"},
+		"custom port kept":           {"http://example.com:8080/rss", "http://example.com:8080/rss"},
+		"local file untouched":       {" file:///tmp/Feed.xml#x ", "file:///tmp/Feed.xml#x"},
+	}
+
+	n := New(nil, false)
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := n.Normalize(tc.in)
+			if err != nil {
+				t.Errorf("Normalize Failed %v", err)
+				return
+			}
+			if got != tc.out {
+				t.Errorf("Normalize Mismatch wanted: %v , got: %v", tc.out, got)
+			}
+		})
+	}
+}
+
+func TestKey(t *testing.T) {
+	cases := map[string]struct {
+		a string
+		b string
+	}{
+		"scheme and trailing slash": {"http://x.com/feed/", "https://x.com/feed"},
+		"tracking params":           {"https://x.com/feed?utm_medium=rss", "https://x.com/feed"},
+		"default port":              {"http://X.com:80/feed", "http://x.com/feed"},
+	}
+
+	n := New(nil, false)
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			a, err := n.Key(tc.a)
+			if err != nil {
+				t.Errorf("Key Failed %v", err)
+				return
+			}
+			b, err := n.Key(tc.b)
+			if err != nil {
+				t.Errorf("Key Failed %v", err)
+				return
+			}
+			if a != b {
+				t.Errorf("Key Mismatch %v != %v", a, b)
+			}
+		})
+	}
+}
+
+func TestCustomTrackingParams(t *testing.T) {
+	n := New([]string{"campaign"}, false)
+	got, err := n.Normalize("https://example.com/a?campaign=x&utm_source=y")
+	if err != nil {
+		t.Errorf("Normalize Failed %v", err)
+		return
+	}
+	if got != "https://example.com/a?utm_source=y" {
+		t.Errorf("Normalize Mismatch got: %v", got)
+	}
+}
@@ -0,0 +1,304 @@
+package watch
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// contextLines is the number of unchanged lines shown around every change of a diff.
+const contextLines = 3
+
+// maxEdits bounds the work of a diff, pages with more changed lines than this
+// are diffed as replaced: every old line deleted and every new one inserted.
+const maxEdits = 1000
+
+var skippedTags = map[atom.Atom]bool{
+	atom.Script: true, atom.Style: true, atom.Noscript: true, atom.Template: true, atom.Svg: true,
+}
+
+var inlineTags = map[atom.Atom]bool{
+	atom.A: true, atom.Abbr: true, atom.B: true, atom.Bdi: true, atom.Cite: true, atom.Code: true,
+	atom.Em: true, atom.I: true, atom.Kbd: true, atom.Mark: true, atom.Q: true, atom.S: true,
+	atom.Small: true, atom.Span: true, atom.Strong: true, atom.Sub: true, atom.Sup: true,
+	atom.Time: true, atom.U: true, atom.Var: true,
+}
+
+// Text returns the normalized text of an html page, one line per block element with
+// whitespace collapsed. When selector is set only the matching elements are kept.
+func Text(r io.Reader, selector string) (string, error) {
+	doc, err := goquery.NewDocumentFromReader(r)
+	if err != nil {
+		return "", err
+	}
+	root := doc.Find("body")
+	if selector != "" {
+		root = doc.Find(selector)
+		if root.Length() == 0 {
+			return "", fmt.Errorf("selector %v matched nothing", selector)
+		}
+	}
+	if root.Length() == 0 {
+		return "", errors.New("page has no body")
+	}
+
+	var b strings.Builder
+	for _, n := range root.Nodes {
+		writeText(&b, n)
+		b.WriteString("\n")
+	}
+
+	var lines []string
+	for _, line := range strings.Split(b.String(), "\n") {
+		line = strings.Join(strings.Fields(line), " ")
+		if line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+func writeText(b *strings.Builder, n *html.Node) {
+	switch n.Type {
+	case html.TextNode:
+		b.WriteString(n.Data)
+		return
+	case html.ElementNode:
+		if skippedTags[n.DataAtom] {
+			return
+		}
+	}
+	block := n.Type == html.ElementNode && !inlineTags[n.DataAtom]
+	if block {
+		b.WriteString("\n")
+	}
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		writeText(b, c)
+	}
+	if block {
+		b.WriteString("\n")
+	}
+}
+
+type opKind int
+
+const (
+	opEqual opKind = iota
+	opDelete
+	opInsert
+)
+
+type op struct {
+	kind opKind
+	line string
+}
+
+// ChangeRatio returns the share of lines that differ between old and new, from 0 to 1.
+func ChangeRatio(old, new string) float64 {
+	a, b := splitLines(old), splitLines(new)
+	if len(a)+len(b) == 0 {
+		return 0
+	}
+	changed := 0
+	for _, o := range diffLines(a, b) {
+		if o.kind != opEqual {
+			changed++
+		}
+	}
+	return float64(changed) / float64(len(a)+len(b))
+}
+
+// Diff returns a unified diff from old to new, or an empty string when they are equal.
+func Diff(old, new, oldName, newName string) string {
+	ops := diffLines(splitLines(old), splitLines(new))
+
+	var hunks strings.Builder
+	aLine, bLine := 1, 1
+	for i := 0; i < len(ops); {
+		if ops[i].kind == opEqual {
+			i++
+			aLine++
+			bLine++
+			continue
+		}
+
+		// widen the hunk backwards for context and forwards until a long equal run
+		start := max(i-contextLines, 0)
+		end := i
+		for end < len(ops) {
+			if ops[end].kind != opEqual {
+				end++
+				continue
+			}
+			run := end
+			for run < len(ops) && ops[run].kind == opEqual {
+				run++
+			}
+			if run == len(ops) || run-end > 2*contextLines {
+				end = min(end+contextLines, run)
+				break
+			}
+			end = run
+		}
+
+		aStart, bStart := aLine-(i-start), bLine-(i-start)
+		aCount, bCount := 0, 0
+		var body strings.Builder
+		for _, o := range ops[start:end] {
+			switch o.kind {
+			case opEqual:
+				body.WriteString(" " + o.line + "\n")
+				aCount++
+				bCount++
+			case opDelete:
+				body.WriteString("-" + o.line + "\n")
+				aCount++
+			case opInsert:
+				body.WriteString("+" + o.line + "\n")
+				bCount++
+			}
+		}
+		fmt.Fprintf(&hunks, "@@ -%v +%v @@\n", hunkRange(aStart, aCount), hunkRange(bStart, bCount))
+		hunks.WriteString(body.String())
+
+		for _, o := range ops[i:end] {
+			if o.kind != opInsert {
+				aLine++
+			}
+			if o.kind != opDelete {
+				bLine++
+			}
+		}
+		i = end
+	}
+
+	if hunks.Len() == 0 {
+		return ""
+	}
+	return fmt.Sprintf("--- %v\n+++ %v\n%v", oldName, newName, hunks.String())
+}
+
+func hunkRange(start, count int) string {
+	if count == 0 {
+		return fmt.Sprintf("%v,0", start-1)
+	}
+	if count == 1 {
+		return fmt.Sprintf("%v", start)
+	}
+	return fmt.Sprintf("%v,%v", start, count)
+}
+
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(s, "\n")
+}
+
+// diffLines returns the edit script from a to b. The lines a and b start and
+// end with are equal, the lines between are diffed by shortestEdit.
+func diffLines(a, b []string) []op {
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	var ops []op
+	for _, line := range a[:prefix] {
+		ops = append(ops, op{opEqual, line})
+	}
+	ops = append(ops, shortestEdit(a[prefix:len(a)-suffix], b[prefix:len(b)-suffix])...)
+	for _, line := range a[len(a)-suffix:] {
+		ops = append(ops, op{opEqual, line})
+	}
+	return ops
+}
+
+// shortestEdit returns the shortest edit script from a to b with the greedy
+// algorithm of Myers, "An O(ND) Difference Algorithm and Its Variations". It
+// takes O((N+M)D) time and O(D²) memory for D edits, past maxEdits edits a
+// is replaced by b instead.
+func shortestEdit(a, b []string) []op {
+	n, m := len(a), len(b)
+	limit := min(n+m, maxEdits)
+	// v holds the furthest x reached on every diagonal k = x - y, trace the
+	// diagonals -d..d of v after every step d
+	offset := limit + 1
+	v := make([]int, 2*limit+3)
+	var trace [][]int
+	for d := 0; d <= limit; d++ {
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+				x = v[offset+k+1]
+			} else {
+				x = v[offset+k-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			v[offset+k] = x
+			if x >= n && y >= m {
+				return editScript(a, b, trace, d)
+			}
+		}
+		trace = append(trace, append([]int(nil), v[offset-d:offset+d+1]...))
+	}
+
+	ops := make([]op, 0, n+m)
+	for _, line := range a {
+		ops = append(ops, op{opDelete, line})
+	}
+	for _, line := range b {
+		ops = append(ops, op{opInsert, line})
+	}
+	return ops
+}
+
+// editScript walks the trace of shortestEdit back from the end of a and b,
+// reached after d edits.
+func editScript(a, b []string, trace [][]int, d int) []op {
+	var ops []op
+	x, y := len(a), len(b)
+	for ; d > 0; d-- {
+		prev := trace[d-1]
+		// prev holds the diagonals -(d-1)..d-1
+		at := func(k int) int { return prev[k+d-1] }
+		k := x - y
+		prevK := k - 1
+		if k == -d || (k != d && at(k-1) < at(k+1)) {
+			prevK = k + 1
+		}
+		prevX := at(prevK)
+		prevY := prevX - prevK
+		for x > prevX && y > prevY {
+			ops = append(ops, op{opEqual, a[x-1]})
+			x--
+			y--
+		}
+		if x == prevX {
+			ops = append(ops, op{opInsert, b[y-1]})
+			y--
+		} else {
+			ops = append(ops, op{opDelete, a[x-1]})
+			x--
+		}
+	}
+	for ; x > 0 && y > 0; x, y = x-1, y-1 {
+		ops = append(ops, op{opEqual, a[x-1]})
+	}
+	slices.Reverse(ops)
+	return ops
+}
@@ -0,0 +1,145 @@
+package watch
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestText(t *testing.T) {
+	page := `<html><head><style>p{}</style></head><body>
+<nav>Home | Docs</nav>
+<div id="changelog">
+  <h2>v1.2.0</h2>
+  <ul><li>Fixed   <b>login</b> bug</li><li>Faster sync</li></ul>
+  <script>track()</script>
+</div>
+</body></html>`
+
+	cases := map[string]struct {
+		selector string
+		out      string
+	}{
+		"whole page": {"", "Home | Docs\nv1.2.0\nFixed login bug\nFaster sync"},
+		"selector":   {"#changelog", "v1.2.0\nFixed login bug\nFaster sync"},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := Text(strings.NewReader(page), tc.selector)
+			if err != nil {
+				t.Errorf("Text Failed %v", err)
+				return
+			}
+			if got != tc.out {
+				t.Errorf("Text Mismatch wanted: %q , got: %q", tc.out, got)
+			}
+		})
+	}
+}
+
+func TestDiff(t *testing.T) {
+	old := "a\nb\nc\nd\ne\nf\ng\nh\ni\nj\nk\nl"
+	new := "a\nB\nc\nd\ne\nf\ng\nh\ni\nj\nk\nl\nm"
+
+	want := `--- old
++++ new
+@@ -1,5 +1,5 @@
+ a
+-b
++B
+ c
+ d
+ e
+@@ -10,3 +10,4 @@
+ j
+ k
+ l
++m
+`
+	got := Diff(old, new, "old", "new")
+	if got != want {
+		t.Errorf("Diff Mismatch wanted:\n%v\ngot:\n%v", want, got)
+	}
+	if Diff(old, old, "old", "new") != "" {
+		t.Errorf("expected no diff for equal text")
+	}
+}
+
+func TestChangeRatio(t *testing.T) {
+	cases := map[string]struct {
+		old, new string
+		ratio    float64
+	}{
+		"equal":     {"a\nb", "a\nb", 0},
+		"one line":  {"a\nb", "a\nc", 0.5},
+		"all new":   {"", "a\nb", 1},
+		"both none": {"", "", 0},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if got := ChangeRatio(tc.old, tc.new); got != tc.ratio {
+				t.Errorf("ChangeRatio Mismatch wanted: %v , got: %v", tc.ratio, got)
+			}
+		})
+	}
+}
+
+func TestDiffLines(t *testing.T) {
+	page := func(n int, changed ...int) []string {
+		lines := make([]string, n)
+		for i := range lines {
+			lines[i] = fmt.Sprintf("line %v", i)
+		}
+		for _, i := range changed {
+			lines[i] = fmt.Sprintf("changed %v", i)
+		}
+		return lines
+	}
+	replaced := page(20000)
+	for i := range replaced {
+		replaced[i] = fmt.Sprintf("new %v", i)
+	}
+	var scattered []int
+	for i := 0; i < 20000; i += 10 {
+		scattered = append(scattered, i)
+	}
+
+	cases := map[string]struct {
+		a, b  []string
+		edits int
+	}{
+		"equal":            {a: page(3), b: page(3), edits: 0},
+		"moved line":       {a: []string{"a", "b", "c", "d"}, b: []string{"b", "c", "a", "d"}, edits: 2},
+		"interleaved":      {a: []string{"a", "b", "c", "a", "b", "b", "a"}, b: []string{"c", "b", "a", "b", "a", "c"}, edits: 5},
+		"large few edits":  {a: page(20000), b: page(20000, 100, 5000, 19999), edits: 6},
+		"large all edited": {a: page(20000), b: replaced, edits: 40000},
+		"large appended":   {a: page(20000), b: page(25000), edits: 5000},
+		// past maxEdits everything between the equal first and last lines is replaced
+		"large past limit": {a: page(20000), b: page(20000, scattered...), edits: 2 * (20000 - 9)},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			ops := diffLines(tc.a, tc.b)
+			var a, b []string
+			edits := 0
+			for _, o := range ops {
+				if o.kind != opInsert {
+					a = append(a, o.line)
+				}
+				if o.kind != opDelete {
+					b = append(b, o.line)
+				}
+				if o.kind != opEqual {
+					edits++
+				}
+			}
+			if !slices.Equal(a, tc.a) || !slices.Equal(b, tc.b) {
+				t.Errorf("diffLines Failed, the edit script does not turn a into b")
+			}
+			if edits != tc.edits {
+				t.Errorf("diffLines Mismatch wanted: %v edits , got: %v", tc.edits, edits)
+			}
+		})
+	}
+}
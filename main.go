@@ -1,11 +1,9 @@
 package main
 
 import (
-	"database/sql"
 	"fmt"
 	"os"
 
-	_ "github.com/lib/pq"
 	"github.com/o0n1x/gator/internal/cli"
 	"github.com/o0n1x/gator/internal/config"
 	"github.com/o0n1x/gator/internal/database"
@@ -20,44 +18,31 @@ func main() {
 		os.Exit(1)
 	}
 
-	db, err := sql.Open("postgres", cnfg.DB_URL)
+	db, err := database.Open(cnfg.DB_URL)
 	if err != nil {
 		fmt.Printf("DB Error: %v\n", err)
 		os.Exit(1)
 	}
-	dbQueries := database.New(db)
+	dbQueries := database.NewStore(db)
 
 	state := cli.State{
 		State: &cnfg,
 		DB:    dbQueries,
+		Conn:  db,
 	}
 
 	//commands
-	commands := cli.Commands{
-		Commands: make(map[string]func(*cli.State, cli.Command) error),
-	}
-	commands.Register("login", cli.HandlerLogin)
-	commands.Register("register", cli.HandlerRegister)
-	commands.Register("reset", cli.HandlerReset)
-	commands.Register("users", cli.HandlerUsers)
-	commands.Register("agg", cli.HandlerAgg)
-	commands.Register("addfeed", cli.MiddlewareLoggedIn(cli.HandlerAddFeed))
-	commands.Register("feeds", cli.HandlerFeeds)
-	commands.Register("follow", cli.MiddlewareLoggedIn(cli.HandlerFollow))
-	commands.Register("following", cli.MiddlewareLoggedIn(cli.HandlerFollowing))
-	commands.Register("unfollow", cli.MiddlewareLoggedIn(cli.HandlerUnfollow))
-	commands.Register("browse", cli.MiddlewareLoggedIn(cli.HandlerBrowse))
+	commands := cli.NewCommands()
 
 	//command executing
-	if len(os.Args) < 2 {
-		fmt.Printf("Error: %v\n", "Invalid input No arguments")
-		os.Exit(1)
+	cmd := cli.Command{}
+	if len(os.Args) > 1 {
+		cmd = cli.Command{Name: os.Args[1], Args: os.Args[2:]}
 	}
-
-	err = commands.Run(&state, cli.Command{Name: os.Args[1], Args: os.Args[2:]})
+	err = commands.Run(&state, cmd)
 	if err != nil {
 		fmt.Printf("Error: %v\n", err)
-		os.Exit(1)
+		os.Exit(cli.ExitCode(err))
 	}
 
 }
@@ -1,11 +1,48 @@
 -- name: CreateFeed :one
-INSERT INTO feeds (id, created_at, updated_at, name, url , user_id)
+INSERT INTO feeds (id, created_at, updated_at, name, url , user_id, url_key, kind)
 VALUES (
     $1,
     $2,
     $3,
     $4,
     $5,
-    $6
+    $6,
+    $7,
+    $8
 )
-RETURNING *;
\ No newline at end of file
+RETURNING *;
+
+-- name: SetFeedSiteURL :exec
+UPDATE feeds
+SET site_url = $2, updated_at = $3
+WHERE id = $1;
+
+-- name: RenameFeed :execrows
+UPDATE feeds
+SET name = $2, updated_at = $3
+WHERE id = $1;
+
+-- name: SetFeedURL :execrows
+UPDATE feeds
+SET url = $2, url_key = $3, updated_at = $4
+WHERE id = $1;
+
+-- name: SetFeedOwner :execrows
+UPDATE feeds
+SET user_id = $2, updated_at = $3
+WHERE id = $1;
+
+-- name: DeleteFeed :execrows
+DELETE FROM feeds
+WHERE id = $1;
+
+-- name: DeleteFeeds :exec
+DELETE FROM feeds;
+
+-- name: DeleteUnsharedFeeds :execrows
+-- the feeds of a user that nobody else follows
+DELETE FROM feeds
+WHERE feeds.user_id = sqlc.arg(user_id)::uuid AND NOT EXISTS (
+    SELECT 1 FROM feed_follows
+    WHERE feed_follows.feed_id = feeds.id AND feed_follows.user_id <> sqlc.arg(user_id)::uuid
+);
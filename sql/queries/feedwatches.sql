@@ -0,0 +1,12 @@
+-- name: CreateFeedWatch :one
+INSERT INTO feed_watches (feed_id, selector, threshold)
+VALUES (
+    $1,
+    $2,
+    $3
+)
+RETURNING *;
+
+-- name: GetFeedWatch :one
+SELECT * FROM feed_watches
+WHERE feed_id = $1;
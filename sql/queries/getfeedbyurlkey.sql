@@ -0,0 +1,8 @@
+-- name: GetFeedByURLKey :one
+SELECT * FROM feeds
+WHERE url_key = $1;
+
+-- name: LockFeedByURLKey :one
+SELECT * FROM feeds
+WHERE url_key = $1
+FOR UPDATE;
@@ -1,5 +1,7 @@
 -- name: GetFeedFollowsForUser :many
-SELECT feed_follows.*,users.name AS user_name ,feeds.name AS feed_name FROM feed_follows
+SELECT feed_follows.*,users.name AS user_name ,feeds.name AS feed_name, feeds.url AS feed_url, feeds.site_url AS feed_site_url, folders.name AS folder_name FROM feed_follows
 INNER JOIN users ON feed_follows.user_id = users.id 
 INNER JOIN feeds ON feed_follows.feed_id = feeds.id
-WHERE feed_follows.user_id = $1;
\ No newline at end of file
+LEFT JOIN folders ON feed_follows.folder_id = folders.id
+WHERE feed_follows.user_id = $1
+ORDER BY folders.name ASC NULLS FIRST, feeds.name ASC;
@@ -0,0 +1,13 @@
+-- name: GetPostsByIDPrefix :many
+-- the wildcards of the prefix are taken literally, posts are found in the
+-- followed feeds of the user and among the posts the user starred or queued
+SELECT sqlc.embed(posts), feeds.name AS feed_name
+FROM posts
+LEFT JOIN feeds ON posts.feed_id = feeds.id
+WHERE posts.id::text LIKE replace(replace(replace(sqlc.arg(prefix)::text, '\', '\\'), '%', '\%'), '_', '\_') || '%' ESCAPE '\'
+    AND (
+        EXISTS (SELECT 1 FROM feed_follows WHERE feed_follows.feed_id = posts.feed_id AND feed_follows.user_id = sqlc.arg(user_id)::uuid)
+        OR EXISTS (SELECT 1 FROM post_stars WHERE post_stars.post_id = posts.id AND post_stars.user_id = sqlc.arg(user_id)::uuid)
+        OR EXISTS (SELECT 1 FROM read_later WHERE read_later.post_id = posts.id AND read_later.user_id = sqlc.arg(user_id)::uuid)
+    )
+LIMIT 2;
@@ -0,0 +1,15 @@
+-- name: CreatePageSnapshot :one
+INSERT INTO page_snapshots (id, created_at, feed_id, content)
+VALUES (
+    $1,
+    $2,
+    $3,
+    $4
+)
+RETURNING *;
+
+-- name: GetLatestPageSnapshot :one
+SELECT * FROM page_snapshots
+WHERE feed_id = $1
+ORDER BY created_at DESC
+LIMIT 1;
@@ -1,5 +1,5 @@
 -- name: CreatePost :one
-INSERT INTO posts (id, created_at, updated_at, title, url, description, published_at, feed_id)
+INSERT INTO posts (id, created_at, updated_at, title, url, description, published_at, feed_id, author)
 VALUES (
     $1,
     $2,
@@ -8,6 +8,8 @@ VALUES (
     $5,
     $6,
     $7,
-    $8
+    $8,
+    $9
 )
+ON CONFLICT (url) DO NOTHING
 RETURNING *;
\ No newline at end of file
@@ -0,0 +1,5 @@
+-- name: PruneOrphanedPosts :execrows
+DELETE FROM posts
+WHERE posts.feed_id IS NULL
+    AND NOT EXISTS (SELECT 1 FROM post_stars WHERE post_stars.post_id = posts.id)
+    AND NOT EXISTS (SELECT 1 FROM read_later WHERE read_later.post_id = posts.id);
@@ -0,0 +1,19 @@
+-- name: SearchPosts :many
+SELECT sqlc.embed(posts),
+    COALESCE(feed_follows.display_name, feeds.name, '')::text AS feed_name,
+    ts_rank(posts.search_vector, websearch_to_tsquery('english', sqlc.arg(query)))::real AS rank,
+    ts_headline('english',
+        COALESCE(NULLIF(posts.content, ''), NULLIF(posts.description, ''), posts.title, ''),
+        websearch_to_tsquery('english', sqlc.arg(query)),
+        'StartSel=[[, StopSel=]], MaxWords=30, MinWords=10, MaxFragments=2'
+    )::text AS snippet
+FROM posts
+LEFT JOIN feeds ON posts.feed_id = feeds.id
+LEFT JOIN feed_follows ON feed_follows.feed_id = posts.feed_id AND feed_follows.user_id = sqlc.arg(user_id)
+WHERE posts.search_vector @@ websearch_to_tsquery('english', sqlc.arg(query))
+    AND (sqlc.arg(all_feeds)::boolean OR feed_follows.user_id IS NOT NULL)
+    AND (sqlc.narg(feed_id)::uuid IS NULL OR posts.feed_id = sqlc.narg(feed_id))
+    AND (sqlc.narg(since)::timestamp IS NULL OR posts.published_at >= sqlc.narg(since))
+    AND (sqlc.narg(until)::timestamp IS NULL OR posts.published_at < sqlc.narg(until))
+ORDER BY rank DESC, posts.published_at DESC
+LIMIT sqlc.arg(max_posts);
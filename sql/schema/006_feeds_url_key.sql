@@ -0,0 +1,13 @@
+-- +goose Up
+ALTER TABLE feeds
+ADD COLUMN url_key TEXT;
+
+-- gator keys the urls in go with urlnorm, the keys it looks feeds up by
+-- +gator go backfill_url_keys
+
+ALTER TABLE feeds
+ADD CONSTRAINT feeds_url_key_key UNIQUE (url_key);
+
+-- +goose Down
+ALTER TABLE feeds
+DROP COLUMN url_key;
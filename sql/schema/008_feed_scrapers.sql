@@ -0,0 +1,20 @@
+-- +goose Up
+ALTER TABLE feeds
+ADD COLUMN kind TEXT NOT NULL DEFAULT 'rss';
+
+CREATE TABLE feed_scrapers(
+    feed_id UUID PRIMARY KEY,
+    FOREIGN KEY(feed_id) REFERENCES feeds (id) ON DELETE CASCADE,
+    item_selector TEXT NOT NULL,
+    title_selector TEXT NOT NULL,
+    link_selector TEXT NOT NULL,
+    date_selector TEXT,
+    date_format TEXT,
+    summary_selector TEXT
+);
+
+-- +goose Down
+DROP TABLE feed_scrapers;
+
+ALTER TABLE feeds
+DROP COLUMN kind;
@@ -0,0 +1,21 @@
+-- +goose Up
+CREATE TABLE feed_watches(
+    feed_id UUID PRIMARY KEY,
+    FOREIGN KEY(feed_id) REFERENCES feeds (id) ON DELETE CASCADE,
+    selector TEXT,
+    threshold DOUBLE PRECISION NOT NULL DEFAULT 0
+);
+
+CREATE TABLE page_snapshots(
+    id UUID PRIMARY KEY,
+    created_at TIMESTAMP NOT NULL,
+    feed_id UUID NOT NULL,
+    FOREIGN KEY(feed_id) REFERENCES feeds (id) ON DELETE CASCADE,
+    content TEXT NOT NULL
+);
+
+CREATE INDEX page_snapshots_feed_id_created_at_idx ON page_snapshots (feed_id, created_at DESC);
+
+-- +goose Down
+DROP TABLE page_snapshots;
+DROP TABLE feed_watches;
@@ -0,0 +1,34 @@
+-- +goose Up
+CREATE TABLE post_stars(
+    user_id UUID NOT NULL,
+    FOREIGN KEY(user_id) REFERENCES users (id) ON DELETE CASCADE,
+    post_id UUID NOT NULL,
+    FOREIGN KEY(post_id) REFERENCES posts (id) ON DELETE CASCADE,
+    starred_at TIMESTAMP NOT NULL,
+    PRIMARY KEY(user_id, post_id)
+);
+
+CREATE TABLE read_later(
+    user_id UUID NOT NULL,
+    FOREIGN KEY(user_id) REFERENCES users (id) ON DELETE CASCADE,
+    post_id UUID NOT NULL,
+    FOREIGN KEY(post_id) REFERENCES posts (id) ON DELETE CASCADE,
+    position INTEGER NOT NULL,
+    added_at TIMESTAMP NOT NULL,
+    PRIMARY KEY(user_id, post_id)
+);
+
+-- posts outlive their feed so starred and queued posts survive feed deletion
+ALTER TABLE posts
+DROP CONSTRAINT posts_feed_id_fkey,
+ADD CONSTRAINT posts_feed_id_fkey FOREIGN KEY(feed_id) REFERENCES feeds (id) ON DELETE SET NULL;
+
+-- +goose Down
+DELETE FROM posts WHERE feed_id IS NULL;
+
+ALTER TABLE posts
+DROP CONSTRAINT posts_feed_id_fkey,
+ADD CONSTRAINT posts_feed_id_fkey FOREIGN KEY(feed_id) REFERENCES feeds (id);
+
+DROP TABLE read_later;
+DROP TABLE post_stars;
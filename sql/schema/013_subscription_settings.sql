@@ -0,0 +1,35 @@
+-- +goose Up
+ALTER TABLE feed_follows
+ADD COLUMN display_name TEXT,
+ADD COLUMN hidden BOOLEAN NOT NULL DEFAULT false,
+ADD COLUMN notify BOOLEAN NOT NULL DEFAULT false,
+ADD COLUMN full_text BOOLEAN NOT NULL DEFAULT false;
+
+ALTER TABLE users
+ADD COLUMN default_sort TEXT NOT NULL DEFAULT 'newest';
+
+-- full content moves from the feed to the subscriptions of its followers
+UPDATE feed_follows
+SET full_text = true
+FROM feeds
+WHERE feeds.id = feed_follows.feed_id AND feeds.fetch_full_content;
+
+ALTER TABLE feeds
+DROP COLUMN fetch_full_content;
+
+-- +goose Down
+ALTER TABLE feeds
+ADD COLUMN fetch_full_content BOOLEAN NOT NULL DEFAULT false;
+
+UPDATE feeds
+SET fetch_full_content = true
+WHERE EXISTS (SELECT 1 FROM feed_follows WHERE feed_follows.feed_id = feeds.id AND feed_follows.full_text);
+
+ALTER TABLE users
+DROP COLUMN default_sort;
+
+ALTER TABLE feed_follows
+DROP COLUMN full_text,
+DROP COLUMN notify,
+DROP COLUMN hidden,
+DROP COLUMN display_name;
@@ -0,0 +1,14 @@
+-- +goose Up
+CREATE TABLE saved_searches(
+    id UUID PRIMARY KEY,
+    created_at TIMESTAMP,
+    updated_at TIMESTAMP,
+    user_id UUID NOT NULL,
+    FOREIGN KEY(user_id) REFERENCES users (id) ON DELETE CASCADE,
+    name TEXT NOT NULL,
+    query TEXT NOT NULL,
+    UNIQUE(user_id, name)
+);
+
+-- +goose Down
+DROP TABLE saved_searches;
@@ -0,0 +1,25 @@
+-- +goose Up
+-- a feed stays with its followers when the user that added it is deleted
+ALTER TABLE feeds
+DROP CONSTRAINT feeds_user_id_fkey;
+
+ALTER TABLE feeds
+ADD CONSTRAINT feeds_user_id_fkey FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE SET NULL;
+
+ALTER TABLE users
+ADD COLUMN is_admin BOOLEAN NOT NULL DEFAULT false;
+
+-- the first user set gator up
+UPDATE users
+SET is_admin = true
+WHERE id = (SELECT id FROM users ORDER BY created_at ASC NULLS LAST LIMIT 1);
+
+-- +goose Down
+ALTER TABLE users
+DROP COLUMN is_admin;
+
+ALTER TABLE feeds
+DROP CONSTRAINT feeds_user_id_fkey;
+
+ALTER TABLE feeds
+ADD CONSTRAINT feeds_user_id_fkey FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE;
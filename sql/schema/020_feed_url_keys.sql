@@ -0,0 +1,7 @@
+-- +goose Up
+-- 006 used to key the urls in sql, which did not match the keys gator looks
+-- feeds up by. This keys them again with urlnorm.
+-- +gator go backfill_url_keys
+
+-- +goose Down
+-- the new keys stay, they are the ones gator looks feeds up by
@@ -0,0 +1,10 @@
+// Package schema embeds the migrations of the database so gator can apply
+// them itself, see internal/migrate.
+package schema
+
+import "embed"
+
+// FS holds the migrations, NNN_name.sql files with goose Up and Down sections.
+//
+//go:embed *.sql
+var FS embed.FS
@@ -0,0 +1,13 @@
+-- name: CreateFeedFollow :one
+-- sqlite has no INSERT in WITH, the names come from subqueries of RETURNING
+INSERT INTO feed_follows (id, created_at, updated_at, user_id, feed_id)
+VALUES (
+    ?1,
+    ?2,
+    ?3,
+    ?4,
+    ?5
+)
+RETURNING id, created_at, updated_at, user_id, feed_id, folder_id, display_name, hidden, notify, full_text, default_sort,
+    (SELECT users.name FROM users WHERE users.id = feed_follows.user_id) AS user_name,
+    (SELECT feeds.name FROM feeds WHERE feeds.id = feed_follows.feed_id) AS feed_name;
@@ -0,0 +1,3 @@
+-- name: DeleteFeedFollow :exec
+DELETE FROM feed_follows
+WHERE user_id = ?1 AND feed_id = ?2;
\ No newline at end of file
@@ -0,0 +1,2 @@
+-- name: DeleteUsers :exec
+DELETE FROM users;
\ No newline at end of file
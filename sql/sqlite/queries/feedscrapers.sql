@@ -0,0 +1,16 @@
+-- name: CreateFeedScraper :one
+INSERT INTO feed_scrapers (feed_id, item_selector, title_selector, link_selector, date_selector, date_format, summary_selector)
+VALUES (
+    ?1,
+    ?2,
+    ?3,
+    ?4,
+    ?5,
+    ?6,
+    ?7
+)
+RETURNING *;
+
+-- name: GetFeedScraper :one
+SELECT * FROM feed_scrapers
+WHERE feed_id = ?1;
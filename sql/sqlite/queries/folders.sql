@@ -0,0 +1,33 @@
+-- name: CreateFolder :one
+INSERT INTO folders (id, created_at, updated_at, user_id, name)
+VALUES (
+    ?1,
+    ?2,
+    ?3,
+    ?4,
+    ?5
+)
+RETURNING *;
+
+-- name: GetFolderByName :one
+SELECT * FROM folders
+WHERE user_id = ?1 AND name = ?2;
+
+-- name: GetFoldersForUser :many
+SELECT * FROM folders
+WHERE user_id = ?1
+ORDER BY name ASC;
+
+-- name: RenameFolder :execrows
+UPDATE folders
+SET name = sqlc.arg(new_name), updated_at = sqlc.arg(updated_at)
+WHERE user_id = sqlc.arg(user_id) AND name = sqlc.arg(name);
+
+-- name: DeleteFolder :execrows
+DELETE FROM folders
+WHERE user_id = ?1 AND name = ?2;
+
+-- name: SetFeedFollowFolder :execrows
+UPDATE feed_follows
+SET folder_id = ?3, updated_at = ?4
+WHERE user_id = ?1 AND feed_id = ?2;
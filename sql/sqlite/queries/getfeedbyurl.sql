@@ -0,0 +1,3 @@
+-- name: GetFeedByURL :one
+SELECT * FROM feeds
+WHERE url = ?1;
\ No newline at end of file
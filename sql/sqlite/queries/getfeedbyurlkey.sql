@@ -0,0 +1,8 @@
+-- name: GetFeedByURLKey :one
+SELECT * FROM feeds
+WHERE url_key = ?1;
+
+-- name: LockFeedByURLKey :one
+-- sqlite has no FOR UPDATE, its transactions take the write lock when they begin.
+SELECT * FROM feeds
+WHERE url_key = ?1;
@@ -0,0 +1,2 @@
+-- name: GetFeeds :many
+SELECT * FROM feeds;
\ No newline at end of file
@@ -0,0 +1,5 @@
+-- name: GetNextFeedToFetch :one
+
+SELECT * FROM feeds
+ORDER BY last_fetched_at ASC NULLS FIRST;
+
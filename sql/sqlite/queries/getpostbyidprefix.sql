@@ -0,0 +1,13 @@
+-- name: GetPostsByIDPrefix :many
+-- the wildcards of the prefix are taken literally, posts are found in the
+-- followed feeds of the user and among the posts the user starred or queued.
+-- sqlc leaves sqlc.arg in EXISTS subqueries as it is, joins find them here.
+SELECT sqlc.embed(posts), feeds.name AS feed_name
+FROM posts
+LEFT JOIN feeds ON posts.feed_id = feeds.id
+LEFT JOIN post_stars ON post_stars.post_id = posts.id AND post_stars.user_id = sqlc.arg(user_id)
+LEFT JOIN read_later ON read_later.post_id = posts.id AND read_later.user_id = sqlc.arg(user_id)
+LEFT JOIN feed_follows ON feed_follows.feed_id = posts.feed_id AND feed_follows.user_id = sqlc.arg(user_id)
+WHERE posts.id LIKE replace(replace(replace(CAST(sqlc.arg(prefix) AS TEXT), '\', '\\'), '%', '\%'), '_', '\_') || '%' ESCAPE '\'
+    AND (feed_follows.id IS NOT NULL OR post_stars.post_id IS NOT NULL OR read_later.post_id IS NOT NULL)
+LIMIT 2;
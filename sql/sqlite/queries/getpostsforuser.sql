@@ -0,0 +1,13 @@
+-- name: GetPostsForUser :many
+-- sqlite takes no UNIQUE alias on the followed feed ids
+SELECT posts.id, posts.created_at, posts.updated_at, posts.title, posts.url, posts.description, posts.published_at, posts.feed_id, posts.content, posts.search_vector, posts.author
+FROM posts
+INNER JOIN feeds ON posts.feed_id = feeds.id
+WHERE feeds.id IN (
+    SELECT feeds.id FROM feeds
+    INNER JOIN feed_follows ON feed_follows.feed_id = feeds.id
+    INNER JOIN users ON feed_follows.user_id = users.id
+    WHERE users.id = ?1
+)
+ORDER BY posts.published_at DESC
+LIMIT ?2;
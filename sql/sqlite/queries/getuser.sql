@@ -0,0 +1,3 @@
+-- name: GetUser :one
+SELECT * FROM users 
+WHERE name = ?1;
\ No newline at end of file
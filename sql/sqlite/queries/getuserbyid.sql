@@ -0,0 +1,3 @@
+-- name: GetUserByID :one
+SELECT * FROM users 
+WHERE id = ?1;
\ No newline at end of file
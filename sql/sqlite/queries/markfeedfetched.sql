@@ -0,0 +1,4 @@
+-- name: MarkFeedFetched :exec
+UPDATE feeds
+SET updated_at = ?2 , last_fetched_at = ?2
+WHERE id = ?1;
\ No newline at end of file
@@ -0,0 +1,20 @@
+-- name: StarPost :exec
+INSERT INTO post_stars (user_id, post_id, starred_at)
+VALUES (
+    ?1,
+    ?2,
+    ?3
+)
+ON CONFLICT (user_id, post_id) DO NOTHING;
+
+-- name: UnstarPost :execrows
+DELETE FROM post_stars
+WHERE user_id = ?1 AND post_id = ?2;
+
+-- name: GetStarredPosts :many
+SELECT sqlc.embed(posts), feeds.name AS feed_name, post_stars.starred_at
+FROM post_stars
+INNER JOIN posts ON post_stars.post_id = posts.id
+LEFT JOIN feeds ON posts.feed_id = feeds.id
+WHERE post_stars.user_id = ?1
+ORDER BY post_stars.starred_at DESC;
@@ -0,0 +1,18 @@
+-- name: AddToReadLater :execrows
+INSERT INTO read_later (user_id, post_id, position, added_at)
+SELECT sqlc.arg(user_id), sqlc.arg(post_id), COALESCE(MAX(position), 0) + 1, sqlc.arg(added_at)
+FROM read_later
+WHERE user_id = sqlc.arg(user_id)
+ON CONFLICT (user_id, post_id) DO NOTHING;
+
+-- name: GetReadLater :many
+SELECT sqlc.embed(posts), feeds.name AS feed_name, read_later.position
+FROM read_later
+INNER JOIN posts ON read_later.post_id = posts.id
+LEFT JOIN feeds ON posts.feed_id = feeds.id
+WHERE read_later.user_id = ?1
+ORDER BY read_later.position ASC;
+
+-- name: RemoveFromReadLater :execrows
+DELETE FROM read_later
+WHERE user_id = ?1 AND post_id = ?2;
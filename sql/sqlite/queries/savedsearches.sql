@@ -0,0 +1,36 @@
+-- name: CreateSavedSearch :one
+INSERT INTO saved_searches (id, created_at, updated_at, user_id, name, query)
+VALUES (
+    ?1,
+    ?2,
+    ?3,
+    ?4,
+    ?5,
+    ?6
+)
+RETURNING *;
+
+-- name: GetSavedSearchByName :one
+SELECT * FROM saved_searches
+WHERE user_id = ?1 AND name = ?2;
+
+-- name: GetSavedSearchesForUser :many
+SELECT saved_searches.*, (
+    SELECT COUNT(*)
+    FROM posts
+    INNER JOIN feed_follows ON feed_follows.feed_id = posts.feed_id AND feed_follows.user_id = saved_searches.user_id
+    LEFT JOIN post_reads ON post_reads.post_id = posts.id AND post_reads.user_id = saved_searches.user_id
+    WHERE post_reads.post_id IS NULL
+        AND posts.id IN (
+            SELECT posts_search_ids.post_id FROM posts_search
+            INNER JOIN posts_search_ids ON posts_search_ids.search_id = posts_search.rowid
+            WHERE posts_search MATCH websearch_to_fts(saved_searches.query)
+        )
+) AS unread
+FROM saved_searches
+WHERE saved_searches.user_id = ?1
+ORDER BY saved_searches.name ASC;
+
+-- name: DeleteSavedSearch :execrows
+DELETE FROM saved_searches
+WHERE user_id = ?1 AND name = ?2;
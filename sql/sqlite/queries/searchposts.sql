@@ -0,0 +1,19 @@
+-- name: SearchPosts :many
+-- bm25 ranks lower for better matches, titles weigh as much more as the
+-- 'A' weight of postgres over 'B'
+SELECT sqlc.embed(posts),
+    COALESCE(feed_follows.display_name, feeds.name, '') AS feed_name,
+    CAST(-bm25(posts_search, 2.5, 1.0) AS REAL) AS "rank",
+    snippet(posts_search, -1, '[[', ']]', ' ... ', 30) AS snippet
+FROM posts_search
+INNER JOIN posts_search_ids ON posts_search_ids.search_id = posts_search.rowid
+INNER JOIN posts ON posts.id = posts_search_ids.post_id
+LEFT JOIN feeds ON posts.feed_id = feeds.id
+LEFT JOIN feed_follows ON feed_follows.feed_id = posts.feed_id AND feed_follows.user_id = sqlc.arg(user_id)
+WHERE posts_search MATCH websearch_to_fts(CAST(sqlc.arg(query) AS TEXT))
+    AND (CAST(sqlc.arg(all_feeds) AS BOOLEAN) OR feed_follows.user_id IS NOT NULL)
+    AND (posts.feed_id = sqlc.narg(feed_id) OR sqlc.narg(feed_id) IS NULL)
+    AND (posts.published_at >= sqlc.narg(since) OR sqlc.narg(since) IS NULL)
+    AND (posts.published_at < sqlc.narg(until) OR sqlc.narg(until) IS NULL)
+ORDER BY "rank" DESC, posts.published_at DESC
+LIMIT sqlc.arg(max_posts);
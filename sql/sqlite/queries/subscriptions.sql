@@ -0,0 +1,21 @@
+-- name: GetFeedFollow :one
+SELECT * FROM feed_follows
+WHERE user_id = ?1 AND feed_id = ?2;
+
+-- name: UpdateFeedFollowSettings :execrows
+UPDATE feed_follows
+SET display_name = ?3, hidden = ?4, notify = ?5, full_text = ?6, default_sort = ?7, updated_at = ?8
+WHERE user_id = ?1 AND feed_id = ?2;
+
+-- name: FeedWantsFullText :one
+SELECT CAST(EXISTS (
+    SELECT 1 FROM feed_follows
+    WHERE feed_id = ?1 AND full_text
+) AS BOOLEAN) AS wants_full_text;
+
+-- name: GetNotifyFollowers :many
+SELECT users.name AS user_name, CAST(COALESCE(feed_follows.display_name, feeds.name) AS TEXT) AS feed_name
+FROM feed_follows
+INNER JOIN users ON feed_follows.user_id = users.id
+INNER JOIN feeds ON feed_follows.feed_id = feeds.id
+WHERE feed_follows.feed_id = ?1 AND feed_follows.notify;
@@ -0,0 +1,4 @@
+-- name: UpdatePostContent :exec
+UPDATE posts
+SET updated_at = ?2 , content = ?3
+WHERE id = ?1;
@@ -0,0 +1,19 @@
+-- name: CreateUser :one
+INSERT INTO users (id, created_at, updated_at, name, is_admin)
+VALUES (
+    ?1,
+    ?2,
+    ?3,
+    ?4,
+    ?5
+)
+RETURNING *;
+
+-- name: SetUserAdmin :execrows
+UPDATE users
+SET is_admin = ?2, updated_at = ?3
+WHERE id = ?1;
+
+-- name: DeleteUser :execrows
+DELETE FROM users
+WHERE id = ?1;
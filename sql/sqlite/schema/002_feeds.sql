@@ -0,0 +1,13 @@
+-- +goose Up
+CREATE TABLE feeds(
+    id UUID PRIMARY KEY,
+    created_at TIMESTAMP,
+    updated_at TIMESTAMP,
+    name TEXT,
+    url TEXT UNIQUE,
+    user_id UUID ,
+    FOREIGN KEY(user_id) REFERENCES users (id) ON DELETE CASCADE
+);
+
+-- +goose Down
+DROP TABLE feeds;
\ No newline at end of file
@@ -0,0 +1,17 @@
+-- +goose Up
+-- sqlite can not change a foreign key later, posts outlive their feed from
+-- the start instead of from 011_stars_read_later
+CREATE TABLE posts(
+    id UUID PRIMARY KEY,
+    created_at TIMESTAMP,
+    updated_at TIMESTAMP,
+    title TEXT,
+    url TEXT UNIQUE,
+    description TEXT,
+    published_at TIMESTAMP,
+    feed_id UUID,
+    FOREIGN KEY(feed_id) REFERENCES feeds (id) ON DELETE SET NULL
+);
+
+-- +goose Down
+DROP TABLE posts;
@@ -0,0 +1,13 @@
+-- +goose Up
+-- sqlite can not add a UNIQUE column, the index makes it unique. There is
+-- nothing to backfill, a sqlite database is new at this version.
+ALTER TABLE feeds
+ADD COLUMN url_key TEXT;
+
+CREATE UNIQUE INDEX feeds_url_key_key ON feeds (url_key);
+
+-- +goose Down
+DROP INDEX feeds_url_key_key;
+
+ALTER TABLE feeds
+DROP COLUMN url_key;
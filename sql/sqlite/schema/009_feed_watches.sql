@@ -0,0 +1,21 @@
+-- +goose Up
+CREATE TABLE feed_watches(
+    feed_id UUID PRIMARY KEY,
+    selector TEXT,
+    threshold DOUBLE PRECISION NOT NULL DEFAULT 0,
+    FOREIGN KEY(feed_id) REFERENCES feeds (id) ON DELETE CASCADE
+);
+
+CREATE TABLE page_snapshots(
+    id UUID PRIMARY KEY,
+    created_at TIMESTAMP NOT NULL,
+    feed_id UUID NOT NULL,
+    content TEXT NOT NULL,
+    FOREIGN KEY(feed_id) REFERENCES feeds (id) ON DELETE CASCADE
+);
+
+CREATE INDEX page_snapshots_feed_id_created_at_idx ON page_snapshots (feed_id, created_at DESC);
+
+-- +goose Down
+DROP TABLE page_snapshots;
+DROP TABLE feed_watches;
@@ -0,0 +1,23 @@
+-- +goose Up
+CREATE TABLE post_stars(
+    user_id UUID NOT NULL,
+    post_id UUID NOT NULL,
+    starred_at TIMESTAMP NOT NULL,
+    FOREIGN KEY(user_id) REFERENCES users (id) ON DELETE CASCADE,
+    FOREIGN KEY(post_id) REFERENCES posts (id) ON DELETE CASCADE,
+    PRIMARY KEY(user_id, post_id)
+);
+
+CREATE TABLE read_later(
+    user_id UUID NOT NULL,
+    post_id UUID NOT NULL,
+    position INTEGER NOT NULL,
+    added_at TIMESTAMP NOT NULL,
+    FOREIGN KEY(user_id) REFERENCES users (id) ON DELETE CASCADE,
+    FOREIGN KEY(post_id) REFERENCES posts (id) ON DELETE CASCADE,
+    PRIMARY KEY(user_id, post_id)
+);
+
+-- +goose Down
+DROP TABLE read_later;
+DROP TABLE post_stars;
@@ -0,0 +1,35 @@
+-- +goose Up
+CREATE TABLE folders(
+    id UUID PRIMARY KEY,
+    created_at TIMESTAMP,
+    updated_at TIMESTAMP,
+    user_id UUID NOT NULL,
+    name TEXT NOT NULL,
+    FOREIGN KEY(user_id) REFERENCES users (id) ON DELETE CASCADE,
+    UNIQUE(user_id, name)
+);
+
+ALTER TABLE feed_follows
+ADD COLUMN folder_id UUID REFERENCES folders (id) ON DELETE SET NULL;
+
+-- +goose Down
+-- sqlite can not drop a column with a foreign key, the table is copied without it
+CREATE TABLE feed_follows_old(
+    id UUID PRIMARY KEY,
+    created_at TIMESTAMP,
+    updated_at TIMESTAMP,
+    user_id UUID ,
+    feed_id UUID ,
+    FOREIGN KEY(user_id) REFERENCES users (id) ON DELETE CASCADE,
+    FOREIGN KEY(feed_id) REFERENCES feeds (id) ON DELETE CASCADE,
+    UNIQUE(user_id,feed_id)
+);
+
+INSERT INTO feed_follows_old (id, created_at, updated_at, user_id, feed_id)
+SELECT id, created_at, updated_at, user_id, feed_id FROM feed_follows;
+
+DROP TABLE feed_follows;
+
+ALTER TABLE feed_follows_old RENAME TO feed_follows;
+
+DROP TABLE folders;
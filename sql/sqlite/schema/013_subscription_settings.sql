@@ -0,0 +1,46 @@
+-- +goose Up
+ALTER TABLE feed_follows
+ADD COLUMN display_name TEXT;
+
+ALTER TABLE feed_follows
+ADD COLUMN hidden BOOLEAN NOT NULL DEFAULT false;
+
+ALTER TABLE feed_follows
+ADD COLUMN notify BOOLEAN NOT NULL DEFAULT false;
+
+ALTER TABLE feed_follows
+ADD COLUMN full_text BOOLEAN NOT NULL DEFAULT false;
+
+ALTER TABLE users
+ADD COLUMN default_sort TEXT NOT NULL DEFAULT 'newest';
+
+-- full content moves from the feed to the subscriptions of its followers
+UPDATE feed_follows
+SET full_text = 1
+WHERE feed_id IN (SELECT id FROM feeds WHERE fetch_full_content);
+
+ALTER TABLE feeds
+DROP COLUMN fetch_full_content;
+
+-- +goose Down
+ALTER TABLE feeds
+ADD COLUMN fetch_full_content BOOLEAN NOT NULL DEFAULT false;
+
+UPDATE feeds
+SET fetch_full_content = true
+WHERE EXISTS (SELECT 1 FROM feed_follows WHERE feed_follows.feed_id = feeds.id AND feed_follows.full_text);
+
+ALTER TABLE users
+DROP COLUMN default_sort;
+
+ALTER TABLE feed_follows
+DROP COLUMN full_text;
+
+ALTER TABLE feed_follows
+DROP COLUMN notify;
+
+ALTER TABLE feed_follows
+DROP COLUMN hidden;
+
+ALTER TABLE feed_follows
+DROP COLUMN display_name;
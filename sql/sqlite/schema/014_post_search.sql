@@ -0,0 +1,63 @@
+-- +goose Up
+-- sqlite has no tsvector. posts_search is the full text index of the posts,
+-- search_vector stays null and is only there so posts has the columns of the
+-- postgres schema.
+ALTER TABLE posts
+ADD COLUMN search_vector TEXT GENERATED ALWAYS AS (NULL) VIRTUAL;
+
+-- posts_search_ids gives every post a lasting integer id for the rows of
+-- posts_search, the rowids of posts can change on VACUUM
+CREATE TABLE posts_search_ids(
+    search_id INTEGER PRIMARY KEY,
+    post_id UUID NOT NULL UNIQUE,
+    FOREIGN KEY(post_id) REFERENCES posts (id) ON DELETE CASCADE
+);
+
+-- title matches rank above matches in the description or content
+CREATE VIRTUAL TABLE posts_search USING fts5(title, body, tokenize = 'porter unicode61');
+
+INSERT INTO posts_search_ids (post_id)
+SELECT id FROM posts;
+
+INSERT INTO posts_search (rowid, title, body)
+SELECT posts_search_ids.search_id, coalesce(posts.title, ''), coalesce(posts.description, '') || ' ' || coalesce(posts.content, '')
+FROM posts_search_ids
+INNER JOIN posts ON posts.id = posts_search_ids.post_id;
+
+-- +goose StatementBegin
+CREATE TRIGGER posts_search_insert AFTER INSERT ON posts
+BEGIN
+    INSERT INTO posts_search_ids (post_id) VALUES (new.id);
+    INSERT INTO posts_search (rowid, title, body)
+    SELECT search_id, coalesce(new.title, ''), coalesce(new.description, '') || ' ' || coalesce(new.content, '')
+    FROM posts_search_ids WHERE post_id = new.id;
+END;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TRIGGER posts_search_update AFTER UPDATE OF title, description, content ON posts
+BEGIN
+    UPDATE posts_search
+    SET title = coalesce(new.title, ''), body = coalesce(new.description, '') || ' ' || coalesce(new.content, '')
+    WHERE rowid = (SELECT search_id FROM posts_search_ids WHERE post_id = new.id);
+END;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TRIGGER posts_search_delete BEFORE DELETE ON posts
+BEGIN
+    DELETE FROM posts_search
+    WHERE rowid = (SELECT search_id FROM posts_search_ids WHERE post_id = old.id);
+    DELETE FROM posts_search_ids WHERE post_id = old.id;
+END;
+-- +goose StatementEnd
+
+-- +goose Down
+DROP TRIGGER posts_search_delete;
+DROP TRIGGER posts_search_update;
+DROP TRIGGER posts_search_insert;
+DROP TABLE posts_search;
+DROP TABLE posts_search_ids;
+
+ALTER TABLE posts
+DROP COLUMN search_vector;
@@ -0,0 +1,73 @@
+-- +goose NO TRANSACTION
+-- +goose Up
+-- sqlite can not change a foreign key, feeds is rebuilt with the foreign
+-- keys off so dropping the old table does not cascade to the follows
+PRAGMA foreign_keys = OFF;
+BEGIN;
+
+CREATE TABLE feeds_new(
+    id UUID PRIMARY KEY,
+    created_at TIMESTAMP,
+    updated_at TIMESTAMP,
+    name TEXT,
+    url TEXT UNIQUE,
+    user_id UUID,
+    last_fetched_at TIMESTAMP,
+    url_key TEXT,
+    kind TEXT NOT NULL DEFAULT 'rss',
+    site_url TEXT,
+    FOREIGN KEY(user_id) REFERENCES users (id) ON DELETE SET NULL
+);
+
+INSERT INTO feeds_new (id, created_at, updated_at, name, url, user_id, last_fetched_at, url_key, kind, site_url)
+SELECT id, created_at, updated_at, name, url, user_id, last_fetched_at, url_key, kind, site_url FROM feeds;
+
+DROP TABLE feeds;
+
+ALTER TABLE feeds_new RENAME TO feeds;
+
+CREATE UNIQUE INDEX feeds_url_key_key ON feeds (url_key);
+
+ALTER TABLE users
+ADD COLUMN is_admin BOOLEAN NOT NULL DEFAULT false;
+
+-- the first user set gator up
+UPDATE users
+SET is_admin = true
+WHERE id = (SELECT id FROM users ORDER BY created_at ASC NULLS LAST LIMIT 1);
+
+COMMIT;
+PRAGMA foreign_keys = ON;
+
+-- +goose Down
+PRAGMA foreign_keys = OFF;
+BEGIN;
+
+ALTER TABLE users
+DROP COLUMN is_admin;
+
+CREATE TABLE feeds_new(
+    id UUID PRIMARY KEY,
+    created_at TIMESTAMP,
+    updated_at TIMESTAMP,
+    name TEXT,
+    url TEXT UNIQUE,
+    user_id UUID,
+    last_fetched_at TIMESTAMP,
+    url_key TEXT,
+    kind TEXT NOT NULL DEFAULT 'rss',
+    site_url TEXT,
+    FOREIGN KEY(user_id) REFERENCES users (id) ON DELETE CASCADE
+);
+
+INSERT INTO feeds_new (id, created_at, updated_at, name, url, user_id, last_fetched_at, url_key, kind, site_url)
+SELECT id, created_at, updated_at, name, url, user_id, last_fetched_at, url_key, kind, site_url FROM feeds;
+
+DROP TABLE feeds;
+
+ALTER TABLE feeds_new RENAME TO feeds;
+
+CREATE UNIQUE INDEX feeds_url_key_key ON feeds (url_key);
+
+COMMIT;
+PRAGMA foreign_keys = ON;
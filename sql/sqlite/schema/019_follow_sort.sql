@@ -0,0 +1,23 @@
+-- +goose Up
+-- the default sort is a setting of every subscription, like its display name
+ALTER TABLE feed_follows
+ADD COLUMN default_sort TEXT NOT NULL DEFAULT 'newest';
+
+UPDATE feed_follows
+SET default_sort = (SELECT users.default_sort FROM users WHERE users.id = feed_follows.user_id);
+
+ALTER TABLE users
+DROP COLUMN default_sort;
+
+-- +goose Down
+ALTER TABLE users
+ADD COLUMN default_sort TEXT NOT NULL DEFAULT 'newest';
+
+-- users keep oldest first only when every subscription has it
+UPDATE users
+SET default_sort = 'oldest'
+WHERE EXISTS (SELECT 1 FROM feed_follows WHERE feed_follows.user_id = users.id)
+    AND NOT EXISTS (SELECT 1 FROM feed_follows WHERE feed_follows.user_id = users.id AND feed_follows.default_sort <> 'oldest');
+
+ALTER TABLE feed_follows
+DROP COLUMN default_sort;
@@ -0,0 +1,11 @@
+// Package schema embeds the sqlite migrations. They mirror the postgres
+// migrations of sql/schema version for version, so both kinds of database
+// report the same schema version.
+package schema
+
+import "embed"
+
+// FS holds the migrations, NNN_name.sql files with goose Up and Down sections.
+//
+//go:embed *.sql
+var FS embed.FS